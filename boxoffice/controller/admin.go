@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ydsxiong/go-playground/boxoffice/model"
+)
+
+/*
+ * the admin endpoints to look after the events, all of them speaking json
+ */
+func ListEvents(w http.ResponseWriter, r *http.Request) {
+	allEvents, err := eventService.GetAllEvents()
+	if err != nil {
+		sendJSONError(w, "problem listing the events", http.StatusInternalServerError)
+		return
+	}
+	sendJSON(w, allEvents, http.StatusOK)
+}
+
+/*
+ * create an event from its json, e.g.
+ *   {"name": "Hamlet", "capacity": 100, "price": 2500, "starts_at": "2026-12-01T19:30:00Z"}
+ * its sales opening from now until it starts, unless its sales_open_at and sales_close_at say otherwise
+ */
+func CreateEvent(w http.ResponseWriter, r *http.Request) {
+	event := &model.Event{}
+	if err := json.NewDecoder(r.Body).Decode(event); err != nil {
+		sendJSONError(w, "problem reading the event, "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	// the ids and the closing of events are the box office's to give, not the caller's
+	event.ID = 0
+	event.ClosedAt = nil
+	if err := event.Validate(time.Now()); err != nil {
+		sendJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := eventService.CreateEvent(event); err != nil {
+		sendJSONError(w, "problem creating the event, "+err.Error(), http.StatusConflict)
+		return
+	}
+	sendJSON(w, event, http.StatusCreated)
+}
+
+/*
+ * close an event, by its "event" id, so no more tickets can be reserved or paid for
+ */
+func CloseEvent(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.FormValue("event"), 10, 32)
+	if err != nil {
+		sendJSONError(w, "there is no such event", http.StatusNotFound)
+		return
+	}
+	event, err := eventService.CloseEvent(uint(id))
+	if err != nil {
+		sendJSONError(w, "problem closing the event", http.StatusInternalServerError)
+		return
+	}
+	if event == nil {
+		sendJSONError(w, "there is no such event", http.StatusNotFound)
+		return
+	}
+	sendJSON(w, event, http.StatusOK)
+}
+
+func sendJSON(w http.ResponseWriter, v interface{}, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func sendJSONError(w http.ResponseWriter, errMsg string, code int) {
+	sendJSON(w, map[string]string{"error": errMsg}, code)
+}
@@ -0,0 +1,99 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ydsxiong/go-playground/boxoffice/controller"
+	"github.com/ydsxiong/go-playground/boxoffice/model"
+)
+
+const adminKey = "let-me-in"
+
+func TestAdminEvents(t *testing.T) {
+	initController(t)
+	adminAuth := controller.CreateAdminAuthMiddleWare(adminKey)
+
+	call := func(handler http.HandlerFunc, method, path, body, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set("X-Admin-Key", key)
+		}
+		res := httptest.NewRecorder()
+		adminAuth(handler)(res, req)
+		return res
+	}
+	assertStatus := func(t *testing.T, res *httptest.ResponseRecorder, want int) {
+		t.Helper()
+		if res.Code != want {
+			t.Fatalf("got status %d, want %d, %s", res.Code, want, res.Body.String())
+		}
+	}
+
+	t.Run("lets in only the admin", func(t *testing.T) {
+		for _, key := range []string{"", "let-me-in-please"} {
+			assertStatus(t, call(controller.ListEvents, "GET", "/admin/events", "", key), http.StatusUnauthorized)
+		}
+		res := httptest.NewRecorder()
+		controller.CreateAdminAuthMiddleWare("")(controller.ListEvents)(res, httptest.NewRequest("GET", "/admin/events", nil))
+		assertStatus(t, res, http.StatusUnauthorized)
+	})
+
+	t.Run("creates an event", func(t *testing.T) {
+		res := call(controller.CreateEvent, "POST", "/admin/events",
+			`{"name": "Hamlet", "capacity": 100, "price": 2500, "starts_at": "2100-06-01T19:30:00Z"}`, adminKey)
+		assertStatus(t, res, http.StatusCreated)
+		var created model.Event
+		json.NewDecoder(res.Body).Decode(&created)
+		if created.ID == 0 || created.Name != "Hamlet" || created.SalesCloseAt != created.StartsAt {
+			t.Errorf("expected Hamlet on sale until it starts, got %+v", created)
+		}
+
+		res = call(controller.ListEvents, "GET", "/admin/events", "", adminKey)
+		assertStatus(t, res, http.StatusOK)
+		var listed []model.Event
+		json.NewDecoder(res.Body).Decode(&listed)
+		if len(listed) != 2 || listed[1].Name != "Hamlet" {
+			t.Errorf("expected Hamlet listed after the best ever show, got %+v", listed)
+		}
+	})
+
+	t.Run("creates a free event", func(t *testing.T) {
+		res := call(controller.CreateEvent, "POST", "/admin/events",
+			`{"name": "Open Rehearsal", "capacity": 20, "price": 0, "starts_at": "2100-05-01T19:30:00Z"}`, adminKey)
+		assertStatus(t, res, http.StatusCreated)
+	})
+
+	t.Run("refuses events that make no sense", func(t *testing.T) {
+		for body, want := range map[string]int{
+			`{"name": "Macbeth", "capacity": 0, "starts_at": "2100-06-01T19:30:00Z"}`:                                            http.StatusBadRequest,
+			`{"name": "Macbeth", "capacity": 10, "starts_at": "2100-06-01T19:30:00Z", "sales_close_at": "2100-07-01T00:00:00Z"}`: http.StatusBadRequest,
+			`{"name": "Macbeth"`: http.StatusBadRequest,
+			`{"name": "Macbeth", "capacity": 10, "price": 10, "starts_at": "2100-06-01T19:30:00Z"}`: http.StatusBadRequest,
+			`{"name": "Hamlet", "capacity": 10, "starts_at": "2100-07-01T19:30:00Z"}`:               http.StatusConflict,
+		} {
+			assertStatus(t, call(controller.CreateEvent, "POST", "/admin/events", body, adminKey), want)
+		}
+	})
+
+	t.Run("closes an event", func(t *testing.T) {
+		res := call(controller.CloseEvent, "POST", "/admin/events/close?event=1", "", adminKey)
+		assertStatus(t, res, http.StatusOK)
+		var closed model.Event
+		json.NewDecoder(res.Body).Decode(&closed)
+		if closed.ClosedAt == nil {
+			t.Errorf("expected the event to be closed, got %+v", closed)
+		}
+
+		res = httptest.NewRecorder()
+		controller.DispatchEventPage(res, httptest.NewRequest("GET", "/event?event=1", nil))
+		if !strings.Contains(res.Body.String(), "Tickets are "+model.EventClosed) {
+			t.Errorf("expected the event page to say it is closed, got %s", res.Body.String())
+		}
+
+		assertStatus(t, call(controller.CloseEvent, "POST", "/admin/events/close?event=42", "", adminKey), http.StatusNotFound)
+	})
+}
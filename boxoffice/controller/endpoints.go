@@ -3,11 +3,13 @@ package controller
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 	"text/template"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/ydsxiong/go-playground/boxoffice/model"
+	"github.com/ydsxiong/go-playground/boxoffice/services/events"
 	"github.com/ydsxiong/go-playground/boxoffice/services/inprogress"
 	"github.com/ydsxiong/go-playground/boxoffice/services/registeredguest"
 
@@ -16,21 +18,21 @@ import (
 	"github.com/stripe/stripe-go/sub"
 )
 
-var totalTicketsAvailable int
 var reservationTime time.Duration
 var guestService registeredguest.RegisteredGuestService
+var eventService events.EventService
 var inProgressService inprogress.InProgressGuestService
 var page *template.Template
 
 func Init(max_reservation_time time.Duration,
-	total_tickets_available int,
 	guestSvc registeredguest.RegisteredGuestService,
+	eventSvc events.EventService,
 	inProgressSvc inprogress.InProgressGuestService,
 	pageViews *template.Template) {
 
-	totalTicketsAvailable = total_tickets_available
 	reservationTime = max_reservation_time
 	guestService = guestSvc
+	eventService = eventSvc
 	inProgressService = inProgressSvc
 	page = pageViews
 }
@@ -39,6 +41,43 @@ func SwapGuestServiceWith(newService registeredguest.RegisteredGuestService) {
 	guestService = newService
 }
 
+/*
+ * an event as it's listed on the pages, with its tickets left and how its sales are going
+ */
+type eventListing struct {
+	*model.Event
+	Starts        string
+	PriceTag      string
+	Status        string
+	OnSale        bool
+	Remaining     int
+	InReservation int
+}
+
+func listEvent(event *model.Event) (*eventListing, []*model.Guest, error) {
+	available, reserved, registeredGuests, err := findNumberOfTicketsAvailable(event, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	return &eventListing{
+		Event:         event,
+		Starts:        event.StartsAt.Format("Mon 2 Jan 2006 15:04"),
+		PriceTag:      formatPrice(event.Price),
+		Status:        event.SalesStatus(now),
+		OnSale:        event.OnSale(now),
+		Remaining:     available,
+		InReservation: reserved,
+	}, registeredGuests, nil
+}
+
+func formatPrice(pence int64) string {
+	if pence == 0 {
+		return "free"
+	}
+	return fmt.Sprintf("£%d.%02d", pence/100, pence%100)
+}
+
 func DispatchHomePage(w http.ResponseWriter, r *http.Request) {
 	data := make(map[string]interface{})
 
@@ -52,22 +91,74 @@ func DispatchHomePage(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	available, reserved, registeredGuests, err := findNumberOfTicketsAvailable(true)
+	allEvents, err := eventService.GetAllEvents()
 	if err != nil {
 		handleInternalError(w, "")
 		return
 	}
+	listings := []*eventListing{}
+	for _, event := range allEvents {
+		listing, _, err := listEvent(event)
+		if err != nil {
+			handleInternalError(w, "")
+			return
+		}
+		listings = append(listings, listing)
+	}
+	data["events"] = listings
 
+	page.ExecuteTemplate(w, "Events", data)
+}
+
+func DispatchEventPage(w http.ResponseWriter, r *http.Request) {
+	event, ok := requestedEvent(w, r)
+	if !ok {
+		return
+	}
+
+	listing, registeredGuests, err := listEvent(event)
+	if err != nil {
+		handleInternalError(w, "")
+		return
+	}
+
+	data := make(map[string]interface{})
+	data["event"] = listing
 	data["guests"] = registeredGuests
-	data["remaining"] = available
-	data["inreservation"] = reserved
+	data["remaining"] = listing.Remaining
+	data["inreservation"] = listing.InReservation
 
 	page.ExecuteTemplate(w, "Guests", data)
 }
 
-func findNumberOfTicketsAvailable(checkInprogress bool) (int, int, []*model.Guest, error) {
+/*
+ * look up the event a request is for, by its "event" id, sending back a page saying there's no such event if it can't be found
+ */
+func requestedEvent(w http.ResponseWriter, r *http.Request) (*model.Event, bool) {
+	id, err := strconv.ParseUint(r.FormValue("event"), 10, 32)
+	if err != nil {
+		handleNotFound(w, "There is no such event")
+		return nil, false
+	}
+	return findEvent(w, uint(id))
+}
+
+func findEvent(w http.ResponseWriter, id uint) (*model.Event, bool) {
+	event, err := eventService.GetEvent(id)
+	if err != nil {
+		handleInternalError(w, "")
+		return nil, false
+	}
+	if event == nil {
+		handleNotFound(w, "There is no such event")
+		return nil, false
+	}
+	return event, true
+}
+
+func findNumberOfTicketsAvailable(event *model.Event, checkInprogress bool) (int, int, []*model.Guest, error) {
 
-	allGuests, err := guestService.GetAllGuests()
+	allGuests, err := guestService.GetAllGuests(event.ID)
 
 	if err != nil {
 		return 0, 0, nil, err
@@ -77,7 +168,7 @@ func findNumberOfTicketsAvailable(checkInprogress bool) (int, int, []*model.Gues
 		return v.ExpiredAt == nil
 	})
 
-	available := totalTicketsAvailable - len(registeredGuests)
+	available := event.Capacity - len(registeredGuests)
 
 	reserved := len(allGuests) - len(registeredGuests)
 
@@ -88,19 +179,24 @@ func findNumberOfTicketsAvailable(checkInprogress bool) (int, int, []*model.Gues
 }
 
 func DispatchReservationForm(w http.ResponseWriter, r *http.Request) {
-	sendNewReservationForm(w, nil)
+	event, ok := requestedEvent(w, r)
+	if !ok {
+		return
+	}
+	sendNewReservationForm(w, event, nil)
 }
 
-func sendNewReservationForm(w http.ResponseWriter, msg *string) {
-	available, reserved, _, err := findNumberOfTicketsAvailable(true)
+func sendNewReservationForm(w http.ResponseWriter, event *model.Event, msg *string) {
+	listing, _, err := listEvent(event)
 	if err != nil {
 		handleInternalError(w, "")
 		return
 	}
 
 	data := make(map[string]interface{})
-	data["remaining"] = available
-	data["reserved"] = reserved
+	data["event"] = listing
+	data["remaining"] = listing.Remaining
+	data["reserved"] = listing.InReservation
 	if msg != nil {
 		data["msg"] = msg
 	}
@@ -110,8 +206,13 @@ func sendNewReservationForm(w http.ResponseWriter, msg *string) {
 
 func MakeReservation(w http.ResponseWriter, r *http.Request) {
 
+	event, ok := requestedEvent(w, r)
+	if !ok {
+		return
+	}
+
 	handleMsg := func(msg string, code int) {
-		sendNewReservationForm(w, &msg)
+		sendNewReservationForm(w, event, &msg)
 	}
 
 	name := r.FormValue("guestname")
@@ -122,14 +223,19 @@ func MakeReservation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	existingGuest, err := guestService.GetGuestByName(name)
+	if status := event.SalesStatus(time.Now()); status != model.EventOnSale {
+		handleMsg("Sorry, tickets for "+event.Name+" are "+status+"!", http.StatusNotAcceptable)
+		return
+	}
+
+	existingGuest, err := guestService.GetGuestByName(event.ID, name)
 	if err != nil {
 		handleInternalError(w, "")
 		return
 	}
 
 	if existingGuest != nil && existingGuest.ExpiredAt == nil {
-		handleMsg(name+" is an already registered guest, one guest can only reserve one ticket!", http.StatusNotAcceptable)
+		handleMsg(name+" is an already registered guest, one guest can only reserve one ticket for each event!", http.StatusNotAcceptable)
 		return
 	}
 
@@ -138,17 +244,17 @@ func MakeReservation(w http.ResponseWriter, r *http.Request) {
 	if tokenCookie != nil {
 		claims := validateToken(tokenCookie)
 		if claims != nil {
-			// check first the authenticated guest to see if anything in progress
+			// check first the authenticated guest to see if anything in progress, for whichever event they were reserving
 			guest := existingGuest
-			if guest == nil {
-				guest = &model.Guest{Name: claims.GuestName}
+			if guest == nil || claims.GuestName != name || claims.EventID != event.ID {
+				guest = &model.Guest{Name: claims.GuestName, EventID: claims.EventID}
 			}
 			running, remainingTime, _ := guestService.IsGuestInProcess(guest)
 			if running {
 				dispatchReservationStillInProgress(w, claims.GuestName, name, remainingTime)
 				return
-			} else if claims.GuestName != name { // check for this incoming other guest to see if anything in progress
-				running, remainingTime, _ = guestService.IsGuestInProcess(&model.Guest{Name: name})
+			} else if claims.GuestName != name || claims.EventID != event.ID { // check for this incoming other guest to see if anything in progress
+				running, remainingTime, _ = guestService.IsGuestInProcess(&model.Guest{Name: name, EventID: event.ID})
 				if running {
 					dispatchReservationStillInProgress(w, name, name, remainingTime)
 					return
@@ -165,13 +271,13 @@ func MakeReservation(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	if !checkAvailability(w, r, true) {
+	if !checkAvailability(w, r, event, true) {
 		return
 	}
 	// finally: proceed with accepting this guest to the reversation in-progress list
 	guestToReserve := existingGuest
 	if guestToReserve == nil {
-		guestToReserve = &model.Guest{Name: name}
+		guestToReserve = &model.Guest{Name: name, EventID: event.ID}
 	}
 	err = guestService.AddGuestInProgress(guestToReserve, reservationTime)
 	if err != nil {
@@ -181,18 +287,22 @@ func MakeReservation(w http.ResponseWriter, r *http.Request) {
 
 	tknstr, expiry := generateToken(&Claims{
 		GuestName:      name,
+		EventID:        event.ID,
 		StandardClaims: jwt.StandardClaims{},
 	})
 	pushTokenIntoClientCookie(w, tknstr, expiry)
 
-	dispatchNewReservationConfirmation(w, name)
+	dispatchNewReservationConfirmation(w, event, name)
 }
 
-func dispatchNewReservationConfirmation(w http.ResponseWriter, name string) {
+func dispatchNewReservationConfirmation(w http.ResponseWriter, event *model.Event, name string) {
 	data := make(map[string]interface{})
 	data["name"] = name
+	data["event"] = event
+	data["amount"] = event.Price
+	data["free"] = event.Price == 0
 	data["greetings"] = "Nice to you meet you " + name + "!"
-	data["remaining"] = fmt.Sprintf("We reserved your ticket for %d minutes", int(reservationTime.Minutes()))
+	data["remaining"] = fmt.Sprintf("We reserved your ticket for %s for %d minutes", event.Name, int(reservationTime.Minutes()))
 	page.ExecuteTemplate(w, "Reservation", data)
 }
 
@@ -207,8 +317,8 @@ func dispatchReservationStillInProgress(w http.ResponseWriter, name, other strin
 	page.ExecuteTemplate(w, "Reservation", data)
 }
 
-func checkAvailability(w http.ResponseWriter, req *http.Request, checkInprogress bool) bool {
-	remaingTickets, reservedTickets, _, err := findNumberOfTicketsAvailable(checkInprogress)
+func checkAvailability(w http.ResponseWriter, req *http.Request, event *model.Event, checkInprogress bool) bool {
+	remaingTickets, reservedTickets, _, err := findNumberOfTicketsAvailable(event, checkInprogress)
 	if err != nil {
 		handleInternalError(w, "")
 		return false
@@ -246,28 +356,42 @@ func PayWithCard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !checkAvailability(w, r, false) {
+	event, ok := findEvent(w, claims.EventID)
+	if !ok {
 		return
 	}
-
-	// proceed with checking out the reservation for guest
-	// Token is created using Checkout or Elements! Get the payment token ID submitted by the form:
-	var payment paymentResult
-	chanPayment := make(chan paymentResult)
-	go processPayment(r.FormValue("stripeToken"), chanPayment)
-	select {
-	case payment = <-chanPayment:
-	case <-time.After(5 * time.Second):
-		handleInternalError(w, "Service was temporarily unavailable, please go back to try again later")
+	if event.ClosedAt != nil {
+		handleInternalError(w, event.Name+" has been closed, your reservation can no longer be paid for")
 		return
 	}
-	if payment.err != nil {
-		handleInternalError(w, payment.err.Error()+";  please go back to try again")
+	if !checkAvailability(w, r, event, false) {
 		return
 	}
 
+	// proceed with checking out the reservation for guest, there's nothing to pay for a free event
+	// Token is created using Checkout or Elements! Get the payment token ID submitted by the form:
+	var payment paymentResult
+	if event.Price > 0 {
+		chanPayment := make(chan paymentResult)
+		go processPayment(r.FormValue("stripeToken"), event, chanPayment)
+		select {
+		case payment = <-chanPayment:
+		case <-time.After(5 * time.Second):
+			handleInternalError(w, "Service was temporarily unavailable, please go back to try again later")
+			return
+		}
+		if payment.err != nil {
+			handleInternalError(w, payment.err.Error()+";  please go back to try again")
+			return
+		}
+	}
+
 	// payment now done, so save the confirmed guest into the db
-	if err := guestService.SaveRegisteredGuest(claims.GuestName); err != nil {
+	if err := guestService.SaveRegisteredGuest(event.ID, claims.GuestName); err != nil {
+		if payment.charge == nil {
+			handleInternalError(w, "")
+			return
+		}
 		// in the event of db saving failure, need to canx the charge
 		chanCancel := make(chan error)
 		go cancelPayment(payment.charge.ID, chanCancel)
@@ -290,6 +414,7 @@ func PayWithCard(w http.ResponseWriter, r *http.Request) {
 	// finally dispatch the successful registeration confirmation to the guest
 	data := make(map[string]interface{})
 	data["name"] = claims.GuestName
+	data["event"] = event
 	page.ExecuteTemplate(w, "Success", data)
 }
 
@@ -308,18 +433,23 @@ func handleInternalError(w http.ResponseWriter, errMsg string) {
 	page.ExecuteTemplate(w, "ErrorPage", data)
 }
 
+func handleNotFound(w http.ResponseWriter, errMsg string) {
+	w.WriteHeader(http.StatusNotFound)
+	handleInternalError(w, errMsg)
+}
+
 type paymentResult struct {
 	charge *stripe.Charge
 	err    error
 }
 
-func processPayment(token string, result chan paymentResult) {
+func processPayment(token string, event *model.Event, result chan paymentResult) {
 	stripe.Key = "sk_test_NJkFUrt4czgQdKvyHIMW3O9I007l9IMGx9"
 
 	params := &stripe.ChargeParams{
-		Amount:      stripe.Int64(999),
+		Amount:      stripe.Int64(event.Price),
 		Currency:    stripe.String(string(stripe.CurrencyGBP)),
-		Description: stripe.String("Test charge"),
+		Description: stripe.String("Ticket for " + event.Name),
 	}
 	params.SetSource(token)
 	charge, err := charge.New(params)
@@ -4,25 +4,49 @@ import (
 	"bytes"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"text/template"
 	"time"
 
 	"github.com/ydsxiong/go-playground/boxoffice/controller"
 	"github.com/ydsxiong/go-playground/boxoffice/model"
+	"github.com/ydsxiong/go-playground/boxoffice/services/events"
 	"github.com/ydsxiong/go-playground/boxoffice/services/inprogress"
 	"github.com/ydsxiong/go-playground/boxoffice/test"
 )
 
-func init() {
+/*
+ * start the controller afresh, with the one show on sale as event 1, and whatever other events given after it
+ */
+func initController(t *testing.T, otherEvents ...*model.Event) {
+	eventService := events.NewInMemoryService()
+	show := &model.Event{
+		Name:         "The Best Ever Show",
+		Capacity:     5,
+		Price:        999,
+		StartsAt:     time.Date(2100, time.January, 1, 19, 30, 0, 0, time.UTC),
+		SalesOpenAt:  time.Date(2000, time.January, 1, 9, 0, 0, 0, time.UTC),
+		SalesCloseAt: time.Date(2100, time.January, 1, 19, 0, 0, 0, time.UTC),
+	}
+	for _, event := range append([]*model.Event{show}, otherEvents...) {
+		if err := eventService.CreateEvent(event); err != nil {
+			t.Fatalf("problem setting up the event %s, %v", event.Name, err)
+		}
+	}
+
 	controller.Init(
-		5*time.Minute, 5,
+		5*time.Minute,
 		&test.MockDBService{},
+		eventService,
 		inprogress.NewInMemoryService(),
 		template.Must(template.ParseGlob("../views/*")))
 }
 
 func TestDispatchingPages(t *testing.T) {
+	initController(t)
+
 	scenarios := []struct {
 		name         string
 		funcHandler  http.HandlerFunc
@@ -33,8 +57,9 @@ func TestDispatchingPages(t *testing.T) {
 	}{
 		{"homepage", controller.DispatchHomePage, "/", "GET", nil, nil},
 		{"homepageSessionTimeout", controller.DispatchHomePage, "/", "GET", &http.Cookie{Name: "sessionexpired", Value: "true"}, nil},
-		{"reservationform", controller.DispatchReservationForm, "/", "reservation", nil, nil},
-		{"reservationwith3ticketsleft", controller.DispatchReservationForm, "/", "reservation", nil, []*model.Guest{{Name: "test1"}, {Name: "test2"}}},
+		{"eventpage", controller.DispatchEventPage, "/event?event=1", "GET", nil, nil},
+		{"reservationform", controller.DispatchReservationForm, "/reservation?event=1", "GET", nil, nil},
+		{"reservationwith3ticketsleft", controller.DispatchReservationForm, "/reservation?event=1", "GET", nil, []*model.Guest{{Name: "test1", EventID: 1}, {Name: "test2", EventID: 1}}},
 	}
 
 	var testDataPath = "../test/data"
@@ -51,7 +76,7 @@ func TestDispatchingPages(t *testing.T) {
 				req.AddCookie(scenario.cookie)
 			}
 			if scenario.defaultGuest != nil { // adjust test case data to alter controller behaviour outcome accordingly
-				controller.SwapGuestServiceWith(&test.MockDBService{scenario.defaultGuest})
+				controller.SwapGuestServiceWith(&test.MockDBService{DefaultGuests: scenario.defaultGuest})
 			}
 			res := httptest.NewRecorder()
 			handler := http.HandlerFunc(scenario.funcHandler)
@@ -71,3 +96,91 @@ func TestDispatchingPages(t *testing.T) {
 		})
 	}
 }
+
+func TestMakingReservations(t *testing.T) {
+	now := time.Now()
+	initController(t,
+		&model.Event{
+			Name:         "Next Year's Show",
+			Capacity:     5,
+			StartsAt:     now.AddDate(1, 0, 0),
+			SalesOpenAt:  now.AddDate(0, 6, 0),
+			SalesCloseAt: now.AddDate(1, 0, 0),
+		},
+		&model.Event{
+			Name:         "Last Night's Show",
+			Capacity:     5,
+			StartsAt:     now.Add(-2 * time.Hour),
+			SalesOpenAt:  now.AddDate(0, -1, 0),
+			SalesCloseAt: now.Add(-3 * time.Hour),
+		})
+
+	t.Run("reserves a ticket for an event on sale", func(t *testing.T) {
+		res := reserve("1", "alice")
+		if !strings.Contains(res.Body.String(), "We reserved your ticket for The Best Ever Show") {
+			t.Errorf("expected the ticket to be reserved, got %s", res.Body.String())
+		}
+		if !strings.Contains(res.Header().Get("Set-Cookie"), "token=") {
+			t.Errorf("expected a token for the reservation, got cookies %q", res.Header().Get("Set-Cookie"))
+		}
+	})
+
+	t.Run("refuses events not on sale", func(t *testing.T) {
+		for event, status := range map[string]string{"2": model.EventNotOnSaleYet, "3": model.EventSalesClosed} {
+			res := reserve(event, "bob")
+			if !strings.Contains(res.Body.String(), "are "+status) {
+				t.Errorf("event %s: expected tickets to be %s, got %s", event, status, res.Body.String())
+			}
+			if res.Header().Get("Set-Cookie") != "" {
+				t.Errorf("event %s: expected no reservation, got cookies %q", event, res.Header().Get("Set-Cookie"))
+			}
+		}
+	})
+
+	t.Run("refuses events there are none of", func(t *testing.T) {
+		for _, event := range []string{"42", "", "first"} {
+			if res := reserve(event, "bob"); res.Code != http.StatusNotFound {
+				t.Errorf("event %q: got status %d, want %d", event, res.Code, http.StatusNotFound)
+			}
+		}
+	})
+}
+
+func TestBookingAFreeEvent(t *testing.T) {
+	initController(t, &model.Event{
+		Name:         "The Free Show",
+		Capacity:     5,
+		StartsAt:     time.Date(2100, time.February, 1, 19, 30, 0, 0, time.UTC),
+		SalesOpenAt:  time.Date(2000, time.January, 1, 9, 0, 0, 0, time.UTC),
+		SalesCloseAt: time.Date(2100, time.February, 1, 19, 0, 0, 0, time.UTC),
+	})
+
+	res := reserve("2", "alice")
+	if body := res.Body.String(); !strings.Contains(body, "Confirm my ticket") || strings.Contains(body, "checkout.stripe.com") {
+		t.Fatalf("expected the ticket to be confirmed without a card, got %s", body)
+	}
+
+	// alice's ticket is held in reservation until she confirms it
+	expiry := time.Now().Add(5 * time.Minute)
+	controller.SwapGuestServiceWith(&test.MockDBService{DefaultGuests: []*model.Guest{{Name: "alice", EventID: 2, ExpiredAt: &expiry}}})
+
+	req := httptest.NewRequest("POST", "/charge", nil)
+	for _, cookie := range res.Result().Cookies() {
+		req.AddCookie(cookie)
+	}
+	confirmed := httptest.NewRecorder()
+	controller.CreateTokenAuthoringMiddleWare(controller.DoAuth)(controller.PayWithCard)(confirmed, req)
+
+	if body := confirmed.Body.String(); !strings.Contains(body, "Congratulations alice") || !strings.Contains(body, "for The Free Show") {
+		t.Errorf("expected alice to be added to the guests of the free show, got %s", body)
+	}
+}
+
+func reserve(event, name string) *httptest.ResponseRecorder {
+	form := url.Values{"event": {event}, "guestname": {name}}
+	req := httptest.NewRequest("POST", "/reserve", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res := httptest.NewRecorder()
+	controller.MakeReservation(res, req)
+	return res
+}
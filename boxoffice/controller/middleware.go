@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"crypto/subtle"
 	"net/http"
 	"reflect"
 	"runtime"
@@ -35,6 +36,22 @@ func CreateTokenAuthoringMiddleWare(doAuth func(w http.ResponseWriter, req *http
 	}
 }
 
+/*
+ *  a middle layer service to protect the admin endpoints, letting through only the requests carrying the admin key in their X-Admin-Key header,
+ *  nothing gets through when no admin key has been set up
+ */
+func CreateAdminAuthMiddleWare(adminKey string) httpHandlerMiddleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, req *http.Request) {
+			if adminKey == "" || subtle.ConstantTimeCompare([]byte(req.Header.Get("X-Admin-Key")), []byte(adminKey)) != 1 {
+				sendJSONError(w, "not authorised", http.StatusUnauthorized)
+				return
+			}
+			next(w, req)
+		}
+	}
+}
+
 /*
  *  or:
  *   a service middleware wrapper to protect those endpoints that may require authentication
@@ -13,6 +13,7 @@ var jwtKey = []byte("box_office_secret_key")
 
 type Claims struct {
 	GuestName string `json:"username"`
+	EventID   uint   `json:"event"`
 	jwt.StandardClaims
 }
 
@@ -2,6 +2,7 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
@@ -12,23 +13,32 @@ import (
 	glog "github.com/go-kit/kit/log"
 	_ "github.com/google/uuid"
 	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
 	"github.com/ydsxiong/go-playground/boxoffice/config"
 	"github.com/ydsxiong/go-playground/boxoffice/controller"
 	"github.com/ydsxiong/go-playground/boxoffice/database"
 	"github.com/ydsxiong/go-playground/boxoffice/model"
+	"github.com/ydsxiong/go-playground/boxoffice/services/events"
 	"github.com/ydsxiong/go-playground/boxoffice/services/inprogress"
 	"github.com/ydsxiong/go-playground/boxoffice/services/registeredguest"
 	"gopkg.in/yaml.v2"
 )
 
 const (
-	ENV_DB_DIALECT          string = "DB_DIALECT"
-	ENV_DB_CONNECT_URI      string = "DB_CONNECT_URI"
-	ENV_DB_USERNAME         string = "DB_USERNAME"
-	EVN_DB_PASSWORD         string = "DB_PASSWORD"
-	ENV_PORT                string = "PORT"
-	MAX_RESERVATION_TIME    int    = 5
+	ENV_DB_DIALECT       string = "DB_DIALECT"
+	ENV_DB_CONNECT_URI   string = "DB_CONNECT_URI"
+	ENV_DB_USERNAME      string = "DB_USERNAME"
+	EVN_DB_PASSWORD      string = "DB_PASSWORD"
+	ENV_PORT             string = "PORT"
+	ENV_ADMIN_KEY        string = "ADMIN_KEY"
+	MAX_RESERVATION_TIME int    = 5
+)
+
+// the one show the box office ran before it ran many, the guests of which are moved over to it
+const (
+	LEGACY_EVENT_NAME       string = "The Best Ever Show"
 	TOTAL_TICKETS_AVAILABLE int    = 5
+	LEGACY_TICKET_PRICE     int64  = 999
 )
 
 func main() {
@@ -54,10 +64,14 @@ func main() {
 	}
 
 	gormDb := database.NewGormDB(conf)
-	// create guest table if not existed
-	gormDb.AutoMigrate(&model.Guest{})
+	// create guest and event tables if not existed, and move the guests of the one show over to events
+	gormDb.AutoMigrate(&model.Guest{}, &model.Event{})
+	if err := migrateGuestsToEvents(gormDb); err != nil {
+		log.Fatal(err)
+	}
 
 	guestService := registeredguest.NewGuestService(gormDb)
+	eventService := events.NewDBService(gormDb)
 
 	// now setup/init the app controller and handlers
 	logger := glog.NewLogfmtLogger(os.Stdout)
@@ -68,21 +82,82 @@ func main() {
 
 	controller.Init(
 		time.Duration(MAX_RESERVATION_TIME)*time.Minute,
-		TOTAL_TICKETS_AVAILABLE,
 		guestService,
+		eventService,
 		inProgressService,
 		template.Must(template.ParseGlob("views/*")))
 
 	var homePageHandler = controller.LoggingMiddleware(logger)(controller.DispatchHomePage)
+	var eventPageHandler = controller.LoggingMiddleware(logger)(controller.DispatchEventPage)
 	var reservationFormHandler = controller.LoggingMiddleware(logger)(controller.DispatchReservationForm)
 	var makeReservationHandler = controller.LoggingMiddleware(logger)(controller.MakeReservation)
 	var payWithCardHandler = controller.LoggingMiddleware(logger)(controller.CreateTokenAuthoringMiddleWare(controller.DoAuth)(controller.PayWithCard))
 
+	// the admin endpoints are shut unless an admin key is given
+	adminAuth := controller.CreateAdminAuthMiddleWare(os.Getenv(ENV_ADMIN_KEY))
+	var listEventsHandler = controller.LoggingMiddleware(logger)(adminAuth(controller.ListEvents))
+	var createEventHandler = controller.LoggingMiddleware(logger)(adminAuth(controller.CreateEvent))
+	var closeEventHandler = controller.LoggingMiddleware(logger)(adminAuth(controller.CloseEvent))
+
 	router := mux.NewRouter()
 	router.HandleFunc("/", homePageHandler).Methods("GET")
+	router.HandleFunc("/event", eventPageHandler).Methods("GET")
 	router.HandleFunc("/reservation", reservationFormHandler).Methods("GET")
 	router.HandleFunc("/reserve", makeReservationHandler).Methods("POST")
 	router.HandleFunc("/charge", payWithCardHandler).Methods("POST")
+	router.HandleFunc("/admin/events", listEventsHandler).Methods("GET")
+	router.HandleFunc("/admin/events", createEventHandler).Methods("POST")
+	router.HandleFunc("/admin/events/close", closeEventHandler).Methods("POST")
 
 	log.Fatal(http.ListenAndServe(":"+conf.ServerPort, router))
 }
+
+/*
+ * AutoMigrate only ever adds columns and indexes, so a database from before events needs a hand:
+ *  - a guest's name was unique on its own, which would stop them reserving for a second show, so that index goes,
+ *  - the guests already there get an event_id of 0, matching no event, so they are moved to an event for the
+ *    one show there used to be, with the tickets it had, in order for what they paid for to still be counted.
+ * It does nothing on a database that has been migrated already.
+ */
+func migrateGuestsToEvents(db *gorm.DB) error {
+	guestTable := db.NewScope(&model.Guest{}).TableName()
+	if db.Dialect().HasIndex(guestTable, "name") {
+		if err := db.Model(&model.Guest{}).RemoveIndex("name").Error; err != nil {
+			return fmt.Errorf("problem dropping the unique index on the guests' names %v", err)
+		}
+	}
+
+	var legacyGuests int
+	if err := db.Unscoped().Model(&model.Guest{}).Where("event_id = 0").Count(&legacyGuests).Error; err != nil {
+		return fmt.Errorf("problem counting the guests from before events %v", err)
+	}
+	if legacyGuests == 0 {
+		return nil
+	}
+
+	tx := db.Begin()
+	event := model.Event{}
+	err := tx.Unscoped().Where("name = ?", LEGACY_EVENT_NAME).First(&event).Error
+	if gorm.IsRecordNotFoundError(err) {
+		// nobody ever said when the one show was on, so it's a month from the move, for the admins to change or close
+		now := time.Now()
+		event = model.Event{
+			Name:     LEGACY_EVENT_NAME,
+			Capacity: TOTAL_TICKETS_AVAILABLE,
+			Price:    LEGACY_TICKET_PRICE,
+			StartsAt: now.AddDate(0, 1, 0),
+		}
+		if err = event.Validate(now); err == nil {
+			err = tx.Create(&event).Error
+		}
+	}
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("problem setting up the event for the guests from before events %v", err)
+	}
+	if err := tx.Unscoped().Model(&model.Guest{}).Where("event_id = 0").Update("event_id", event.ID).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("problem moving the guests from before events to %s %v", event.Name, err)
+	}
+	return tx.Commit().Error
+}
@@ -1,16 +1,91 @@
 package model
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/jinzhu/gorm"
 )
 
 /*
- * This table is for both successfully registered and reservations in progress guests
+ * This table is for both successfully registered and reservations in progress guests,
+ * a guest holding one ticket for each event they reserve for
  */
 type Guest struct {
 	gorm.Model
-	Name      string     `gorm:"unique" json:"name"`
+	Name      string     `gorm:"unique_index:idx_guest_event" json:"name"`
+	EventID   uint       `gorm:"unique_index:idx_guest_event" json:"event_id"`
 	ExpiredAt *time.Time `json:"expired_at"`
 }
+
+// the least a ticket that isn't free can cost, in pence, as cards can't be charged less
+const MinTicketPrice int64 = 30
+
+// the sales status of an event
+const (
+	EventOnSale       = "on sale"
+	EventNotOnSaleYet = "not on sale yet"
+	EventSalesClosed  = "sales closed"
+	EventClosed       = "closed"
+)
+
+/*
+ * A show with its own tickets, on sale from SalesOpenAt until SalesCloseAt or the show starts,
+ * unless it's closed before then. The price is in pence, a price of 0 makes it free.
+ */
+type Event struct {
+	gorm.Model
+	Name         string     `gorm:"unique" json:"name"`
+	Capacity     int        `json:"capacity"`
+	StartsAt     time.Time  `json:"starts_at"`
+	Price        int64      `json:"price"`
+	SalesOpenAt  time.Time  `json:"sales_open_at"`
+	SalesCloseAt time.Time  `json:"sales_close_at"`
+	ClosedAt     *time.Time `json:"closed_at"`
+}
+
+func (e *Event) SalesStatus(now time.Time) string {
+	switch {
+	case e.ClosedAt != nil:
+		return EventClosed
+	case now.Before(e.SalesOpenAt):
+		return EventNotOnSaleYet
+	case !now.Before(e.SalesCloseAt) || !now.Before(e.StartsAt):
+		return EventSalesClosed
+	}
+	return EventOnSale
+}
+
+func (e *Event) OnSale(now time.Time) bool {
+	return e.SalesStatus(now) == EventOnSale
+}
+
+/*
+ * fill in the sales window when it's not given, on sale from now until the show starts,
+ * and check the event makes sense
+ */
+func (e *Event) Validate(now time.Time) error {
+	if e.SalesOpenAt.IsZero() {
+		e.SalesOpenAt = now
+	}
+	if e.SalesCloseAt.IsZero() {
+		e.SalesCloseAt = e.StartsAt
+	}
+	switch {
+	case e.Name == "":
+		return fmt.Errorf("an event needs a name")
+	case e.Capacity <= 0:
+		return fmt.Errorf("an event needs at least one ticket, not %d", e.Capacity)
+	case e.Price < 0:
+		return fmt.Errorf("an event can't have a negative price")
+	case e.Price > 0 && e.Price < MinTicketPrice:
+		return fmt.Errorf("a ticket has to be free or cost at least %dp, not %dp", MinTicketPrice, e.Price)
+	case e.StartsAt.IsZero():
+		return fmt.Errorf("an event needs a start time")
+	case !e.SalesOpenAt.Before(e.SalesCloseAt):
+		return fmt.Errorf("the sales of an event have to open before they close")
+	case e.SalesCloseAt.After(e.StartsAt):
+		return fmt.Errorf("the sales of an event have to close by the time it starts")
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package events
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/ydsxiong/go-playground/boxoffice/model"
+)
+
+type dbService struct {
+	db *gorm.DB
+}
+
+func NewDBService(gdb *gorm.DB) EventService {
+	return &dbService{db: gdb}
+}
+
+func (s *dbService) GetAllEvents() ([]*model.Event, error) {
+	events := []*model.Event{}
+	if err := s.db.Order("starts_at").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (s *dbService) GetEvent(id uint) (*model.Event, error) {
+	event := model.Event{}
+	if err := s.db.First(&event, id).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (s *dbService) CreateEvent(event *model.Event) error {
+	return s.db.Create(event).Error
+}
+
+func (s *dbService) CloseEvent(id uint) (*model.Event, error) {
+	event, err := s.GetEvent(id)
+	if event == nil || err != nil {
+		return nil, err
+	}
+	if event.ClosedAt == nil {
+		closedAt := time.Now()
+		event.ClosedAt = &closedAt
+		if err := s.db.Save(event).Error; err != nil {
+			return nil, err
+		}
+	}
+	return event, nil
+}
@@ -0,0 +1,85 @@
+package events
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ydsxiong/go-playground/boxoffice/model"
+)
+
+type basicService struct {
+	events map[uint]*model.Event
+	lastID uint
+	mux    sync.Mutex
+}
+
+func NewInMemoryService() EventService {
+	return &basicService{events: make(map[uint]*model.Event)}
+}
+
+func (bs *basicService) GetAllEvents() ([]*model.Event, error) {
+	bs.mux.Lock()
+	defer bs.mux.Unlock()
+
+	events := []*model.Event{}
+	for _, event := range bs.events {
+		copied := *event
+		events = append(events, &copied)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].StartsAt.Equal(events[j].StartsAt) {
+			return events[i].ID < events[j].ID
+		}
+		return events[i].StartsAt.Before(events[j].StartsAt)
+	})
+	return events, nil
+}
+
+func (bs *basicService) GetEvent(id uint) (*model.Event, error) {
+	bs.mux.Lock()
+	defer bs.mux.Unlock()
+
+	event, existing := bs.events[id]
+	if !existing {
+		return nil, nil
+	}
+	copied := *event
+	return &copied, nil
+}
+
+func (bs *basicService) CreateEvent(event *model.Event) error {
+	bs.mux.Lock()
+	defer bs.mux.Unlock()
+
+	for _, existing := range bs.events {
+		if existing.Name == event.Name {
+			return fmt.Errorf("there is already an event called %s", event.Name)
+		}
+	}
+	bs.lastID++
+	event.ID = bs.lastID
+	event.CreatedAt = time.Now()
+	event.UpdatedAt = event.CreatedAt
+	copied := *event
+	bs.events[event.ID] = &copied
+	return nil
+}
+
+func (bs *basicService) CloseEvent(id uint) (*model.Event, error) {
+	bs.mux.Lock()
+	defer bs.mux.Unlock()
+
+	event, existing := bs.events[id]
+	if !existing {
+		return nil, nil
+	}
+	if event.ClosedAt == nil {
+		closedAt := time.Now()
+		event.ClosedAt = &closedAt
+		event.UpdatedAt = closedAt
+	}
+	copied := *event
+	return &copied, nil
+}
@@ -0,0 +1,46 @@
+package events_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ydsxiong/go-playground/boxoffice/model"
+	"github.com/ydsxiong/go-playground/boxoffice/services/events"
+)
+
+func TestEvents(t *testing.T) {
+
+	eventService := events.NewInMemoryService()
+
+	later := &model.Event{Name: "Hamlet", Capacity: 10, StartsAt: time.Now().Add(48 * time.Hour)}
+	sooner := &model.Event{Name: "Macbeth", Capacity: 10, StartsAt: time.Now().Add(24 * time.Hour)}
+	for _, event := range []*model.Event{later, sooner} {
+		if err := eventService.CreateEvent(event); err != nil {
+			t.Fatalf("problem creating the event %s, %v", event.Name, err)
+		}
+	}
+
+	if err := eventService.CreateEvent(&model.Event{Name: "Hamlet"}); err == nil {
+		t.Errorf("expected a second Hamlet to be refused")
+	}
+
+	all, _ := eventService.GetAllEvents()
+	if len(all) != 2 || all[0].Name != "Macbeth" || all[1].Name != "Hamlet" {
+		t.Errorf("expected Macbeth then Hamlet. Got %+v instead", all)
+	}
+
+	closed, _ := eventService.CloseEvent(later.ID)
+	if closed == nil || closed.ClosedAt == nil {
+		t.Errorf("expected Hamlet to be closed. Got %+v instead", closed)
+	}
+	if got, _ := eventService.GetEvent(later.ID); got.ClosedAt == nil {
+		t.Errorf("expected Hamlet to stay closed. Got %+v instead", got)
+	}
+
+	if got, _ := eventService.GetEvent(42); got != nil {
+		t.Errorf("expected no such event. Got %+v instead", got)
+	}
+	if got, _ := eventService.CloseEvent(42); got != nil {
+		t.Errorf("expected no such event to close. Got %+v instead", got)
+	}
+}
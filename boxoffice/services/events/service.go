@@ -0,0 +1,12 @@
+package events
+
+import (
+	"github.com/ydsxiong/go-playground/boxoffice/model"
+)
+
+type EventService interface {
+	GetAllEvents() ([]*model.Event, error)
+	GetEvent(id uint) (*model.Event, error)
+	CreateEvent(event *model.Event) error
+	CloseEvent(id uint) (*model.Event, error)
+}
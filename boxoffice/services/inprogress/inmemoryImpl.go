@@ -1,6 +1,7 @@
 package inprogress
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
@@ -25,20 +26,20 @@ func (bs *basicService) AddGuestInProgress(guest *model.Guest, reservationTime t
 	bs.mux.Lock()
 	defer bs.mux.Unlock()
 
-	bs.inprogress[guest.Name] = &inprogressWrapper{time.NewTimer(reservationTime), time.Now().Add(reservationTime)}
+	bs.inprogress[reservationKey(guest)] = &inprogressWrapper{time.NewTimer(reservationTime), time.Now().Add(reservationTime)}
 	return nil
 }
 
 func (bs *basicService) RemoveGuestFromInProgress(guest *model.Guest) error {
 	bs.mux.Lock()
 	defer bs.mux.Unlock()
-	reservation, existing := bs.inprogress[guest.Name]
+	reservation, existing := bs.inprogress[reservationKey(guest)]
 	if existing {
 		running := isRunning(reservation.timer)
 		if running {
 			reservation.timer.Stop()
 		}
-		delete(bs.inprogress, guest.Name)
+		delete(bs.inprogress, reservationKey(guest))
 	}
 	return nil
 }
@@ -47,18 +48,23 @@ func (bs *basicService) IsGuestInProcess(guest *model.Guest) (bool, time.Duratio
 	bs.mux.Lock()
 	defer bs.mux.Unlock()
 
-	reservation, existing := bs.inprogress[guest.Name]
+	reservation, existing := bs.inprogress[reservationKey(guest)]
 	if !existing {
 		return false, 0, nil
 	}
 	running := isRunning(reservation.timer)
 
 	if !running {
-		delete(bs.inprogress, guest.Name)
+		delete(bs.inprogress, reservationKey(guest))
 	}
 	return running, reservation.expireAt.Sub(time.Now()), nil
 }
 
+// a guest can have a reservation in progress for each event
+func reservationKey(guest *model.Guest) string {
+	return fmt.Sprintf("%d/%s", guest.EventID, guest.Name)
+}
+
 func isRunning(reservation *time.Timer) bool {
 	select {
 	case <-reservation.C:
@@ -48,3 +48,21 @@ func TestGuestInProgress(t *testing.T) {
 		t.Errorf("expected %d. Got %d instead", expected, got)
 	}
 }
+
+func TestGuestInProgressForEachEvent(t *testing.T) {
+
+	inProgressService := inprogress.NewInMemoryService()
+
+	inProgressService.AddGuestInProgress(&model.Guest{Name: "mark", EventID: 1}, 10*time.Second)
+
+	yes := false
+	if got, _, _ := inProgressService.IsGuestInProcess(&model.Guest{Name: "mark", EventID: 2}); got != yes {
+		t.Errorf("expected %t. Got %t instead", yes, got)
+	}
+
+	inProgressService.AddGuestInProgress(&model.Guest{Name: "mark", EventID: 2}, 10*time.Second)
+	expected := 2
+	if got, _ := inProgressService.NumberOfGuestInProcess(); got != expected {
+		t.Errorf("expected %d. Got %d instead", expected, got)
+	}
+}
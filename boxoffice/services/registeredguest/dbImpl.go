@@ -15,26 +15,26 @@ func NewGuestService(gormdb *gorm.DB) RegisteredGuestService {
 	return &guestService{gormdb}
 }
 
-func (svc *guestService) GetAllGuests() ([]*model.Guest, error) {
+func (svc *guestService) GetAllGuests(eventID uint) ([]*model.Guest, error) {
 
 	guests := []*model.Guest{}
-	if err := svc.db.Find(&guests).Error; err != nil {
+	if err := svc.db.Where("event_id = ?", eventID).Find(&guests).Error; err != nil {
 		return nil, err
 	}
 	return guests, nil
 }
 
-func (svc *guestService) GetGuestByName(guestname string) (*model.Guest, error) {
-	return findGuestByName(guestname, svc.db)
+func (svc *guestService) GetGuestByName(eventID uint, guestname string) (*model.Guest, error) {
+	return findGuestByName(eventID, guestname, svc.db)
 }
 
-func (svc *guestService) SaveRegisteredGuest(name string) error {
-	guest, err := findGuestByName(name, svc.db)
+func (svc *guestService) SaveRegisteredGuest(eventID uint, name string) error {
+	guest, err := findGuestByName(eventID, name, svc.db)
 	if err != nil {
 		return err
 	}
 	if guest == nil {
-		guest = &model.Guest{Name: name, ExpiredAt: nil}
+		guest = &model.Guest{Name: name, EventID: eventID, ExpiredAt: nil}
 	} else {
 		guest.ExpiredAt = nil
 	}
@@ -70,9 +70,9 @@ func (s *guestService) NumberOfGuestInProcess() (num int, err error) {
 	return 0, nil
 }
 
-func findGuestByName(guestname string, db *gorm.DB) (*model.Guest, error) {
+func findGuestByName(eventID uint, guestname string, db *gorm.DB) (*model.Guest, error) {
 	guest := model.Guest{}
-	if err := db.First(&guest, model.Guest{Name: guestname}).Error; err != nil {
+	if err := db.Where("event_id = ? AND name = ?", eventID, guestname).First(&guest).Error; err != nil {
 		if gorm.IsRecordNotFoundError(err) {
 			return nil, nil
 		}
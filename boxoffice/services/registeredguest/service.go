@@ -6,8 +6,8 @@ import (
 )
 
 type RegisteredGuestService interface {
-	GetAllGuests() ([]*model.Guest, error)
-	GetGuestByName(guestname string) (*model.Guest, error)
-	SaveRegisteredGuest(name string) error
+	GetAllGuests(eventID uint) ([]*model.Guest, error)
+	GetGuestByName(eventID uint, guestname string) (*model.Guest, error)
+	SaveRegisteredGuest(eventID uint, name string) error
 	inprogress.InProgressGuestService
 }
@@ -25,13 +25,19 @@ type MockDBService struct {
 	DefaultGuests []*model.Guest
 }
 
-func (ms *MockDBService) GetAllGuests() ([]*model.Guest, error) {
-	return ms.DefaultGuests, nil
+func (ms *MockDBService) GetAllGuests(eventID uint) ([]*model.Guest, error) {
+	guests := []*model.Guest{}
+	for _, guest := range ms.DefaultGuests {
+		if guest.EventID == eventID {
+			guests = append(guests, guest)
+		}
+	}
+	return guests, nil
 }
-func (ms *MockDBService) GetGuestByName(guestname string) (*model.Guest, error) {
+func (ms *MockDBService) GetGuestByName(eventID uint, guestname string) (*model.Guest, error) {
 	return nil, nil
 }
-func (ms *MockDBService) SaveRegisteredGuest(name string) error {
+func (ms *MockDBService) SaveRegisteredGuest(eventID uint, name string) error {
 	return nil
 }
 
@@ -0,0 +1,114 @@
+package clockface
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// The characters the terminal clock is drawn in.
+const (
+	asciiBezel      = '.'
+	asciiHourMark   = '+'
+	asciiHourHand   = '#'
+	asciiMinuteHand = '*'
+	asciiSecondHand = ':'
+	asciiCentre     = 'o'
+)
+
+/**
+WriteASCII draws the clock face showing the time t as text for a terminal, rows lines high.
+A character in a terminal is about twice as tall as it is wide, so the face is twice as many
+columns across as it is rows down to come out round. The hands are placed by TransformHand as the
+other faces' are and drawn second hand first, so the hour hand is on top where they cross.
+The time is written underneath, as the face is too coarse to read to the second.
+*/
+func WriteASCII(writer io.Writer, t time.Time, rows int) error {
+	if rows < 7 {
+		return fmt.Errorf("a terminal clock needs at least 7 rows, not %d", rows)
+	}
+	grid := newASCIIGrid(rows)
+
+	// the bezel is plotted a degree at a time, half the clock being 180 of them
+	for degree := 0.0; degree < 360; degree++ {
+		grid.plot(TransformHand(ClockHandDef{degree, ClockR, "", 180}), asciiBezel)
+	}
+	for hour := 0; hour < 12; hour++ {
+		at := TransformHand(ClockHandDef{float64(hour), ClockR * 0.85, "", HoursInHalfClock})
+		switch hour {
+		case 0:
+			grid.write(at, "12")
+		case 3, 6, 9:
+			grid.write(at, fmt.Sprint(hour))
+		default:
+			grid.plot(at, asciiHourMark)
+		}
+	}
+
+	grid.hand(GetSecondHandDef(timeInSeconds(t)), asciiSecondHand)
+	grid.hand(GetMinuteHandDef(timeInMinutes(t)), asciiMinuteHand)
+	grid.hand(GetHourHandDef(timeInHours(t)), asciiHourHand)
+	grid.plot(Point{ClockCentreX, ClockCentreY}, asciiCentre)
+
+	for _, line := range grid.cells {
+		if _, err := fmt.Fprintln(writer, strings.TrimRight(string(line), " ")); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(writer, "%*s\n", grid.columns/2+4, t.Format("15:04:05"))
+	return err
+}
+
+type asciiGrid struct {
+	rows, columns int
+	cells         [][]rune
+}
+
+func newASCIIGrid(rows int) *asciiGrid {
+	grid := &asciiGrid{rows: rows, columns: rows*2 + 1}
+	for row := 0; row < rows; row++ {
+		grid.cells = append(grid.cells, []rune(strings.Repeat(" ", grid.columns)))
+	}
+	return grid
+}
+
+// cell is where a point of the clock falls on the grid, the face filling it edge to edge.
+func (g *asciiGrid) cell(p Point) (int, int) {
+	column := int(math.Round((p.X - ClockCentreX + ClockR) / (2 * ClockR) * float64(g.columns-1)))
+	row := int(math.Round((p.Y - ClockCentreY + ClockR) / (2 * ClockR) * float64(g.rows-1)))
+	return row, column
+}
+
+func (g *asciiGrid) plot(p Point, char rune) {
+	row, column := g.cell(p)
+	if row >= 0 && row < g.rows && column >= 0 && column < g.columns {
+		g.cells[row][column] = char
+	}
+}
+
+// write puts the text centred on the point.
+func (g *asciiGrid) write(p Point, text string) {
+	row, column := g.cell(p)
+	for i, char := range text {
+		g.plot(g.point(row, column-len(text)/2+i), char)
+	}
+}
+
+func (g *asciiGrid) point(row, column int) Point {
+	return Point{
+		float64(column)/float64(g.columns-1)*2*ClockR + ClockCentreX - ClockR,
+		float64(row)/float64(g.rows-1)*2*ClockR + ClockCentreY - ClockR,
+	}
+}
+
+// hand plots the hand from the centre to its tip, a character for every cell it passes through.
+func (g *asciiGrid) hand(hand ClockHandDef, char rune) {
+	tip := TransformHand(hand)
+	steps := g.columns * 2
+	for i := 1; i <= steps; i++ {
+		along := float64(i) / float64(steps)
+		g.plot(Point{ClockCentreX + (tip.X-ClockCentreX)*along, ClockCentreY + (tip.Y-ClockCentreY)*along}, char)
+	}
+}
@@ -0,0 +1,294 @@
+package clockface
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The frames a clock can be streamed in, asked for with ?frames=.
+const (
+	SVGFrames   = "svg"
+	DeltaFrames = "delta"
+)
+
+// frameBuffer is how many frames a client can fall behind by before it is taken for dead and dropped.
+const frameBuffer = 8
+
+// face is where the hands of a themed clock are at a moment, by their ids, and what its digital readout reads.
+type face struct {
+	hands   map[string][]Point
+	digital string
+}
+
+/**
+faceAt places the hands of the theme at the time t. A smooth second hand sweeps through the
+fractions of a second rather than jumping from one second to the next, for clocks ticking faster than a second.
+*/
+func faceAt(theme Theme, t time.Time, smooth bool) face {
+	seconds := timeInSeconds(t)
+	if smooth {
+		seconds += float64(t.Nanosecond()) / float64(time.Second)
+	}
+	f := face{hands: map[string][]Point{
+		HourHandID:   handPoints(theme.HourHand, GetHourHandDef(timeInHours(t))),
+		MinuteHandID: handPoints(theme.MinuteHand, GetMinuteHandDef(timeInMinutes(t))),
+		SecondHandID: handPoints(theme.SecondHand, GetSecondHandDef(seconds)),
+	}}
+	if theme.Digital {
+		f.digital = t.Format("15:04:05")
+	}
+	return f
+}
+
+/**
+DeltaFrame is a frame of a clock streamed with ?frames=delta. After a first frame of the whole face
+in SVG, every frame only has the points of the hands that have moved since the frame before, by the
+ids of the hands in the SVG, and the digital readout when it has changed:
+
+	{"Hands": {"second-hand": [{"X": 52, "Y": 60.5}, {"X": 52, "Y": 14}]}}
+
+The points of a line hand are its tail and its tip, those of a tapered hand are the points of its polygon.
+Points are to two decimal places, a hundredth of the face's 104 units being too little to see.
+*/
+type DeltaFrame struct {
+	Hands   map[string][]Point `json:",omitempty"`
+	Digital string             `json:",omitempty"`
+}
+
+// delta is what has changed from the face before to this one, rounded as it is sent.
+func (f face) delta(before face) DeltaFrame {
+	var frame DeltaFrame
+	for id, points := range f.hands {
+		points = roundPoints(points)
+		if !samePoints(points, roundPoints(before.hands[id])) {
+			if frame.Hands == nil {
+				frame.Hands = map[string][]Point{}
+			}
+			frame.Hands[id] = points
+		}
+	}
+	if f.digital != before.digital {
+		frame.Digital = f.digital
+	}
+	return frame
+}
+
+func roundPoints(points []Point) []Point {
+	rounded := make([]Point, len(points))
+	for i, p := range points {
+		rounded[i] = Point{math.Round(p.X*100) / 100, math.Round(p.Y*100) / 100}
+	}
+	return rounded
+}
+
+func samePoints(a, b []Point) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+/**
+clockFrame is what a client is sent for a tick, its message being a DeltaFrame as JSON when delta
+is true, the whole face in SVG otherwise, or nil when nothing on its face has changed.
+*/
+type clockFrame struct {
+	at      time.Time
+	message []byte
+	delta   bool
+}
+
+/**
+clockClient is a browser the broadcaster streams a clock to, or every clock of a dashboard. The
+broadcaster puts its frames on frames, and closes dropped if it falls so far behind it is taken for dead.
+*/
+type clockClient struct {
+	zone      Zone
+	theme     Theme
+	delta     bool
+	dashboard *Dashboard
+	frames    chan clockFrame
+	dropped   chan struct{}
+
+	// what the client was last sent, for the next delta
+	last *face
+	// the second the dashboard was last sent at, its clocks only move once a second
+	sent time.Time
+}
+
+/**
+broadcaster streams clocks to every client from a single ticker, which only runs while there are
+clients. Each tick the face of every zone and theme asked for is drawn once, however many clients
+want it, and sent to each of them as SVG or as a delta from what they were sent last. Dashboards are
+drawn once a second in the same way, for every set of zones and theme asked for. Sending never
+waits on a client, one that has not taken its last frameBuffer frames is dropped instead.
+*/
+type broadcaster struct {
+	tick   time.Duration
+	now    func() time.Time
+	smooth bool
+
+	lock    sync.Mutex
+	clients map[*clockClient]bool
+	stop    chan struct{}
+}
+
+func newBroadcaster(tick time.Duration, now func() time.Time) *broadcaster {
+	return &broadcaster{tick: tick, now: now, smooth: tick < time.Second, clients: map[*clockClient]bool{}}
+}
+
+/**
+subscribe starts streaming the clock in the zone and theme to a new client, starting the ticker for the first one.
+A client of delta frames that already has the face as it was at since is sent the deltas from it, rather than
+a whole face first, since is the zero time otherwise.
+*/
+func (b *broadcaster) subscribe(zone Zone, theme Theme, delta bool, since time.Time) *clockClient {
+	client := &clockClient{zone: zone, theme: theme, delta: delta, frames: make(chan clockFrame, frameBuffer), dropped: make(chan struct{})}
+	if delta && !since.IsZero() {
+		before := faceAt(theme, since.In(zone.Location), b.smooth)
+		client.last = &before
+	}
+	return b.add(client)
+}
+
+// subscribeDashboard starts streaming every clock of the dashboard to a new client, as the JSON of Dashboard.At.
+func (b *broadcaster) subscribeDashboard(dashboard Dashboard) *clockClient {
+	return b.add(&clockClient{dashboard: &dashboard, frames: make(chan clockFrame, frameBuffer), dropped: make(chan struct{})})
+}
+
+func (b *broadcaster) add(client *clockClient) *clockClient {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.clients[client] = true
+	if b.stop == nil {
+		b.stop = make(chan struct{})
+		go b.run(b.stop)
+	}
+	return client
+}
+
+// unsubscribe stops streaming to the client, stopping the ticker when it was the last one.
+func (b *broadcaster) unsubscribe(client *clockClient) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.drop(client)
+}
+
+func (b *broadcaster) drop(client *clockClient) {
+	if !b.clients[client] {
+		return
+	}
+	delete(b.clients, client)
+	close(client.dropped)
+	if len(b.clients) == 0 {
+		close(b.stop)
+		b.stop = nil
+	}
+}
+
+func (b *broadcaster) count() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return len(b.clients)
+}
+
+func (b *broadcaster) run(stop chan struct{}) {
+	ticker := time.NewTicker(b.tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			b.broadcast(b.now())
+		}
+	}
+}
+
+// broadcast sends every client its frame for the time now.
+func (b *broadcaster) broadcast(now time.Time) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	type drawn struct {
+		face face
+		svg  []byte
+	}
+	faces := map[string]*drawn{}
+	dashboards := map[string][]byte{}
+	second := now.Truncate(time.Second)
+	for client := range b.clients {
+		if client.dashboard != nil {
+			if second.Equal(client.sent) {
+				continue
+			}
+			key := dashboardKey(*client.dashboard)
+			message, ok := dashboards[key]
+			if !ok {
+				message, _ = json.Marshal(client.dashboard.At(now))
+				dashboards[key] = message
+			}
+			if b.send(client, clockFrame{at: now, message: message}) {
+				client.sent = second
+			}
+			continue
+		}
+
+		local := now.In(client.zone.Location)
+		key := client.zone.Location.String() + "\x00" + client.theme.Name
+		d, ok := faces[key]
+		if !ok {
+			d = &drawn{face: faceAt(client.theme, local, b.smooth)}
+			faces[key] = d
+		}
+
+		frame := clockFrame{at: local, delta: client.delta && client.last != nil}
+		if frame.delta {
+			if delta := d.face.delta(*client.last); delta.Hands != nil || delta.Digital != "" {
+				frame.message, _ = json.Marshal(delta)
+			}
+		} else {
+			if d.svg == nil {
+				var svg bytes.Buffer
+				writeThemedSVG(&svg, client.theme, d.face)
+				d.svg = svg.Bytes()
+			}
+			frame.message = d.svg
+		}
+
+		if b.send(client, frame) {
+			client.last = &d.face
+		}
+	}
+}
+
+// send puts the frame on the client's frames, dropping the client instead if there is no room for it.
+func (b *broadcaster) send(client *clockClient, frame clockFrame) bool {
+	select {
+	case client.frames <- frame:
+		return true
+	default:
+		b.drop(client)
+		return false
+	}
+}
+
+// dashboardKey tells apart the dashboards that are not drawn the same, by their theme and zones.
+func dashboardKey(dashboard Dashboard) string {
+	key := &strings.Builder{}
+	key.WriteString(dashboard.Theme.Name)
+	for _, zone := range dashboard.Zones {
+		fmt.Fprintf(key, "\x00%s=%s", zone.Label, zone.Location)
+	}
+	return key.String()
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"time"
 )
 
 const svgStart = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
@@ -131,3 +132,23 @@ func GetMinuteHandDef(timeInUnit float64) ClockHandDef {
 func GetHourHandDef(timeInUnit float64) ClockHandDef {
 	return ClockHandDef{timeInUnit, HourHandLength, HourHandColor, HoursInHalfClock}
 }
+
+// WriteClockAt writes the clock face showing the time t, in the location t is in.
+func WriteClockAt(writer io.Writer, t time.Time) {
+	WriteSVG(writer,
+		GetSecondHandDef(timeInSeconds(t)),
+		GetMinuteHandDef(timeInMinutes(t)),
+		GetHourHandDef(timeInHours(t)))
+}
+
+func timeInSeconds(time time.Time) float64 {
+	return float64(time.Second())
+}
+
+func timeInMinutes(time time.Time) float64 {
+	return float64(time.Minute())
+}
+
+func timeInHours(time time.Time) float64 {
+	return float64(time.Hour()) + float64(time.Minute())/60.0
+}
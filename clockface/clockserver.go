@@ -0,0 +1,165 @@
+package clockface
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+/**
+ClockServer streams a clock face to the browser over a websocket, a frame every tick in the zone
+and theme the browser asked for with ?tz= and ?theme=. The frames are the whole face in SVG, or
+with ?frames=delta a first frame in SVG and DeltaFrame as JSON after it. A tick of less than a second
+sweeps the second hand smoothly. Every clock is drawn by the one broadcaster, however many are being watched,
+and so are the dashboards ServeDashboard streams.
+
+Over the same socket the browser can send ClockCommand to run a countdown, a stopwatch or alarms,
+which the server answers with ClockEvent as JSON, the events having a Mode where the delta frames do not.
+Each connection has its own countdown, stopwatch and alarms, which go when the connection does.
+Where a websocket cannot get through, ServeSSE streams the same frames as Server-Sent Events.
+*/
+type ClockServer struct {
+	themes      *Themes
+	now         func() time.Time
+	timers      int64
+	broadcaster *broadcaster
+}
+
+func NewClockServer(themes *Themes, tick time.Duration) *ClockServer {
+	return &ClockServer{themes: themes, now: time.Now, broadcaster: newBroadcaster(tick, time.Now)}
+}
+
+// Clients counts the browsers the clocks and dashboards are being streamed to.
+func (cs *ClockServer) Clients() int {
+	return cs.broadcaster.count()
+}
+
+// Timers counts the countdowns and alarms the connections have going.
+func (cs *ClockServer) Timers() int {
+	return int(atomic.LoadInt64(&cs.timers))
+}
+
+// ClockRequest reads the zone and the theme a clock is asked for in, ?tz=Europe/London&theme=roman for instance.
+func ClockRequest(req *http.Request, themes *Themes) (Zone, Theme, error) {
+	zone, err := LoadZone(req.URL.Query().Get("tz"))
+	if err != nil {
+		return Zone{}, Theme{}, err
+	}
+	theme, err := themes.Get(req.URL.Query().Get("theme"))
+	return zone, theme, err
+}
+
+// deltaRequest reads whether the clock is asked for in delta frames, with ?frames=delta.
+func deltaRequest(req *http.Request) (bool, error) {
+	switch frames := req.URL.Query().Get("frames"); frames {
+	case "", SVGFrames:
+		return false, nil
+	case DeltaFrames:
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown frames %q, expecting %s or %s", frames, SVGFrames, DeltaFrames)
+	}
+}
+
+func (cs *ClockServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	zone, theme, err := ClockRequest(req, cs.themes)
+	delta := false
+	if err == nil {
+		delta, err = deltaRequest(req)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ws := NewWwebSocket(w, req)
+	if ws.Conn == nil {
+		return
+	}
+	defer ws.Close()
+
+	session := newClockSession(func(event ClockEvent) error { return ws.WriteJSON(event) }, cs.now, &cs.timers)
+	defer session.close()
+
+	gone := make(chan struct{})
+	go cs.readCommands(ws, session, gone)
+
+	client := cs.broadcaster.subscribe(zone, theme, delta, time.Time{})
+	defer cs.broadcaster.unsubscribe(client)
+	for {
+		select {
+		case <-gone:
+			return
+		case <-client.dropped:
+			return
+		case frame := <-client.frames:
+			if frame.message != nil {
+				if _, err := ws.Write(frame.message); err != nil {
+					return
+				}
+			}
+			if err := session.tick(frame.at); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readCommands carries out the commands the browser sends until it goes away, when gone is closed.
+func (cs *ClockServer) readCommands(ws *ClockFaceWS, session *clockSession, gone chan struct{}) {
+	defer close(gone)
+	for {
+		_, message, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		var cmd ClockCommand
+		if err := json.Unmarshal(message, &cmd); err != nil {
+			err = fmt.Errorf("problem reading the command %q, %v", message, err)
+			ws.WriteJSON(ClockEvent{Mode: ErrorMode, Error: err.Error()})
+			continue
+		}
+		if err := session.handle(cmd); err != nil {
+			ws.WriteJSON(ClockEvent{Mode: ErrorMode, Error: err.Error()})
+		}
+	}
+}
+
+/**
+ServeDashboard streams every clock of the dashboard over a websocket, a frame with the clocks as JSON
+whenever the time on them changes, from the same broadcaster as the single clocks. Nothing the browser
+sends is used, it is only read to find out as soon as the browser has closed the websocket.
+*/
+func (cs *ClockServer) ServeDashboard(w http.ResponseWriter, req *http.Request, dashboard Dashboard) {
+	ws := NewWwebSocket(w, req)
+	if ws.Conn == nil {
+		return
+	}
+	defer ws.Close()
+
+	gone := make(chan struct{})
+	go func() {
+		defer close(gone)
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	client := cs.broadcaster.subscribeDashboard(dashboard)
+	defer cs.broadcaster.unsubscribe(client)
+	for {
+		select {
+		case <-gone:
+			return
+		case <-client.dropped:
+			return
+		case frame := <-client.frames:
+			if _, err := ws.Write(frame.message); err != nil {
+				return
+			}
+		}
+	}
+}
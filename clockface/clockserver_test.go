@@ -0,0 +1,349 @@
+package clockface_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/ydsxiong/go-playground/clockface"
+)
+
+const tick = 10 * time.Millisecond
+
+func TestClockServer(t *testing.T) {
+	t.Run("streams the face as SVG", func(t *testing.T) {
+		_, ws := startClockServer(t, "?theme=night")
+		_, frame, err := ws.ReadMessage()
+		if err != nil {
+			t.Fatalf("problem reading the first frame %v", err)
+		}
+		if !strings.HasPrefix(string(frame), "<?xml") {
+			t.Errorf("expected a frame of SVG, got %.40q", frame)
+		}
+	})
+
+	t.Run("rejects an unknown zone or theme", func(t *testing.T) {
+		for _, query := range []string{"?tz=Nowhere/Special", "?theme=plaid", "?frames=gif"} {
+			server := httptest.NewServer(clockface.NewClockServer(clockface.NewThemes(), tick))
+			response, err := http.Get(server.URL + query)
+			server.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if response.StatusCode != http.StatusBadRequest {
+				t.Errorf("%s: got status %d, want %d", query, response.StatusCode, http.StatusBadRequest)
+			}
+		}
+	})
+
+	t.Run("sends only what has moved after the first frame", func(t *testing.T) {
+		_, ws := startClockServer(t, "?frames=delta&theme=night")
+		_, first, err := ws.ReadMessage()
+		if err != nil {
+			t.Fatalf("problem reading the first frame %v", err)
+		}
+		if !strings.Contains(string(first), `id="second-hand"`) {
+			t.Fatalf("expected the first frame to be the whole face, got %.40q", first)
+		}
+
+		for i := 0; i < 5; i++ {
+			frame, size := nextDelta(t, ws)
+			if size >= len(first)/10 {
+				t.Errorf("expected a delta frame to be much smaller than the %d bytes of the face, got %d", len(first), size)
+			}
+			for id, points := range frame.Hands {
+				if id != clockface.HourHandID && id != clockface.MinuteHandID && id != clockface.SecondHandID {
+					t.Errorf("unknown hand %q in %+v", id, frame)
+				}
+				// the hands of the night theme are tapered
+				if len(points) != 5 {
+					t.Errorf("expected the 5 points of a tapered hand, got %v", points)
+				}
+			}
+		}
+	})
+
+	t.Run("sweeps the second hand when ticking faster than a second", func(t *testing.T) {
+		_, ws := startClockServer(t, "?frames=delta")
+		ws.ReadMessage()
+
+		moves := map[clockface.Point]bool{}
+		for deadline := time.Now().Add(200 * time.Millisecond); time.Now().Before(deadline); {
+			frame, _ := nextDelta(t, ws)
+			if points, ok := frame.Hands[clockface.SecondHandID]; ok {
+				moves[points[1]] = true
+			}
+		}
+		if len(moves) < 5 {
+			t.Errorf("expected the second hand to move with every tick, it was at %d places in 200ms", len(moves))
+		}
+	})
+
+	t.Run("streams to every client from the one broadcaster", func(t *testing.T) {
+		clock := clockface.NewClockServer(clockface.NewThemes(), tick)
+		server := httptest.NewServer(clock)
+		defer server.Close()
+
+		var clients []*websocket.Conn
+		for _, query := range []string{"", "?tz=Asia/Tokyo", "?frames=delta&theme=roman"} {
+			ws := dialClock(t, server, query)
+			if _, _, err := ws.ReadMessage(); err != nil {
+				t.Fatalf("%s: problem reading the first frame %v", query, err)
+			}
+			clients = append(clients, ws)
+		}
+		assertClients(t, clock, 3)
+
+		for _, ws := range clients {
+			ws.Close()
+		}
+		assertClients(t, clock, 0)
+	})
+
+	t.Run("streams dashboards from the same broadcaster as the clocks", func(t *testing.T) {
+		zones, err := clockface.LoadZones("Europe/London,Asia/Tokyo")
+		if err != nil {
+			t.Fatalf("problem loading zones, %v", err)
+		}
+		clock := clockface.NewClockServer(clockface.NewThemes(), tick)
+		mux := http.NewServeMux()
+		mux.Handle("/", clock)
+		mux.HandleFunc("/dashboard", func(w http.ResponseWriter, req *http.Request) {
+			clock.ServeDashboard(w, req, clockface.Dashboard{Zones: zones})
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		var clients []*websocket.Conn
+		for _, path := range []string{"/", "/dashboard", "/dashboard"} {
+			ws := dialClock(t, server, path)
+			ws.SetReadDeadline(time.Now().Add(time.Second))
+			_, frame, err := ws.ReadMessage()
+			if err != nil {
+				t.Fatalf("%s: problem reading the first frame %v", path, err)
+			}
+			if path == "/dashboard" {
+				var clocks []clockface.ZoneTime
+				if err := json.Unmarshal(frame, &clocks); err != nil || len(clocks) != 2 || clocks[1].Label != "Tokyo" {
+					t.Errorf("expected the clocks of London and Tokyo, got %.80q (%v)", frame, err)
+				}
+			}
+			clients = append(clients, ws)
+		}
+		assertClients(t, clock, 3)
+
+		for _, ws := range clients {
+			ws.Close()
+		}
+		assertClients(t, clock, 0)
+	})
+
+	t.Run("drops a client that has stopped reading", func(t *testing.T) {
+		clock := clockface.NewClockServer(clockface.NewThemes(), time.Millisecond)
+		server := httptest.NewServer(clock)
+		defer server.Close()
+
+		reading := dialClock(t, server, "?frames=delta")
+		dialClock(t, server, "")
+		assertClients(t, clock, 2)
+
+		// the client that reads keeps up, the other one's frames back up through the buffers of the
+		// connection until it is taken for dead
+		giveUp := time.Now().Add(30 * time.Second)
+		for clock.Clients() == 2 && time.Now().Before(giveUp) {
+			reading.SetReadDeadline(time.Now().Add(time.Second))
+			if _, _, err := reading.ReadMessage(); err != nil {
+				t.Fatalf("the client reading its frames lost them, %v", err)
+			}
+		}
+		assertClients(t, clock, 1)
+	})
+
+	t.Run("counts down to a time", func(t *testing.T) {
+		clock, ws := startClockServer(t, "")
+		sendCommand(t, ws, clockface.ClockCommand{Mode: "countdown", Action: "start", At: time.Now().Add(100 * time.Millisecond)})
+
+		started := nextEvent(t, ws, "countdown", "start")
+		if started.Remaining <= 0 || started.Remaining > 100 {
+			t.Errorf("expected up to 100ms remaining, got %d", started.Remaining)
+		}
+		ticked := nextEvent(t, ws, "countdown", "tick")
+		if ticked.Remaining > started.Remaining {
+			t.Errorf("expected the countdown to go down from %d, got %d", started.Remaining, ticked.Remaining)
+		}
+		nextEvent(t, ws, "countdown", "done")
+		assertTimers(t, clock, 0)
+	})
+
+	t.Run("times with a stopwatch", func(t *testing.T) {
+		_, ws := startClockServer(t, "")
+		sendCommand(t, ws, clockface.ClockCommand{Mode: "stopwatch", Action: "lap"})
+		nextEvent(t, ws, "error", "")
+
+		sendCommand(t, ws, clockface.ClockCommand{Mode: "stopwatch", Action: "start"})
+		if started := nextEvent(t, ws, "stopwatch", "start"); !started.Running {
+			t.Errorf("expected the stopwatch to be running, got %+v", started)
+		}
+		time.Sleep(3 * tick)
+		sendCommand(t, ws, clockface.ClockCommand{Mode: "stopwatch", Action: "lap"})
+		lap := nextEvent(t, ws, "stopwatch", "lap")
+		if len(lap.Laps) != 1 || lap.Laps[0] < 30 {
+			t.Errorf("expected a lap of at least 30ms, got %v", lap.Laps)
+		}
+
+		sendCommand(t, ws, clockface.ClockCommand{Mode: "stopwatch", Action: "stop"})
+		stopped := nextEvent(t, ws, "stopwatch", "stop")
+		if stopped.Running || stopped.Elapsed < lap.Laps[0] || len(stopped.Laps) != 1 {
+			t.Errorf("expected a stopped stopwatch with a lap, got %+v", stopped)
+		}
+
+		sendCommand(t, ws, clockface.ClockCommand{Mode: "stopwatch", Action: "reset"})
+		if reset := nextEvent(t, ws, "stopwatch", "reset"); reset.Elapsed != 0 || len(reset.Laps) != 0 {
+			t.Errorf("expected the stopwatch back at nothing, got %+v", reset)
+		}
+	})
+
+	t.Run("rings an alarm", func(t *testing.T) {
+		clock, ws := startClockServer(t, "")
+		sendCommand(t, ws, clockface.ClockCommand{Mode: "alarm", Action: "set", At: time.Now().Add(50 * time.Millisecond), Label: "Tea"})
+		nextEvent(t, ws, "alarm", "set")
+		if ring := nextEvent(t, ws, "alarm", "ring"); ring.Label != "Tea" {
+			t.Errorf("expected the Tea alarm to ring, got %q", ring.Label)
+		}
+		assertTimers(t, clock, 0)
+	})
+
+	t.Run("cancels an alarm", func(t *testing.T) {
+		clock, ws := startClockServer(t, "")
+		sendCommand(t, ws, clockface.ClockCommand{Mode: "alarm", Action: "set", At: time.Now().Add(time.Hour), Label: "Tea"})
+		nextEvent(t, ws, "alarm", "set")
+		assertTimers(t, clock, 1)
+
+		sendCommand(t, ws, clockface.ClockCommand{Mode: "alarm", Action: "cancel", Label: "Tea"})
+		nextEvent(t, ws, "alarm", "cancel")
+		assertTimers(t, clock, 0)
+	})
+
+	t.Run("tells of commands it cannot carry out", func(t *testing.T) {
+		_, ws := startClockServer(t, "")
+		commands := []string{
+			`not json`,
+			`{"Mode": "egg timer"}`,
+			`{"Mode": "stopwatch", "Action": "rewind"}`,
+			`{"Mode": "alarm", "Action": "cancel", "Label": "Tea"}`,
+			`{"Mode": "alarm", "Action": "set", "At": "2001-01-01T00:00:00Z"}`,
+			`{"Mode": "countdown", "Action": "start", "At": "2001-01-01T00:00:00Z"}`,
+		}
+		for _, command := range commands {
+			if err := ws.WriteMessage(websocket.TextMessage, []byte(command)); err != nil {
+				t.Fatal(err)
+			}
+			if event := nextEvent(t, ws, "error", ""); event.Error == "" {
+				t.Errorf("%s: expected an error, got %+v", command, event)
+			}
+		}
+	})
+
+	t.Run("stops the timers of a connection when it closes", func(t *testing.T) {
+		clock, ws := startClockServer(t, "")
+		sendCommand(t, ws, clockface.ClockCommand{Mode: "countdown", Action: "start", At: time.Now().Add(time.Hour)})
+		nextEvent(t, ws, "countdown", "start")
+		sendCommand(t, ws, clockface.ClockCommand{Mode: "alarm", Action: "set", At: time.Now().Add(time.Hour), Label: "Tea"})
+		nextEvent(t, ws, "alarm", "set")
+		sendCommand(t, ws, clockface.ClockCommand{Mode: "alarm", Action: "set", At: time.Now().Add(time.Hour), Label: "Coffee"})
+		nextEvent(t, ws, "alarm", "set")
+		assertTimers(t, clock, 3)
+
+		ws.Close()
+		assertTimers(t, clock, 0)
+	})
+}
+
+func startClockServer(t *testing.T, query string) (*clockface.ClockServer, *websocket.Conn) {
+	t.Helper()
+	clock := clockface.NewClockServer(clockface.NewThemes(), tick)
+	server := httptest.NewServer(clock)
+	t.Cleanup(server.Close)
+	return clock, dialClock(t, server, query)
+}
+
+func dialClock(t *testing.T, server *httptest.Server, query string) *websocket.Conn {
+	t.Helper()
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+query, nil)
+	if err != nil {
+		t.Fatalf("could not open a ws connection on %s %v", server.URL, err)
+	}
+	t.Cleanup(func() { ws.Close() })
+	return ws
+}
+
+// nextDelta reads the next delta frame and how big it was.
+func nextDelta(t *testing.T, ws *websocket.Conn) (clockface.DeltaFrame, int) {
+	t.Helper()
+	ws.SetReadDeadline(time.Now().Add(time.Second))
+	_, message, err := ws.ReadMessage()
+	if err != nil {
+		t.Fatalf("gave up waiting for a delta frame, %v", err)
+	}
+	var frame clockface.DeltaFrame
+	if err := json.Unmarshal(message, &frame); err != nil {
+		t.Fatalf("expected a delta frame, got %.40q, %v", message, err)
+	}
+	return frame, len(message)
+}
+
+func assertClients(t *testing.T, clock *clockface.ClockServer, want int) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if clock.Clients() == want {
+			return
+		}
+	}
+	t.Errorf("got %d clients, want %d", clock.Clients(), want)
+}
+
+func sendCommand(t *testing.T, ws *websocket.Conn, cmd clockface.ClockCommand) {
+	t.Helper()
+	if err := ws.WriteJSON(cmd); err != nil {
+		t.Fatalf("could not send the command %+v %v", cmd, err)
+	}
+}
+
+// nextEvent reads past the frames of the face and any other events to the next event of the mode and action.
+func nextEvent(t *testing.T, ws *websocket.Conn, mode, action string) clockface.ClockEvent {
+	t.Helper()
+	ws.SetReadDeadline(time.Now().Add(time.Second))
+	for {
+		_, message, err := ws.ReadMessage()
+		if err != nil {
+			t.Fatalf("gave up waiting for a %s %s event, %v", mode, action, err)
+		}
+		if !strings.HasPrefix(string(message), "{") {
+			continue
+		}
+		var event clockface.ClockEvent
+		if err := json.Unmarshal(message, &event); err != nil {
+			t.Fatalf("problem reading the event %q, %v", message, err)
+		}
+		if event.Mode == mode && event.Action == action {
+			return event
+		}
+		if event.Mode == "error" {
+			t.Fatalf("expected a %s %s event, got the error %q", mode, action, event.Error)
+		}
+	}
+}
+
+func assertTimers(t *testing.T, clock *clockface.ClockServer, want int) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if clock.Timers() == want {
+			return
+		}
+	}
+	t.Errorf("got %d timers going, want %d", clock.Timers(), want)
+}
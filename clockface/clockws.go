@@ -3,17 +3,29 @@ package clockface
 import (
 	"log"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
 
+// writeWait is how long a write to a browser can take before it is taken for dead.
+const writeWait = 10 * time.Second
+
 var wsUpgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
 }
 
+/**
+ClockFaceWS is the websocket a clock is streamed over. A websocket takes one writer at a time,
+and the frames of the face and the events of its modes come from different goroutines, so every
+write goes through the lock. A write that takes longer than writeWait fails, as the browser
+has stopped reading.
+*/
 type ClockFaceWS struct {
 	*websocket.Conn
+	lock sync.Mutex
 }
 
 func NewWwebSocket(w http.ResponseWriter, r *http.Request) *ClockFaceWS {
@@ -22,14 +34,17 @@ func NewWwebSocket(w http.ResponseWriter, r *http.Request) *ClockFaceWS {
 		log.Printf("problem upgrading connection to WebSockets %v\n", err)
 	}
 
-	return &ClockFaceWS{conn}
+	return &ClockFaceWS{Conn: conn}
 }
 
 /**
 Enable it to be used as a response writer in the context of http req/res handling
 */
 func (ws *ClockFaceWS) Write(p []byte) (n int, err error) {
-	err = ws.WriteMessage(1, p)
+	ws.lock.Lock()
+	defer ws.lock.Unlock()
+	ws.SetWriteDeadline(time.Now().Add(writeWait))
+	err = ws.WriteMessage(websocket.TextMessage, p)
 
 	if err != nil {
 		return 0, err
@@ -37,3 +52,10 @@ func (ws *ClockFaceWS) Write(p []byte) (n int, err error) {
 
 	return len(p), nil
 }
+
+func (ws *ClockFaceWS) WriteJSON(v interface{}) error {
+	ws.lock.Lock()
+	defer ws.lock.Unlock()
+	ws.SetWriteDeadline(time.Now().Add(writeWait))
+	return ws.Conn.WriteJSON(v)
+}
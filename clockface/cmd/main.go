@@ -1,8 +1,13 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"os"
+	"strings"
 	"text/template"
 	"time"
 
@@ -35,41 +40,125 @@ A thing to note about SVGs: the origin - point (0,0) - is at the top left hand c
 
 //var clockFilePath = "./clock.svg"
 
+// the zones on the dashboard unless told otherwise, labelled with their cities
+const defaultZones = "UTC,America/Los_Angeles,America/New_York,Europe/London,Asia/Kolkata,Asia/Tokyo,Australia/Sydney"
+
 func main() {
-	var clockfaceTemplate, err = template.ParseFiles("clockface.html")
+	zoneList := flag.String("zones", defaultZones, "comma separated time zones for the dashboard, each a zone or Label=Zone")
+	themeFile := flag.String("themes", "", "a JSON file of themes to add to the built-in ones")
+	tick := flag.Duration("tick", time.Second, "how often the clocks in the browser tick, less than a second sweeps the second hand")
+	terminal := flag.Bool("terminal", false, "show the clock in the terminal instead of serving it")
+	snapshot := flag.String("snapshot", "", "write the clock at this time (RFC 3339, or now) to files instead of serving it")
+	formats := flag.String("formats", "svg,png,txt", "the comma separated formats of the snapshot files")
+	out := flag.String("out", ".", "the directory to write the snapshot files to")
+	tz := flag.String("tz", "", "the time zone of the terminal clock or the snapshot, the local time by default")
+	themeName := flag.String("theme", clockface.ClassicThemeName, "the theme of the snapshot")
+	flag.Parse()
+
+	zones, err := clockface.LoadZones(*zoneList)
+	if err != nil {
+		log.Fatalf("problem with the dashboard zones, %v", err)
+	}
+	themes, err := loadThemes(*themeFile)
+	if err != nil {
+		log.Fatalf("problem with the themes, %v", err)
+	}
+
+	if *terminal || *snapshot != "" {
+		zone, err := clockface.LoadZone(*tz)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if *terminal {
+			runInTerminal(os.Stdout, zone)
+		}
+		theme, err := themes.Get(*themeName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := writeSnapshots(*out, theme, *snapshot, zone, strings.Split(*formats, ",")); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	templates, err := template.ParseFiles("clockface.html", "dashboard.html")
 	if err != nil {
-		log.Printf("problem upgrading connection to WebSockets %v\n", err)
+		log.Printf("problem loading the clock face templates %v\n", err)
 	} else {
 		http.HandleFunc("/clock", func(w http.ResponseWriter, req *http.Request) {
-			clockfaceTemplate.Execute(w, nil)
+			if _, _, err := clockface.ClockRequest(req, themes); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			templates.ExecuteTemplate(w, "clockface.html", nil)
+		})
+		clock := clockface.NewClockServer(themes, *tick)
+		http.Handle("/clockupdate", clock)
+		http.HandleFunc("/clockevents", clock.ServeSSE)
+		http.HandleFunc("/dashboard", func(w http.ResponseWriter, req *http.Request) {
+			theme, err := themes.Get(req.URL.Query().Get("theme"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			templates.ExecuteTemplate(w, "dashboard.html", clockface.Dashboard{Zones: zones, Theme: theme}.At(time.Now()))
+		})
+		http.HandleFunc("/dashboardupdate", func(w http.ResponseWriter, req *http.Request) {
+			theme, err := themes.Get(req.URL.Query().Get("theme"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			clock.ServeDashboard(w, req, clockface.Dashboard{Zones: zones, Theme: theme})
 		})
-		http.HandleFunc("/clockupdate", sendClockToBrowser)
 	}
 	http.ListenAndServe(":9080", nil)
 }
 
-func sendClockToBrowser(w http.ResponseWriter, req *http.Request) {
-	clockfaceWS := clockface.NewWwebSocket(w, req)
-
+// runInTerminal redraws the clock in the terminal every second, until it is interrupted.
+func runInTerminal(out io.Writer, zone clockface.Zone) {
 	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
 	for currTime := range ticker.C {
-		clockface.WriteSVG(clockfaceWS,
-			clockface.GetSecondHandDef(timeInSeconds(currTime)),
-			clockface.GetMinuteHandDef(timeInMinutes(currTime)),
-			clockface.GetHourHandDef(timeInHours(currTime)))
+		// clear the screen and go back to the top left corner
+		fmt.Fprint(out, "\033[H\033[2J")
+		fmt.Fprintf(out, "%s (%s)\n", zone.Label, clockface.FormatOffset(offsetOf(currTime.In(zone.Location))))
+		clockface.WriteASCII(out, currTime.In(zone.Location), 21)
 	}
 }
 
-func timeInSeconds(time time.Time) float64 {
-	return float64(time.Second())
+func offsetOf(t time.Time) int {
+	_, offset := t.Zone()
+	return offset
 }
 
-func timeInMinutes(time time.Time) float64 {
-	return float64(time.Minute())
+func writeSnapshots(dir string, theme clockface.Theme, at string, zone clockface.Zone, formats []string) error {
+	t := time.Now()
+	if at != "now" {
+		var err error
+		if t, err = time.Parse(time.RFC3339, at); err != nil {
+			return fmt.Errorf("problem reading the time of the snapshot, %v", err)
+		}
+	}
+	paths, err := clockface.WriteSnapshots(dir, theme, t.In(zone.Location), formats...)
+	for _, path := range paths {
+		fmt.Println("wrote", path)
+	}
+	return err
 }
 
-func timeInHours(time time.Time) float64 {
-	return float64(time.Hour()) + float64(time.Minute())/60.0
+func loadThemes(path string) (*clockface.Themes, error) {
+	themes := clockface.NewThemes()
+	if path == "" {
+		return themes, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return themes, themes.Load(file)
 }
 
 // func sendClockToFile() {
@@ -78,9 +167,5 @@ func timeInHours(time time.Time) float64 {
 // 		panic(err)
 // 	}
 // 	defer clockfile.Close()
-// 	now := time.Now()
-// 	clockface.WriteSVG(clockfile,
-// 		clockface.GetSecondHandDef(timeInSeconds(now)),
-// 		clockface.GetMinuteHandDef(timeInMinutes(now)),
-// 		clockface.GetHourHandDef(timeInHours(now)))
+// 	clockface.WriteClockAt(clockfile, time.Now())
 // }
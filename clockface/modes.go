@@ -0,0 +1,284 @@
+package clockface
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// The modes the browser can ask the clock for, alongside the time it always shows.
+const (
+	CountdownMode = "countdown"
+	StopwatchMode = "stopwatch"
+	AlarmMode     = "alarm"
+	ErrorMode     = "error"
+)
+
+// The actions of the modes, what the browser asks for and what the clock tells it has happened.
+const (
+	StartAction  = "start"
+	StopAction   = "stop"
+	LapAction    = "lap"
+	ResetAction  = "reset"
+	SetAction    = "set"
+	CancelAction = "cancel"
+	TickAction   = "tick"
+	DoneAction   = "done"
+	RingAction   = "ring"
+)
+
+/**
+ClockCommand is what the browser sends over the clock's websocket to use one of the modes:
+
+	{"Mode": "countdown", "Action": "start", "At": "2026-10-18T17:00:00Z"}   count down to At
+	{"Mode": "countdown", "Action": "cancel"}
+	{"Mode": "stopwatch", "Action": "start"}                                  or stop, lap and reset
+	{"Mode": "alarm", "Action": "set", "At": "2026-10-18T17:00:00Z", "Label": "Tea"}
+	{"Mode": "alarm", "Action": "cancel", "Label": "Tea"}
+
+Setting an alarm with a label that is already set moves it to the new time.
+*/
+type ClockCommand struct {
+	Mode   string
+	Action string
+	At     time.Time
+	Label  string
+}
+
+/**
+ClockEvent is what the clock sends back as JSON, in between the frames of its face. Every command
+is answered with an event of the same mode and action, or an event of the error mode if it could not be done.
+While a countdown or a running stopwatch is going there is a tick event with every frame, a countdown
+is done once it gets to its time and an alarm rings at its time. Times are in milliseconds.
+*/
+type ClockEvent struct {
+	Mode      string
+	Action    string  `json:",omitempty"`
+	Label     string  `json:",omitempty"`
+	Remaining int64   `json:",omitempty"`
+	Elapsed   int64   `json:",omitempty"`
+	Laps      []int64 `json:",omitempty"`
+	Running   bool    `json:",omitempty"`
+	Error     string  `json:",omitempty"`
+}
+
+/**
+clockSession is the state of the modes of one connection. The timers it starts belong to the
+connection and are all stopped by close, after which the session does nothing. live counts the
+timers that have neither gone off nor been stopped, and can be shared between sessions.
+*/
+type clockSession struct {
+	send func(ClockEvent) error
+	now  func() time.Time
+	live *int64
+
+	mu        sync.Mutex
+	closed    bool
+	countdown *countdown
+	stopwatch stopwatch
+	alarms    map[string]*time.Timer
+}
+
+type countdown struct {
+	target time.Time
+	timer  *time.Timer
+}
+
+type stopwatch struct {
+	running bool
+	started time.Time
+	elapsed time.Duration
+	laps    []time.Duration
+}
+
+func newClockSession(send func(ClockEvent) error, now func() time.Time, live *int64) *clockSession {
+	return &clockSession{send: send, now: now, live: live, alarms: map[string]*time.Timer{}}
+}
+
+// startTimer calls f once the time d has gone by, unless the timer is stopped by stopTimer first.
+func (s *clockSession) startTimer(d time.Duration, f func()) *time.Timer {
+	atomic.AddInt64(s.live, 1)
+	return time.AfterFunc(d, func() {
+		atomic.AddInt64(s.live, -1)
+		f()
+	})
+}
+
+func (s *clockSession) stopTimer(timer *time.Timer) {
+	if timer.Stop() {
+		atomic.AddInt64(s.live, -1)
+	}
+}
+
+// handle carries out a command, an error is what to tell the browser when it cannot be done.
+func (s *clockSession) handle(cmd ClockCommand) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+
+	switch cmd.Mode {
+	case CountdownMode:
+		return s.countdownCommand(cmd)
+	case StopwatchMode:
+		return s.stopwatchCommand(cmd)
+	case AlarmMode:
+		return s.alarmCommand(cmd)
+	}
+	return fmt.Errorf("unknown mode %q, expecting %s, %s or %s", cmd.Mode, CountdownMode, StopwatchMode, AlarmMode)
+}
+
+func (s *clockSession) countdownCommand(cmd ClockCommand) error {
+	switch cmd.Action {
+	case StartAction:
+		if !cmd.At.After(s.now()) {
+			return fmt.Errorf("a countdown needs a time in the future to count down to, not %v", cmd.At)
+		}
+		s.stopCountdown()
+		c := &countdown{target: cmd.At}
+		c.timer = s.startTimer(cmd.At.Sub(s.now()), func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.closed || s.countdown != c {
+				return
+			}
+			s.countdown = nil
+			s.send(ClockEvent{Mode: CountdownMode, Action: DoneAction})
+		})
+		s.countdown = c
+		return s.send(ClockEvent{Mode: CountdownMode, Action: StartAction, Remaining: millis(cmd.At.Sub(s.now()))})
+	case CancelAction:
+		s.stopCountdown()
+		return s.send(ClockEvent{Mode: CountdownMode, Action: CancelAction})
+	}
+	return unknownAction(cmd, StartAction, CancelAction)
+}
+
+func (s *clockSession) stopCountdown() {
+	if s.countdown != nil {
+		s.stopTimer(s.countdown.timer)
+		s.countdown = nil
+	}
+}
+
+func (s *clockSession) stopwatchCommand(cmd ClockCommand) error {
+	now := s.now()
+	watch := &s.stopwatch
+	switch cmd.Action {
+	case StartAction:
+		if !watch.running {
+			watch.running, watch.started = true, now
+		}
+	case StopAction:
+		if watch.running {
+			watch.elapsed += now.Sub(watch.started)
+			watch.running = false
+		}
+	case LapAction:
+		if !watch.running {
+			return fmt.Errorf("the stopwatch has to be running to time a lap")
+		}
+		watch.laps = append(watch.laps, watch.at(now))
+	case ResetAction:
+		*watch = stopwatch{}
+	default:
+		return unknownAction(cmd, StartAction, StopAction, LapAction, ResetAction)
+	}
+	return s.send(watch.event(cmd.Action, now))
+}
+
+// at is the time on the stopwatch at now.
+func (w stopwatch) at(now time.Time) time.Duration {
+	if w.running {
+		return w.elapsed + now.Sub(w.started)
+	}
+	return w.elapsed
+}
+
+func (w stopwatch) event(action string, now time.Time) ClockEvent {
+	event := ClockEvent{Mode: StopwatchMode, Action: action, Elapsed: millis(w.at(now)), Running: w.running}
+	for _, lap := range w.laps {
+		event.Laps = append(event.Laps, millis(lap))
+	}
+	return event
+}
+
+func (s *clockSession) alarmCommand(cmd ClockCommand) error {
+	label := cmd.Label
+	if label == "" {
+		label = AlarmMode
+	}
+	switch cmd.Action {
+	case SetAction:
+		if !cmd.At.After(s.now()) {
+			return fmt.Errorf("an alarm needs a time in the future to go off at, not %v", cmd.At)
+		}
+		if timer, ok := s.alarms[label]; ok {
+			s.stopTimer(timer)
+		}
+		var timer *time.Timer
+		timer = s.startTimer(cmd.At.Sub(s.now()), func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.closed || s.alarms[label] != timer {
+				return
+			}
+			delete(s.alarms, label)
+			s.send(ClockEvent{Mode: AlarmMode, Action: RingAction, Label: label})
+		})
+		s.alarms[label] = timer
+		return s.send(ClockEvent{Mode: AlarmMode, Action: SetAction, Label: label, Remaining: millis(cmd.At.Sub(s.now()))})
+	case CancelAction:
+		timer, ok := s.alarms[label]
+		if !ok {
+			return fmt.Errorf("there is no alarm %q to cancel", label)
+		}
+		s.stopTimer(timer)
+		delete(s.alarms, label)
+		return s.send(ClockEvent{Mode: AlarmMode, Action: CancelAction, Label: label})
+	}
+	return unknownAction(cmd, SetAction, CancelAction)
+}
+
+// tick tells the browser how the countdown and the stopwatch are getting on, with every frame of the clock.
+func (s *clockSession) tick(now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	if s.countdown != nil {
+		if err := s.send(ClockEvent{Mode: CountdownMode, Action: TickAction, Remaining: millis(s.countdown.target.Sub(now))}); err != nil {
+			return err
+		}
+	}
+	if s.stopwatch.running {
+		return s.send(s.stopwatch.event(TickAction, now))
+	}
+	return nil
+}
+
+// close stops every timer the session started, the connection they would have told has gone.
+func (s *clockSession) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.stopCountdown()
+	for label, timer := range s.alarms {
+		s.stopTimer(timer)
+		delete(s.alarms, label)
+	}
+}
+
+func unknownAction(cmd ClockCommand, expecting ...string) error {
+	return fmt.Errorf("unknown %s action %q, expecting one of %v", cmd.Mode, cmd.Action, expecting)
+}
+
+func millis(d time.Duration) int64 {
+	if d < 0 {
+		return 0
+	}
+	return d.Milliseconds()
+}
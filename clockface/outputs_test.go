@@ -0,0 +1,104 @@
+package clockface_test
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ydsxiong/go-playground/clockface"
+)
+
+func TestWritePNG(t *testing.T) {
+	b := bytes.Buffer{}
+	if err := clockface.WritePNG(&b, clockface.ClassicTheme, simpletime(0, 30, 0), 208); err != nil {
+		t.Fatalf("problem writing the PNG, %v", err)
+	}
+	img, err := png.Decode(&b)
+	if err != nil {
+		t.Fatalf("problem reading the PNG back, %v", err)
+	}
+	if size := img.Bounds().Size(); size.X != 208 || size.Y != 208 {
+		t.Fatalf("got a %v image, want 208 pixels square", size)
+	}
+
+	// the image is twice the size of the clock, the minute hand of half past points straight down
+	halfway := color.NRGBAModel.Convert(img.At(104, 104+clockface.MinuteHandLength)).(color.NRGBA)
+	if halfway != (color.NRGBA{0, 0, 0, 255}) {
+		t.Errorf("got %v on the minute hand, want it black", halfway)
+	}
+
+	t.Run("the edges of a hand are anti-aliased", func(t *testing.T) {
+		b := bytes.Buffer{}
+		clockface.WritePNG(&b, clockface.ClassicTheme, simpletime(0, 8, 0), 208)
+		img, _ := png.Decode(&b)
+
+		// around the middle of the minute hand, at eight minutes past it is on a slant
+		greys := map[color.NRGBA]bool{}
+		for y := 70; y < 86; y++ {
+			for x := 124; x < 144; x++ {
+				c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+				if c.R == c.G && c.G == c.B && c.R > 0 && c.R < 255 {
+					greys[c] = true
+				}
+			}
+		}
+		if len(greys) == 0 {
+			t.Error("expected shades of grey at the edges of the minute hand")
+		}
+	})
+
+	t.Run("outside the face is transparent", func(t *testing.T) {
+		if _, _, _, a := img.At(1, 1).RGBA(); a != 0 {
+			t.Errorf("got alpha %d in the corner, want it transparent", a)
+		}
+	})
+}
+
+func TestWriteASCII(t *testing.T) {
+	b := bytes.Buffer{}
+	if err := clockface.WriteASCII(&b, simpletime(10, 9, 30), 21); err != nil {
+		t.Fatalf("problem writing the terminal clock, %v", err)
+	}
+
+	golden := filepath.Join("testdata", "ascii.txt")
+	if *update {
+		os.WriteFile(golden, b.Bytes(), 0644)
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("problem reading %s, %v", golden, err)
+	}
+	if b.String() != string(want) {
+		t.Errorf("got\n%s\nwant\n%s", b.String(), want)
+	}
+
+	if err := clockface.WriteASCII(&b, simpletime(10, 9, 30), 3); err == nil {
+		t.Error("expected an error for a clock too small to draw")
+	}
+}
+
+func TestWriteSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	at := time.Date(2026, time.March, 14, 10, 9, 30, 0, time.UTC)
+
+	paths, err := clockface.WriteSnapshots(dir, clockface.ClassicTheme, at, "svg", "png", "txt")
+	if err != nil {
+		t.Fatalf("problem writing snapshots, %v", err)
+	}
+	for _, name := range []string{"clock-20260314-100930.svg", "clock-20260314-100930.png", "clock-20260314-100930.txt"} {
+		if info, err := os.Stat(filepath.Join(dir, name)); err != nil || info.Size() == 0 {
+			t.Errorf("expected a snapshot %s, %v", name, err)
+		}
+	}
+	if len(paths) != 3 {
+		t.Errorf("got paths %v, want 3 of them", paths)
+	}
+
+	if _, err := clockface.WriteSnapshots(dir, clockface.ClassicTheme, at, "gif"); err == nil {
+		t.Error("expected an error for a format there is no writer for")
+	}
+}
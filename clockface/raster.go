@@ -0,0 +1,258 @@
+package clockface
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/**
+WritePNG rasterises the clock face showing the time t in the theme given, size pixels square.
+The face is drawn from the same geometry as the SVG, TransformHand placing every hand and tick,
+and every edge is anti-aliased by how much of the pixel it covers.
+
+The standard library has no fonts, so numerals and the digital readout are drawn with strokes
+of a font of our own that only knows the digits, the colon and the roman I, V and X. A logo that is
+text is left out, a logo image is only drawn in the SVG.
+*/
+func WritePNG(writer io.Writer, theme Theme, t time.Time, size int) error {
+	if size < 16 {
+		return fmt.Errorf("a clock needs to be at least 16 pixels across, not %d", size)
+	}
+	c := newCanvas(size, float64(size)/(ClockCentreX*2))
+
+	c.disc(Point{ClockCentreX, ClockCentreY}, ClockR, parseColor(theme.Face))
+	c.ring(Point{ClockCentreX, ClockCentreY}, ClockR, theme.BezelWidth, parseColor(theme.Bezel))
+
+	for minute := 0; minute < 60; minute++ {
+		ticks := theme.MinuteTicks
+		if minute%5 == 0 {
+			ticks = theme.HourTicks
+		}
+		if ticks.Length > 0 {
+			outer := TransformHand(ClockHandDef{float64(minute), ClockR - theme.BezelWidth, "", TimeInHalfClock})
+			inner := TransformHand(ClockHandDef{float64(minute), ClockR - theme.BezelWidth - ticks.Length, "", TimeInHalfClock})
+			c.line(inner, outer, ticks.Width, parseColor(ticks.Color))
+		}
+	}
+
+	if theme.Numerals != NoNumerals {
+		radius := ClockR - theme.BezelWidth - math.Max(theme.HourTicks.Length, theme.MinuteTicks.Length) - theme.NumeralSize*0.8
+		for hour := 0; hour < 12; hour++ {
+			numeral := strconv.Itoa(hour)
+			if hour == 0 {
+				numeral = "12"
+			}
+			if theme.Numerals == RomanNumerals {
+				numeral = romanNumerals[hour]
+			}
+			at := TransformHand(ClockHandDef{float64(hour), radius, "", HoursInHalfClock})
+			c.text(numeral, at, theme.NumeralSize, parseColor(theme.NumeralColor))
+		}
+	}
+	if theme.Digital {
+		// the text is drawn around the point given, the SVG sits it on the baseline
+		c.text(t.Format("15:04:05"), Point{ClockCentreX, digitalBaselineY - textHeight(digitalSize)/2}, digitalSize, parseColor(theme.DigitalColor))
+	}
+
+	face := faceAt(theme, t, false)
+	c.hand(theme.HourHand, face.hands[HourHandID])
+	c.hand(theme.MinuteHand, face.hands[MinuteHandID])
+	c.hand(theme.SecondHand, face.hands[SecondHandID])
+
+	return png.Encode(writer, c.img)
+}
+
+// canvas draws in the units of the clock, scale being the pixels to a unit.
+type canvas struct {
+	img   *image.NRGBA
+	scale float64
+}
+
+func newCanvas(size int, scale float64) *canvas {
+	return &canvas{image.NewNRGBA(image.Rect(0, 0, size, size)), scale}
+}
+
+/**
+fill paints every pixel of the box from min to max by how much of it the shape covers.
+distance is how far a point, in pixels, is outside the shape, negative inside it, so a pixel
+whose centre is on the edge is half covered.
+*/
+func (c *canvas) fill(min, max Point, colour color.NRGBA, distance func(x, y float64) float64) {
+	if colour.A == 0 {
+		return
+	}
+	bounds := c.img.Bounds()
+	x0, y0 := int(math.Floor(min.X*c.scale))-1, int(math.Floor(min.Y*c.scale))-1
+	x1, y1 := int(math.Ceil(max.X*c.scale))+1, int(math.Ceil(max.Y*c.scale))+1
+	for y := maxInt(y0, bounds.Min.Y); y < minInt(y1, bounds.Max.Y); y++ {
+		for x := maxInt(x0, bounds.Min.X); x < minInt(x1, bounds.Max.X); x++ {
+			coverage := math.Max(0, math.Min(1, 0.5-distance(float64(x)+0.5, float64(y)+0.5)))
+			if coverage > 0 {
+				c.blend(x, y, colour, coverage)
+			}
+		}
+	}
+}
+
+// blend lays the colour over the pixel, as much of it as the coverage.
+func (c *canvas) blend(x, y int, colour color.NRGBA, coverage float64) {
+	under := c.img.NRGBAAt(x, y)
+	alpha := coverage * float64(colour.A) / 255
+	underAlpha := float64(under.A) / 255
+	outAlpha := alpha + underAlpha*(1-alpha)
+	if outAlpha == 0 {
+		return
+	}
+	mix := func(over, below uint8) uint8 {
+		return uint8(math.Round((float64(over)*alpha + float64(below)*underAlpha*(1-alpha)) / outAlpha))
+	}
+	c.img.SetNRGBA(x, y, color.NRGBA{mix(colour.R, under.R), mix(colour.G, under.G), mix(colour.B, under.B), uint8(math.Round(outAlpha * 255))})
+}
+
+func (c *canvas) disc(centre Point, r float64, colour color.NRGBA) {
+	c.fill(Point{centre.X - r, centre.Y - r}, Point{centre.X + r, centre.Y + r}, colour, func(x, y float64) float64 {
+		return math.Hypot(x-centre.X*c.scale, y-centre.Y*c.scale) - r*c.scale
+	})
+}
+
+// ring is the stroke of a circle, width wide and centred on its radius as an SVG stroke is.
+func (c *canvas) ring(centre Point, r, width float64, colour color.NRGBA) {
+	if width <= 0 {
+		return
+	}
+	outer := r + width/2
+	c.fill(Point{centre.X - outer, centre.Y - outer}, Point{centre.X + outer, centre.Y + outer}, colour, func(x, y float64) float64 {
+		return math.Abs(math.Hypot(x-centre.X*c.scale, y-centre.Y*c.scale)-r*c.scale) - width/2*c.scale
+	})
+}
+
+// line is a stroke from a to b, width wide and with square ends as SVG draws them by default.
+func (c *canvas) line(a, b Point, width float64, colour color.NRGBA) {
+	length := math.Hypot(b.X-a.X, b.Y-a.Y)
+	if length == 0 || width <= 0 {
+		return
+	}
+	along := Point{(b.X - a.X) / length, (b.Y - a.Y) / length}
+	half := width / 2
+	c.fill(Point{math.Min(a.X, b.X) - half, math.Min(a.Y, b.Y) - half}, Point{math.Max(a.X, b.X) + half, math.Max(a.Y, b.Y) + half}, colour,
+		func(x, y float64) float64 {
+			px, py := x/c.scale-a.X, y/c.scale-a.Y
+			t := px*along.X + py*along.Y
+			across := math.Abs(-px*along.Y + py*along.X)
+			return math.Max(across-half, math.Max(-t, t-length)) * c.scale
+		})
+}
+
+// polygon fills a convex polygon, its points in order either way round.
+func (c *canvas) polygon(points []Point, colour color.NRGBA) {
+	min, max := points[0], points[0]
+	area := 0.0
+	for i, p := range points {
+		min = Point{math.Min(min.X, p.X), math.Min(min.Y, p.Y)}
+		max = Point{math.Max(max.X, p.X), math.Max(max.Y, p.Y)}
+		next := points[(i+1)%len(points)]
+		area += p.X*next.Y - next.X*p.Y
+	}
+	if area == 0 {
+		return
+	}
+	c.fill(min, max, colour, func(x, y float64) float64 {
+		// the distance outside the furthest edge, every edge seen from inside the polygon
+		outside := math.Inf(-1)
+		for i, a := range points {
+			b := points[(i+1)%len(points)]
+			length := math.Hypot(b.X-a.X, b.Y-a.Y)
+			if length == 0 {
+				continue
+			}
+			d := ((b.X-a.X)*(y/c.scale-a.Y) - (b.Y-a.Y)*(x/c.scale-a.X)) / length
+			if area > 0 {
+				d = -d
+			}
+			outside = math.Max(outside, d)
+		}
+		return outside * c.scale
+	})
+}
+
+func (c *canvas) hand(style HandStyle, points []Point) {
+	if style.Shape == LineHand {
+		c.line(points[0], points[1], style.Width, parseColor(style.Color))
+		return
+	}
+	c.polygon(points, parseColor(style.Color))
+}
+
+/**
+strokeFont draws each character it knows as strokes in a box one unit wide and two high,
+from the top left corner, the way a seven segment display draws its digits.
+*/
+var strokeFont = map[rune][][4]float64{
+	'0': {{0, 0, 1, 0}, {1, 0, 1, 2}, {1, 2, 0, 2}, {0, 2, 0, 0}},
+	'1': {{0.5, 0, 0.5, 2}},
+	'2': {{0, 0, 1, 0}, {1, 0, 1, 1}, {1, 1, 0, 1}, {0, 1, 0, 2}, {0, 2, 1, 2}},
+	'3': {{0, 0, 1, 0}, {1, 0, 1, 2}, {0, 1, 1, 1}, {0, 2, 1, 2}},
+	'4': {{0, 0, 0, 1}, {0, 1, 1, 1}, {1, 0, 1, 2}},
+	'5': {{1, 0, 0, 0}, {0, 0, 0, 1}, {0, 1, 1, 1}, {1, 1, 1, 2}, {1, 2, 0, 2}},
+	'6': {{1, 0, 0, 0}, {0, 0, 0, 2}, {0, 2, 1, 2}, {1, 2, 1, 1}, {1, 1, 0, 1}},
+	'7': {{0, 0, 1, 0}, {1, 0, 1, 2}},
+	'8': {{0, 0, 1, 0}, {1, 0, 1, 2}, {1, 2, 0, 2}, {0, 2, 0, 0}, {0, 1, 1, 1}},
+	'9': {{1, 1, 0, 1}, {0, 1, 0, 0}, {0, 0, 1, 0}, {1, 0, 1, 2}, {1, 2, 0, 2}},
+	':': {{0.5, 0.55, 0.5, 0.65}, {0.5, 1.35, 0.5, 1.45}},
+	'I': {{0.5, 0, 0.5, 2}},
+	'V': {{0, 0, 0.5, 2}, {0.5, 2, 1, 0}},
+	'X': {{0, 0, 1, 2}, {1, 0, 0, 2}},
+}
+
+// text draws the text centred on at, size being the height of a character as a font size would be.
+func (c *canvas) text(text string, at Point, size float64, colour color.NRGBA) {
+	height := textHeight(size)
+	width, gap := height/2, height/4
+	left := at.X - (float64(len(text))*(width+gap)-gap)/2
+	top := at.Y - height/2
+	for i, char := range strings.ToUpper(text) {
+		x := left + float64(i)*(width+gap)
+		for _, stroke := range strokeFont[char] {
+			c.line(Point{x + stroke[0]*width, top + stroke[1]*height/2}, Point{x + stroke[2]*width, top + stroke[3]*height/2}, height/8, colour)
+		}
+	}
+}
+
+// textHeight is how tall the stroked capitals and digits of a font size are, as tall as the SVG's are above the baseline.
+func textHeight(size float64) float64 {
+	return size * 0.7
+}
+
+// parseColor reads the #rgb and #rrggbb colours themes are given in, anything else is not drawn.
+func parseColor(value string) color.NRGBA {
+	hex := strings.TrimPrefix(strings.TrimSpace(value), "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 6 || err != nil {
+		return color.NRGBA{}
+	}
+	return color.NRGBA{uint8(rgb >> 16), uint8(rgb >> 8), uint8(rgb), 255}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
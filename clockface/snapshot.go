@@ -0,0 +1,57 @@
+package clockface
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// The formats a snapshot of the clock can be written in, named by their file extensions.
+const (
+	SVGFormat   = "svg"
+	PNGFormat   = "png"
+	ASCIIFormat = "txt"
+)
+
+// the size of the faces written as snapshots, in pixels for a PNG and in rows for the text
+const (
+	SnapshotPixels = 400
+	SnapshotRows   = 21
+)
+
+/**
+WriteSnapshots writes the clock face at the time t to a file in dir for every format asked for,
+named after the time in it, clock-20260314-100930.png for instance, and returns the paths written.
+The time is shown as it is in t's location, so a report can have its clocks in any zone.
+*/
+func WriteSnapshots(dir string, theme Theme, t time.Time, formats ...string) ([]string, error) {
+	var paths []string
+	for _, format := range formats {
+		format = strings.ToLower(strings.TrimSpace(format))
+		face := bytes.Buffer{}
+		switch format {
+		case SVGFormat:
+			WriteThemedSVG(&face, theme, t)
+		case PNGFormat:
+			if err := WritePNG(&face, theme, t, SnapshotPixels); err != nil {
+				return paths, err
+			}
+		case ASCIIFormat:
+			if err := WriteASCII(&face, t, SnapshotRows); err != nil {
+				return paths, err
+			}
+		default:
+			return paths, fmt.Errorf("unknown snapshot format %q, expecting %s, %s or %s", format, SVGFormat, PNGFormat, ASCIIFormat)
+		}
+
+		path := filepath.Join(dir, fmt.Sprintf("clock-%s.%s", t.Format("20060102-150405"), format))
+		if err := os.WriteFile(path, face.Bytes(), 0644); err != nil {
+			return paths, fmt.Errorf("problem writing snapshot %s, %v", path, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
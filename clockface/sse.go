@@ -0,0 +1,97 @@
+package clockface
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sseRetry is how long a browser waits to reconnect to an event stream that has gone, in milliseconds.
+const sseRetry = 1000
+
+/**
+ServeSSE streams the clock as Server-Sent Events, for browsers that cannot get a websocket through.
+The clock is asked for just as it is over the websocket, with ?tz=, ?theme= and ?frames=, and its
+frames are the same, each an event named svg or delta:
+
+	id: 1760781600000000000
+	event: svg
+	data: <?xml version="1.0" encoding="UTF-8" standalone="no"?>
+	data: ...
+
+The id of a frame is the time on its face in nanoseconds since 1970. When the browser reconnects it
+sends the id of the last frame it had as Last-Event-ID, so a stream of delta frames can carry on from
+the face the browser already has instead of starting again with the whole of it.
+An event stream only goes one way, so the countdown, the stopwatch and alarms need the websocket.
+*/
+func (cs *ClockServer) ServeSSE(w http.ResponseWriter, req *http.Request) {
+	zone, theme, err := ClockRequest(req, cs.themes)
+	delta := false
+	if err == nil {
+		delta, err = deltaRequest(req)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetry)
+	flusher.Flush()
+
+	client := cs.broadcaster.subscribe(zone, theme, delta, lastEventTime(req))
+	defer cs.broadcaster.unsubscribe(client)
+	controller := http.NewResponseController(w)
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case <-client.dropped:
+			return
+		case frame := <-client.frames:
+			if frame.message == nil {
+				continue
+			}
+			controller.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := writeSSE(w, frame); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// lastEventTime is the time on the face of the last frame the browser had, the zero time when it is starting afresh.
+func lastEventTime(req *http.Request) time.Time {
+	nanos, err := strconv.ParseInt(req.Header.Get("Last-Event-ID"), 10, 64)
+	if err != nil || nanos <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// writeSSE writes the frame as an event, every line of it a line of data.
+func writeSSE(w io.Writer, frame clockFrame) error {
+	event := SVGFrames
+	if frame.delta {
+		event = DeltaFrames
+	}
+	sse := &strings.Builder{}
+	fmt.Fprintf(sse, "id: %d\nevent: %s\n", frame.at.UnixNano(), event)
+	for _, line := range strings.Split(strings.TrimRight(string(frame.message), "\n"), "\n") {
+		fmt.Fprintf(sse, "data: %s\n", line)
+	}
+	sse.WriteString("\n")
+	_, err := io.WriteString(w, sse.String())
+	return err
+}
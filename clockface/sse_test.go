@@ -0,0 +1,167 @@
+package clockface_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ydsxiong/go-playground/clockface"
+)
+
+type sseEvent struct {
+	id, event, data string
+}
+
+func TestServeSSE(t *testing.T) {
+	t.Run("streams the face as events of SVG", func(t *testing.T) {
+		events := startSSE(t, "?theme=station", "")
+		for i := 0; i < 3; i++ {
+			event := nextSSE(t, events)
+			if event.event != "svg" || !strings.HasPrefix(event.data, "<?xml") || !strings.HasSuffix(event.data, "</svg>") {
+				t.Errorf("expected an event of the whole face, got %s %.40q", event.event, event.data)
+			}
+			if event.id == "" {
+				t.Errorf("expected the event to have an id")
+			}
+		}
+	})
+
+	t.Run("streams delta frames after the first", func(t *testing.T) {
+		events := startSSE(t, "?frames=delta", "")
+		if first := nextSSE(t, events); first.event != "svg" {
+			t.Fatalf("expected the whole face first, got a %s event", first.event)
+		}
+		assertDeltaEvent(t, nextSSE(t, events))
+	})
+
+	t.Run("carries on from the last event when reconnecting", func(t *testing.T) {
+		events := startSSE(t, "?frames=delta", "")
+		first := nextSSE(t, events)
+
+		resumed := startSSE(t, "?frames=delta", first.id)
+		assertDeltaEvent(t, nextSSE(t, resumed))
+	})
+
+	t.Run("starts afresh from an id it does not know", func(t *testing.T) {
+		events := startSSE(t, "?frames=delta", "the-last-one")
+		if first := nextSSE(t, events); first.event != "svg" {
+			t.Errorf("expected the whole face first, got a %s event", first.event)
+		}
+	})
+
+	t.Run("rejects an unknown zone, theme or frames", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(clockface.NewClockServer(clockface.NewThemes(), tick).ServeSSE))
+		defer server.Close()
+		for _, query := range []string{"?tz=Nowhere/Special", "?theme=plaid", "?frames=gif"} {
+			response, err := http.Get(server.URL + query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			response.Body.Close()
+			if response.StatusCode != http.StatusBadRequest {
+				t.Errorf("%s: got status %d, want %d", query, response.StatusCode, http.StatusBadRequest)
+			}
+		}
+	})
+
+	t.Run("stops streaming when the browser goes", func(t *testing.T) {
+		clock := clockface.NewClockServer(clockface.NewThemes(), tick)
+		server := httptest.NewServer(http.HandlerFunc(clock.ServeSSE))
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		response, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer response.Body.Close()
+		nextSSE(t, bufio.NewReader(response.Body))
+		assertClients(t, clock, 1)
+
+		cancel()
+		assertClients(t, clock, 0)
+	})
+}
+
+// startSSE opens an event stream of the clock, as a browser reconnecting after the event lastID when there is one.
+func startSSE(t *testing.T, query, lastID string) *bufio.Reader {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(clockface.NewClockServer(clockface.NewThemes(), tick).ServeSSE))
+	t.Cleanup(server.Close)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+query, nil)
+	req.Header.Set("Accept", "text/event-stream")
+	if lastID != "" {
+		req.Header.Set("Last-Event-ID", lastID)
+	}
+	response, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("could not open an event stream on %s %v", server.URL, err)
+	}
+	t.Cleanup(func() { response.Body.Close() })
+	if got := response.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("got content type %q, want text/event-stream", got)
+	}
+	return bufio.NewReader(response.Body)
+}
+
+// nextSSE reads the next event of the stream, skipping anything that is not one.
+func nextSSE(t *testing.T, events *bufio.Reader) sseEvent {
+	t.Helper()
+	read := make(chan sseEvent, 1)
+	go func() {
+		var event sseEvent
+		var data []string
+		for {
+			line, err := events.ReadString('\n')
+			if err != nil {
+				close(read)
+				return
+			}
+			line = strings.TrimSuffix(line, "\n")
+			switch {
+			case line == "" && event.event != "":
+				event.data = strings.Join(data, "\n")
+				read <- event
+				return
+			case strings.HasPrefix(line, "id: "):
+				event.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				event.event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				data = append(data, strings.TrimPrefix(line, "data: "))
+			}
+		}
+	}()
+
+	select {
+	case event, ok := <-read:
+		if !ok {
+			t.Fatal("the event stream ended")
+		}
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("gave up waiting for an event")
+	}
+	return sseEvent{}
+}
+
+func assertDeltaEvent(t *testing.T, event sseEvent) {
+	t.Helper()
+	if event.event != "delta" {
+		t.Fatalf("expected a delta event, got a %s event", event.event)
+	}
+	var frame clockface.DeltaFrame
+	if err := json.Unmarshal([]byte(event.data), &frame); err != nil {
+		t.Fatalf("expected a delta frame, got %.40q, %v", event.data, err)
+	}
+	if _, ok := frame.Hands[clockface.SecondHandID]; !ok {
+		t.Errorf("expected the second hand to have moved, got %+v", frame)
+	}
+}
@@ -0,0 +1,363 @@
+package clockface
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The shapes a hand can be drawn in.
+const (
+	LineHand    = "line"
+	TaperedHand = "tapered"
+)
+
+// The numerals a face can have around it, none at all by default.
+const (
+	NoNumerals     = ""
+	ArabicNumerals = "arabic"
+	RomanNumerals  = "roman"
+)
+
+const ClassicThemeName = "classic"
+
+// TickStyle is how the tick marks of the hours or of the minutes are drawn, there are none if Length is 0.
+type TickStyle struct {
+	Length float64
+	Width  float64
+	Color  string
+}
+
+/**
+HandStyle is how a hand is drawn: a line Width wide, or a tapered polygon Width wide
+where it meets the centre and coming to a point at its tip. Tail is how far the hand
+sticks out on the other side of the centre.
+*/
+type HandStyle struct {
+	Shape  string
+	Length float64
+	Width  float64
+	Tail   float64
+	Color  string
+}
+
+/**
+Theme is the look of a clock face. Everything is measured in the units the hands are,
+on a face of radius ClockR around the centre (ClockCentreX, ClockCentreY).
+The logo is a line of text under the 12, or an image if LogoImage is the URL of one,
+and the digital readout shows the time as text above the 6.
+*/
+type Theme struct {
+	Name string
+
+	Face       string
+	Bezel      string
+	BezelWidth float64
+
+	HourTicks   TickStyle
+	MinuteTicks TickStyle
+
+	Numerals     string
+	NumeralColor string
+	NumeralSize  float64
+
+	HourHand   HandStyle
+	MinuteHand HandStyle
+	SecondHand HandStyle
+
+	Logo      string
+	LogoImage string
+	LogoColor string
+
+	Digital      bool
+	DigitalColor string
+}
+
+// ClassicTheme is the face WriteSVG has always drawn, it is what themes loaded from JSON start from.
+var ClassicTheme = Theme{
+	Name:       ClassicThemeName,
+	Face:       "#fff",
+	Bezel:      "#000",
+	BezelWidth: 3,
+	HourHand:   HandStyle{Shape: LineHand, Length: HourHandLength, Width: 2, Color: HourHandColor},
+	MinuteHand: HandStyle{Shape: LineHand, Length: MinuteHandLength, Width: 2, Color: MinuteHandColor},
+	SecondHand: HandStyle{Shape: LineHand, Length: SecondHandLength, Width: 2, Color: SecondHandColor},
+}
+
+// BuiltInThemes returns the themes every server has, the classic face among them.
+func BuiltInThemes() []Theme {
+	return []Theme{
+		ClassicTheme,
+		{
+			Name:         "station",
+			Face:         "#fff",
+			Bezel:        "#222",
+			BezelWidth:   2.5,
+			HourTicks:    TickStyle{Length: 6, Width: 2, Color: "#222"},
+			MinuteTicks:  TickStyle{Length: 2.5, Width: 0.6, Color: "#222"},
+			Numerals:     ArabicNumerals,
+			NumeralColor: "#222",
+			NumeralSize:  7,
+			HourHand:     HandStyle{Shape: TaperedHand, Length: 26, Width: 4.5, Tail: 5, Color: "#222"},
+			MinuteHand:   HandStyle{Shape: TaperedHand, Length: 40, Width: 3.5, Tail: 6, Color: "#222"},
+			SecondHand:   HandStyle{Shape: LineHand, Length: 42, Width: 0.8, Tail: 10, Color: "#d00"},
+		},
+		{
+			Name:         "roman",
+			Face:         "#fbf5e6",
+			Bezel:        "#a8842c",
+			BezelWidth:   4,
+			HourTicks:    TickStyle{Length: 4, Width: 1.2, Color: "#5a4516"},
+			Numerals:     RomanNumerals,
+			NumeralColor: "#5a4516",
+			NumeralSize:  6,
+			HourHand:     HandStyle{Shape: TaperedHand, Length: 24, Width: 3.5, Color: "#5a4516"},
+			MinuteHand:   HandStyle{Shape: TaperedHand, Length: 36, Width: 2.5, Color: "#5a4516"},
+			SecondHand:   HandStyle{Shape: LineHand, Length: 38, Width: 0.5, Tail: 8, Color: "#a8842c"},
+			Logo:         "GO PLAYGROUND",
+			LogoColor:    "#a8842c",
+		},
+		{
+			Name:         "night",
+			Face:         "#10142a",
+			Bezel:        "#5b6bbf",
+			BezelWidth:   2,
+			HourTicks:    TickStyle{Length: 5, Width: 1.5, Color: "#cfd6ff"},
+			MinuteTicks:  TickStyle{Length: 2, Width: 0.5, Color: "#5b6bbf"},
+			HourHand:     HandStyle{Shape: TaperedHand, Length: 26, Width: 4, Color: "#cfd6ff"},
+			MinuteHand:   HandStyle{Shape: TaperedHand, Length: 38, Width: 3, Color: "#cfd6ff"},
+			SecondHand:   HandStyle{Shape: TaperedHand, Length: 42, Width: 1.5, Tail: 8, Color: "#ffb347"},
+			Digital:      true,
+			DigitalColor: "#ffb347",
+		},
+	}
+}
+
+func (t Theme) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("a theme needs a name")
+	}
+	if t.Numerals != NoNumerals && t.Numerals != ArabicNumerals && t.Numerals != RomanNumerals {
+		return fmt.Errorf("theme %s has unknown numerals %q, expecting %s or %s", t.Name, t.Numerals, ArabicNumerals, RomanNumerals)
+	}
+	for _, hand := range []HandStyle{t.HourHand, t.MinuteHand, t.SecondHand} {
+		if hand.Shape != LineHand && hand.Shape != TaperedHand {
+			return fmt.Errorf("theme %s has a hand of unknown shape %q, expecting %s or %s", t.Name, hand.Shape, LineHand, TaperedHand)
+		}
+		if hand.Length <= 0 || hand.Length > ClockR {
+			return fmt.Errorf("theme %s has a hand %v long, it has to fit on the face", t.Name, hand.Length)
+		}
+	}
+	return nil
+}
+
+// Themes are the themes a clock can be asked for by name, the built-in ones along with any loaded.
+type Themes struct {
+	themes map[string]Theme
+}
+
+func NewThemes() *Themes {
+	themes := &Themes{themes: map[string]Theme{}}
+	for _, theme := range BuiltInThemes() {
+		themes.themes[theme.Name] = theme
+	}
+	return themes
+}
+
+// Add adds a theme, or replaces the one of the same name.
+func (ts *Themes) Add(theme Theme) error {
+	if err := theme.Validate(); err != nil {
+		return err
+	}
+	ts.themes[theme.Name] = theme
+	return nil
+}
+
+// Get returns the theme of that name, the classic theme if no name is given.
+func (ts *Themes) Get(name string) (Theme, error) {
+	if name == "" {
+		name = ClassicThemeName
+	}
+	theme, ok := ts.themes[name]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme %q, expecting one of %s", name, strings.Join(ts.Names(), ", "))
+	}
+	return theme, nil
+}
+
+func (ts *Themes) Names() []string {
+	names := make([]string, 0, len(ts.themes))
+	for name := range ts.themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+/**
+Load adds the themes in a JSON array. Every theme starts from the classic one,
+so a theme only has to give what it changes: [{"Name": "red", "Face": "#fdd"}].
+*/
+func (ts *Themes) Load(in io.Reader) error {
+	var raw []json.RawMessage
+	if err := json.NewDecoder(in).Decode(&raw); err != nil {
+		return fmt.Errorf("problem parsing themes, %v", err)
+	}
+	for i, data := range raw {
+		theme := ClassicTheme
+		theme.Name = ""
+		if err := json.Unmarshal(data, &theme); err != nil {
+			return fmt.Errorf("problem parsing theme %d, %v", i+1, err)
+		}
+		if err := ts.Add(theme); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const themedSVGStart = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<!DOCTYPE svg PUBLIC "-//W3C//DTD SVG 1.1//EN" "http://www.w3.org/Graphics/SVG/1.1/DTD/svg11.dtd">
+<svg xmlns="http://www.w3.org/2000/svg"
+     width="100%%"
+     height="100%%"
+     viewBox="0 0 %.0f %.0f"
+     version="2.0">`
+
+var romanNumerals = []string{"XII", "I", "II", "III", "IV", "V", "VI", "VII", "VIII", "IX", "X", "XI"}
+
+// The ids of the parts of a themed clock face that move, for a browser to find them by.
+const (
+	HourHandID   = "hour-hand"
+	MinuteHandID = "minute-hand"
+	SecondHandID = "second-hand"
+	DigitalID    = "digital"
+)
+
+// The digital readout sits on the face at the same place and size whichever format the face is drawn in.
+const (
+	digitalBaselineY = ClockCentreY + ClockR*0.45
+	digitalSize      = ClockR / 7
+)
+
+/**
+WriteThemedSVG draws the clock face showing the time t in the theme given. The hands
+are placed by TransformHand just as WriteSVG places them, the theme only changes how they look.
+*/
+func WriteThemedSVG(writer io.Writer, theme Theme, t time.Time) {
+	writeThemedSVG(writer, theme, faceAt(theme, t, false))
+}
+
+// writeThemedSVG draws the clock face in the theme with its hands and digital readout where the face has them.
+func writeThemedSVG(writer io.Writer, theme Theme, face face) {
+	svg := &strings.Builder{}
+	fmt.Fprintf(svg, themedSVGStart, ClockCentreX*2, ClockCentreY*2)
+	fmt.Fprintf(svg, `<circle cx="%.5f" cy="%.5f" r="%.5f" style="fill:%s;stroke:%s;stroke-width:%.5fpx;"/>`,
+		ClockCentreX, ClockCentreY, ClockR, attr(theme.Face), attr(theme.Bezel), theme.BezelWidth)
+
+	for minute := 0; minute < 60; minute++ {
+		ticks := theme.MinuteTicks
+		if minute%5 == 0 {
+			ticks = theme.HourTicks
+		}
+		if ticks.Length > 0 {
+			outer := TransformHand(ClockHandDef{float64(minute), ClockR - theme.BezelWidth, "", TimeInHalfClock})
+			inner := TransformHand(ClockHandDef{float64(minute), ClockR - theme.BezelWidth - ticks.Length, "", TimeInHalfClock})
+			fmt.Fprintf(svg, `<line x1="%.5f" y1="%.5f" x2="%.5f" y2="%.5f" style="stroke:%s;stroke-width:%.5fpx;"/>`,
+				inner.X, inner.Y, outer.X, outer.Y, attr(ticks.Color), ticks.Width)
+		}
+	}
+
+	if theme.Numerals != NoNumerals {
+		// the numerals sit inside the longest of the ticks
+		radius := ClockR - theme.BezelWidth - math.Max(theme.HourTicks.Length, theme.MinuteTicks.Length) - theme.NumeralSize*0.8
+		for hour := 0; hour < 12; hour++ {
+			numeral := fmt.Sprint(hour)
+			if hour == 0 {
+				numeral = "12"
+			}
+			if theme.Numerals == RomanNumerals {
+				numeral = romanNumerals[hour]
+			}
+			at := TransformHand(ClockHandDef{float64(hour), radius, "", HoursInHalfClock})
+			fmt.Fprintf(svg, `<text x="%.5f" y="%.5f" text-anchor="middle" dominant-baseline="central" style="fill:%s;font-family:sans-serif;font-size:%.5fpx;">%s</text>`,
+				at.X, at.Y, attr(theme.NumeralColor), theme.NumeralSize, numeral)
+		}
+	}
+
+	switch {
+	case theme.LogoImage != "":
+		fmt.Fprintf(svg, `<image href="%s" x="%.5f" y="%.5f" width="%.5f" height="%.5f"/>`,
+			attr(theme.LogoImage), ClockCentreX-ClockR/4, ClockCentreY-ClockR/2, ClockR/2, ClockR/4)
+	case theme.Logo != "":
+		fmt.Fprintf(svg, `<text x="%.5f" y="%.5f" text-anchor="middle" style="fill:%s;font-family:serif;font-size:%.5fpx;">%s</text>`,
+			ClockCentreX, ClockCentreY-ClockR*0.35, attr(theme.LogoColor), ClockR/14, html.EscapeString(theme.Logo))
+	}
+	if theme.Digital {
+		fmt.Fprintf(svg, `<text id="%s" x="%.5f" y="%.5f" text-anchor="middle" style="fill:%s;font-family:monospace;font-size:%.5fpx;">%s</text>`,
+			DigitalID, ClockCentreX, digitalBaselineY, attr(theme.DigitalColor), digitalSize, face.digital)
+	}
+
+	writeHand(svg, HourHandID, theme.HourHand, face.hands[HourHandID])
+	writeHand(svg, MinuteHandID, theme.MinuteHand, face.hands[MinuteHandID])
+	writeHand(svg, SecondHandID, theme.SecondHand, face.hands[SecondHandID])
+
+	svg.WriteString(svgEnd)
+	io.WriteString(writer, svg.String())
+}
+
+// writeHand draws a hand of the style given through its points.
+func writeHand(svg io.Writer, id string, style HandStyle, points []Point) {
+	if style.Shape == LineHand {
+		fmt.Fprintf(svg, `<line id="%s" x1="%.5f" y1="%.5f" x2="%.5f" y2="%.5f" style="fill:none;stroke:%s;stroke-width:%.5fpx;"/>`,
+			id, points[0].X, points[0].Y, points[1].X, points[1].Y, attr(style.Color), style.Width)
+		return
+	}
+	fmt.Fprintf(svg, `<polygon id="%s" points="%s" style="fill:%s;stroke:none;"/>`, id, formatPoints(points, 5), attr(style.Color))
+}
+
+/**
+handPoints are the points a hand of the style given is drawn through when it points where the definition
+points, the tail and the tip of a line, or the corners of a tapered hand going round from its tail to its tip and back.
+*/
+func handPoints(style HandStyle, hand ClockHandDef) []Point {
+	hand.HandLength = style.Length
+	tip := TransformHand(hand)
+	hand.HandLength = -style.Tail
+	tail := TransformHand(hand)
+
+	if style.Shape == LineHand {
+		return []Point{tail, tip}
+	}
+
+	// across is a unit vector at right angles to the hand, the hand is widest where it crosses the centre
+	length := math.Hypot(tip.X-ClockCentreX, tip.Y-ClockCentreY)
+	across := Point{-(tip.Y - ClockCentreY) / length, (tip.X - ClockCentreX) / length}
+	half := style.Width / 2
+	return []Point{
+		{tail.X + across.X*half/2, tail.Y + across.Y*half/2},
+		{ClockCentreX + across.X*half, ClockCentreY + across.Y*half},
+		tip,
+		{ClockCentreX - across.X*half, ClockCentreY - across.Y*half},
+		{tail.X - across.X*half/2, tail.Y - across.Y*half/2},
+	}
+}
+
+// formatPoints writes the points as the points attribute of an SVG polygon, to the decimal places given.
+func formatPoints(points []Point, places int) string {
+	formatted := make([]string, len(points))
+	for i, p := range points {
+		formatted[i] = fmt.Sprintf("%.*f,%.*f", places, p.X, places, p.Y)
+	}
+	return strings.Join(formatted, " ")
+}
+
+// attr escapes a value of the theme for an attribute, themes can come from anywhere.
+func attr(value string) string {
+	return html.EscapeString(value)
+}
@@ -0,0 +1,107 @@
+package clockface_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ydsxiong/go-playground/clockface"
+)
+
+// go test -update writes the golden files afresh, after a change to how a theme looks
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestThemeGoldenFiles(t *testing.T) {
+	at := time.Date(2026, time.March, 14, 10, 9, 30, 0, time.UTC)
+
+	for _, theme := range clockface.BuiltInThemes() {
+		t.Run(theme.Name, func(t *testing.T) {
+			got := bytes.Buffer{}
+			clockface.WriteThemedSVG(&got, theme, at)
+
+			golden := filepath.Join("testdata", theme.Name+".svg")
+			if *update {
+				if err := os.WriteFile(golden, got.Bytes(), 0644); err != nil {
+					t.Fatalf("problem writing %s, %v", golden, err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("problem reading %s, run go test -update to write it, %v", golden, err)
+			}
+			if !bytes.Equal(got.Bytes(), want) {
+				t.Errorf("the %s theme does not match %s, run go test -update if the change is intended\ngot  %s\nwant %s", theme.Name, golden, got.String(), want)
+			}
+
+			svg := clockface.SVG{}
+			if err := xml.Unmarshal(got.Bytes(), &svg); err != nil {
+				t.Errorf("the %s theme is not valid XML, %v", theme.Name, err)
+			}
+		})
+	}
+}
+
+func TestThemes(t *testing.T) {
+	t.Run("the classic theme draws the hands where WriteSVG does", func(t *testing.T) {
+		b := bytes.Buffer{}
+		clockface.WriteThemedSVG(&b, clockface.ClassicTheme, simpletime(0, 30, 0))
+
+		svg := clockface.SVG{}
+		xml.Unmarshal(b.Bytes(), &svg)
+
+		hand := clockface.Line{clockface.ClockCentreX, clockface.ClockCentreY, clockface.ClockCentreX, clockface.ClockCentreY + clockface.MinuteHandLength}
+		if !containsLine(hand, svg.Line) {
+			t.Errorf("expected the minute hand %+v, in %+v", hand, svg.Line)
+		}
+	})
+
+	t.Run("themes loaded from JSON start from the classic theme", func(t *testing.T) {
+		themes := clockface.NewThemes()
+		err := themes.Load(strings.NewReader(`[{"Name": "brand", "Face": "#fdd", "Logo": "<ACME>", "Numerals": "roman"}]`))
+		if err != nil {
+			t.Fatalf("problem loading themes, %v", err)
+		}
+
+		theme, err := themes.Get("brand")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if theme.Face != "#fdd" || theme.HourHand != clockface.ClassicTheme.HourHand {
+			t.Errorf("got %+v, expected the face changed and the rest classic", theme)
+		}
+
+		b := bytes.Buffer{}
+		clockface.WriteThemedSVG(&b, theme, simpletime(3, 0, 0))
+		for _, want := range []string{"&lt;ACME&gt;", ">XII<", ">IX<"} {
+			if !strings.Contains(b.String(), want) {
+				t.Errorf("expected %s in %s", want, b.String())
+			}
+		}
+	})
+
+	t.Run("it rejects themes it cannot draw", func(t *testing.T) {
+		themes := clockface.NewThemes()
+		for _, bad := range []string{
+			`[{"Face": "#fdd"}]`,
+			`[{"Name": "bad", "Numerals": "klingon"}]`,
+			`[{"Name": "bad", "HourHand": {"Shape": "wavy"}}]`,
+			`[{"Name": "bad", "SecondHand": {"Length": 80}}]`,
+			`{"Name": "bad"}`,
+		} {
+			if err := themes.Load(strings.NewReader(bad)); err == nil {
+				t.Errorf("expected an error loading %s", bad)
+			}
+		}
+		if _, err := themes.Get("bad"); err == nil {
+			t.Error("expected no bad theme to have been added")
+		}
+		if theme, _ := themes.Get(""); theme.Name != clockface.ClassicThemeName {
+			t.Errorf("got %s, want the classic theme by default", theme.Name)
+		}
+	})
+}
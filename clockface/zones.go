@@ -0,0 +1,116 @@
+package clockface
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The hours of the day a clock shows as daytime, from sunrise up to but not including sunset.
+const (
+	Sunrise = 6
+	Sunset  = 18
+)
+
+// Zone is a time zone with the label its clock is shown under.
+type Zone struct {
+	Label    string
+	Location *time.Location
+}
+
+/**
+LoadZone loads a zone by its IANA name, such as Europe/London, labelled with the city in it.
+A zone can be given a label of its own as Label=Zone, HQ=America/Chicago for instance,
+and an empty name is the server's local time.
+*/
+func LoadZone(name string) (Zone, error) {
+	label, name := "", strings.TrimSpace(name)
+	if i := strings.Index(name, "="); i >= 0 {
+		label, name = strings.TrimSpace(name[:i]), strings.TrimSpace(name[i+1:])
+	}
+
+	if name == "" {
+		name = "Local"
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return Zone{}, fmt.Errorf("problem loading time zone %q, %v", name, err)
+	}
+	if label == "" {
+		label = location.String()
+		if i := strings.LastIndex(label, "/"); i >= 0 {
+			label = label[i+1:]
+		}
+		label = strings.ReplaceAll(label, "_", " ")
+	}
+	return Zone{label, location}, nil
+}
+
+// LoadZones loads a comma separated list of zones, as LoadZone does each of them.
+func LoadZones(names string) ([]Zone, error) {
+	var zones []Zone
+	for _, name := range strings.Split(names, ",") {
+		zone, err := LoadZone(name)
+		if err != nil {
+			return nil, err
+		}
+		zones = append(zones, zone)
+	}
+	return zones, nil
+}
+
+// ZoneTime is what a clock of the dashboard shows at a given time, its face as SVG.
+type ZoneTime struct {
+	Label   string
+	Zone    string
+	Time    string
+	Offset  string
+	Daytime bool
+	SVG     string
+}
+
+// At reads the time in the zone and renders its clock face in the theme given.
+func (z Zone) At(t time.Time, theme Theme) ZoneTime {
+	local := t.In(z.Location)
+	_, offset := local.Zone()
+
+	face := bytes.Buffer{}
+	WriteThemedSVG(&face, theme, local)
+
+	return ZoneTime{
+		Label:   z.Label,
+		Zone:    z.Location.String(),
+		Time:    local.Format("15:04"),
+		Offset:  FormatOffset(offset),
+		Daytime: local.Hour() >= Sunrise && local.Hour() < Sunset,
+		SVG:     face.String(),
+	}
+}
+
+// FormatOffset formats an offset from UTC in seconds as UTC+05:30, UTC-04:00 or UTC+00:00.
+func FormatOffset(seconds int) string {
+	sign := '+'
+	if seconds < 0 {
+		sign, seconds = '-', -seconds
+	}
+	return fmt.Sprintf("UTC%c%02d:%02d", sign, seconds/3600, seconds%3600/60)
+}
+
+// Dashboard is the grid of clocks for the zones configured, all of them read at the same time, the classic theme unless given one.
+type Dashboard struct {
+	Zones []Zone
+	Theme Theme
+}
+
+func (d Dashboard) At(t time.Time) []ZoneTime {
+	theme := d.Theme
+	if theme.Name == "" {
+		theme = ClassicTheme
+	}
+	times := make([]ZoneTime, 0, len(d.Zones))
+	for _, zone := range d.Zones {
+		times = append(times, zone.At(t, theme))
+	}
+	return times
+}
@@ -0,0 +1,88 @@
+package clockface_test
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/ydsxiong/go-playground/clockface"
+)
+
+func TestLoadZone(t *testing.T) {
+	cases := []struct {
+		name  string
+		label string
+		zone  string
+	}{
+		{"Europe/London", "London", "Europe/London"},
+		{"America/New_York", "New York", "America/New_York"},
+		{"HQ = America/Chicago", "HQ", "America/Chicago"},
+		{"UTC", "UTC", "UTC"},
+		{"", "Local", "Local"},
+	}
+	for _, c := range cases {
+		zone, err := clockface.LoadZone(c.name)
+		if err != nil {
+			t.Fatalf("problem loading %q, %v", c.name, err)
+		}
+		if zone.Label != c.label || zone.Location.String() != c.zone {
+			t.Errorf("got %s in %s for %q, want %s in %s", zone.Label, zone.Location, c.name, c.label, c.zone)
+		}
+	}
+
+	if _, err := clockface.LoadZone("Europe/Atlantis"); err == nil {
+		t.Error("expected an error for a zone that does not exist")
+	}
+	if _, err := clockface.LoadZones("UTC,Mars/Olympus"); err == nil {
+		t.Error("expected an error for a list with a zone that does not exist")
+	}
+}
+
+func TestDashboard(t *testing.T) {
+	zones, err := clockface.LoadZones("Europe/London,America/New_York,Asia/Kolkata")
+	if err != nil {
+		t.Fatalf("problem loading zones, %v", err)
+	}
+	// summer time in London and New York
+	now := time.Date(2026, time.July, 1, 13, 30, 0, 0, time.UTC)
+
+	clocks := clockface.Dashboard{Zones: zones}.At(now)
+
+	want := []struct {
+		time    string
+		offset  string
+		daytime bool
+	}{
+		{"14:30", "UTC+01:00", true},
+		{"09:30", "UTC-04:00", true},
+		{"19:00", "UTC+05:30", false},
+	}
+	if len(clocks) != len(want) {
+		t.Fatalf("got %d clocks, want %d", len(clocks), len(want))
+	}
+	for i, w := range want {
+		got := clocks[i]
+		if got.Time != w.time || got.Offset != w.offset || got.Daytime != w.daytime {
+			t.Errorf("got %s at %s %s daytime %v, want %s %s daytime %v", got.Label, got.Time, got.Offset, got.Daytime, w.time, w.offset, w.daytime)
+		}
+	}
+
+	t.Run("each clock's hands show the time in its zone", func(t *testing.T) {
+		svg := clockface.SVG{}
+		xml.Unmarshal([]byte(clocks[2].SVG), &svg)
+
+		// it is on the hour in Kolkata, so its minute hand points straight up
+		hand := clockface.Line{clockface.ClockCentreX, clockface.ClockCentreY, clockface.ClockCentreX, clockface.ClockCentreY - clockface.MinuteHandLength}
+		if !containsLine(hand, svg.Line) {
+			t.Errorf("expected the minute hand on the hour %+v, in %+v", hand, svg.Line)
+		}
+	})
+}
+
+func TestFormatOffset(t *testing.T) {
+	for seconds, want := range map[int]string{0: "UTC+00:00", -9000: "UTC-02:30", 45900: "UTC+12:45"} {
+		if got := clockface.FormatOffset(seconds); got != want {
+			t.Errorf("got %s for %d seconds, want %s", got, seconds, want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -9,8 +10,21 @@ import (
 )
 
 const dbFileName = "../../game.db.json"
+const historyFileName = "../../history.db.json"
+const structuresFileName = "../../blind-structures.yml"
+const alertsFileName = "../../alerts.yml"
 
 func main() {
+	speed := flag.Float64("speed", 1, "how many times faster than real time the blinds go up, e.g. 60 for a demo")
+	flag.Parse()
+	if *speed <= 0 {
+		log.Fatalf("The speed must be more than 0, got %v", *speed)
+	}
+
+	var clock poker.Clock = poker.RealClock{}
+	if *speed != 1 {
+		clock = poker.NewScaledClock(*speed)
+	}
 
 	store, closeStore, err := poker.LoadUpFileStore(dbFileName)
 	if err != nil {
@@ -18,7 +32,26 @@ func main() {
 	}
 	defer closeStore()
 
+	games, closeGames, err := poker.LoadUpGameFileStore(historyFileName)
+	if err != nil {
+		log.Fatalf("Problem with loading in game history, %v", err)
+	}
+	defer closeGames()
+
+	structures, err := poker.LoadUpBlindStructures(structuresFileName)
+	if err != nil {
+		log.Fatalf("Problem with loading in blind structures, %v", err)
+	}
+
+	alerter, closeAlerts, err := poker.LoadUpAlerter(alertsFileName, clock)
+	if err != nil {
+		log.Fatalf("Problem with setting up the blind alerts, %v", err)
+	}
+	defer closeAlerts()
+
 	fmt.Println("Let's play some poker...")
-	fmt.Println("Type {Name} wins to record a win")
-	poker.NewPokerCLI(os.Stdin, os.Stdout, poker.NewGame(store, poker.BlindAlerterFunc(poker.StdOutAlerter))).PlayPoker()
+	fmt.Println("Type start to play a game, on your turn type fold, check, call, bet {n} or raise {n}, the best hand wins")
+	fmt.Println("Type help to see everything else you can do")
+	game := poker.NewGame(store, alerter, games, structures, clock)
+	poker.NewPokerCLI(os.Stdin, os.Stdout, game, games, store, structures).Run()
 }
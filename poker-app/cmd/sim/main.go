@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ydsxiong/go-playground/poker-app/poker"
+)
+
+const structuresFileName = "../../blind-structures.yml"
+
+/**
+sim plays games among bots and prints how they went, e.g.
+
+	go run . -games 500 -bots tight,tight,aggressive,random -structure turbo
+
+Nothing is written to the league or the game history on disk, the games are kept in memory.
+*/
+func main() {
+	numberOfGames := flag.Int("games", 100, "how many games to play")
+	botList := flag.String("bots", strings.Join(poker.BotStrategies, ","), "the strategy of every bot at the table, comma separated")
+	structure := flag.String("structure", "", "the blind structure to play, the standard one if not given")
+	stack := flag.Int("stack", poker.DefaultStartingStack, "the chips every bot starts a game with")
+	handDuration := flag.Duration("hand", poker.DefaultHandDuration, "how long a hand takes, the blinds go up by it")
+	maxHands := flag.Int("max-hands", poker.DefaultMaxHands, "the most hands a game lasts, the biggest stack wins after that")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "seeds the bots' decisions")
+	flag.Parse()
+
+	structures, err := poker.LoadUpBlindStructures(structuresFileName)
+	if err != nil {
+		log.Fatalf("Problem with loading in blind structures, %v", err)
+	}
+
+	rnd := rand.New(rand.NewSource(*seed))
+	bots := poker.Bots{}
+	for i, strategy := range strings.Split(*botList, ",") {
+		bot, err := poker.NewBot(strings.TrimSpace(strategy), rnd)
+		if err != nil {
+			log.Fatal(err)
+		}
+		bots[fmt.Sprintf("%s-%d", strings.TrimSpace(strategy), i+1)] = bot
+	}
+
+	clock := poker.NewVirtualClock(time.Now())
+	store := poker.NewInMemoryPlayerStore(clock)
+	games := poker.GetInMemoryGameStore()
+	game := poker.NewGame(store, poker.ClockAlerter(clock), games, structures, clock)
+
+	sim := poker.NewSimulation(game, games, clock, bots, *structure)
+	sim.StartingStack = *stack
+	sim.HandDuration = *handDuration
+	sim.MaxHands = *maxHands
+
+	stats, err := sim.Play(*numberOfGames)
+	if err != nil {
+		log.Fatalf("Problem with the simulation, %v", err)
+	}
+	printStats(stats)
+}
+
+func printStats(stats poker.SimulationStats) {
+	fmt.Printf("Played %d games, %d hands\n", stats.Games, stats.Hands)
+	fmt.Printf("Average game length: %.1f hands, %v\n\n", stats.AverageHands(), stats.AverageLength().Round(time.Second))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Blind level at finish\tGames")
+	var levels []int
+	for level := range stats.Levels {
+		levels = append(levels, level)
+	}
+	sort.Ints(levels)
+	for _, level := range levels {
+		fmt.Fprintf(w, "%d\t%d\n", level, stats.Levels[level])
+	}
+	w.Flush()
+	fmt.Println()
+
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Bot\tWins\tWin %")
+	var bots []string
+	for bot := range stats.Wins {
+		bots = append(bots, bot)
+	}
+	sort.Slice(bots, func(i, j int) bool {
+		return stats.Wins[bots[i]] > stats.Wins[bots[j]]
+	})
+	for _, bot := range bots {
+		fmt.Fprintf(w, "%s\t%d\t%.1f\n", bot, stats.Wins[bot], float64(stats.Wins[bot])/float64(stats.Games)*100)
+	}
+	w.Flush()
+}
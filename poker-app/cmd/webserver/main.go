@@ -1,9 +1,13 @@
 package main
 
 import (
+	"flag"
 	"log"
+	"net"
 	"net/http"
 
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
 	"github.com/ydsxiong/go-playground/poker-app/poker"
 )
 
@@ -22,21 +26,79 @@ type HandlerFunc func(ResponseWriter, *Request)
 */
 
 const dbFileName = "../../game.db.json"
+const historyFileName = "../../history.db.json"
+const structuresFileName = "../../blind-structures.yml"
+const alertsFileName = "../../alerts.yml"
 
 func main() {
+	historyDB := flag.String("history-db", "", "a sqlite3 database file to keep the game history in, instead of "+historyFileName)
+	flag.Parse()
+
 	store, closeStore, err := poker.LoadUpFileStore(dbFileName)
 	if err != nil {
 		log.Fatalf("Problem with loading in file store, %v", err)
 	}
 	defer closeStore()
 
-	game := poker.NewGame(store, poker.BlindAlerterFunc(poker.Alerter))
-	server, e := poker.NewPlayerServer(store, game)
+	games, closeGames := setupGameStore(*historyDB)
+	defer closeGames()
+
+	structures, err := poker.LoadUpBlindStructures(structuresFileName)
+	if err != nil {
+		log.Fatalf("Problem with loading in blind structures, %v", err)
+	}
+
+	alerter, closeAlerts, err := poker.LoadUpAlerter(alertsFileName, poker.RealClock{})
+	if err != nil {
+		log.Fatalf("Problem with setting up the blind alerts, %v", err)
+	}
+	defer closeAlerts()
+
+	game := poker.NewGame(store, alerter, games, structures, poker.RealClock{})
+	tables := poker.NewTableRegistry(func() poker.Game {
+		return poker.NewGame(store, alerter, games, structures, poker.RealClock{})
+	})
+	server, e := poker.NewPlayerServer(store, game, tables, games, structures)
 	if e != nil {
 		log.Fatalf("Problem with setting up the server, %v", err)
 	}
 
+	// the gRPC API runs alongside, on the same store, game and hub as the web pages
+	listener, err := net.Listen("tcp", ":5001")
+	if err != nil {
+		log.Fatalf("Couldn't listen to 5001 port, %v", err)
+	}
+	go func() {
+		if err := poker.NewGRPCServer(store, game, server.Hub()).Serve(listener); err != nil {
+			log.Fatalf("Problem serving gRPC, %v", err)
+		}
+	}()
+
 	if http.ListenAndServe(":5000", server) != nil {
 		log.Fatalf("Couldn't listen to 5000 port, %v", err)
 	}
 }
+
+/**
+setupGameStore keeps the game history in the sqlite3 database given, or in the history file when none is.
+*/
+func setupGameStore(historyDB string) (poker.GameStore, func()) {
+	if historyDB == "" {
+		games, closeGames, err := poker.LoadUpGameFileStore(historyFileName)
+		if err != nil {
+			log.Fatalf("Problem with loading in game history, %v", err)
+		}
+		return games, closeGames
+	}
+
+	gormdb, err := gorm.Open("sqlite3", historyDB)
+	if err != nil {
+		log.Fatalf("Problem with opening game history database, %v", err)
+	}
+	games, err := poker.NewDatabaseGameStore(gormdb)
+	if err != nil {
+		gormdb.Close()
+		log.Fatalf("Problem with loading in game history, %v", err)
+	}
+	return games, func() { gormdb.Close() }
+}
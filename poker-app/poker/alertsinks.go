@@ -0,0 +1,416 @@
+package poker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+/**
+BlindAlert is what an alert template is executed with: the level that has been reached,
+so {{.Blind}}, {{.Ante}}, {{.Minutes}} and {{.Break}} can be used, and {{.At}}, when it was reached.
+{{.}} on its own is the standard message, e.g. "Blind is now 200".
+*/
+type BlindAlert struct {
+	BlindLevel
+	At time.Time
+}
+
+type AlertTemplate struct {
+	tmpl *template.Template
+}
+
+var DefaultAlertTemplate, _ = ParseAlertTemplate("")
+
+// ParseAlertTemplate reads a text/template for blind alerts, an empty one gives the standard message.
+func ParseAlertTemplate(text string) (*AlertTemplate, error) {
+	if text == "" {
+		text = "{{.}}"
+	}
+	tmpl, err := template.New("alert").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("problem parsing alert template %q, %v", text, err)
+	}
+	return &AlertTemplate{tmpl}, nil
+}
+
+func (t *AlertTemplate) Render(alert BlindAlert) (string, error) {
+	var msg strings.Builder
+	if err := t.tmpl.Execute(&msg, alert); err != nil {
+		return "", fmt.Errorf("problem rendering blind alert, %v", err)
+	}
+	return msg.String(), nil
+}
+
+// AlertSink is somewhere blind alerts are delivered to besides the players of the game.
+type AlertSink interface {
+	Deliver(alert BlindAlert) error
+}
+
+/**
+SinkAlerter sends the players each alert as soon as it is due, rendered with the template given,
+and delivers it to every sink as well. Every sink gets the alerts on a goroutine of its own, so
+a sink that is slow or failing never holds up the players or any other sink.
+Once closed the players still get their alerts, but the sinks get nothing more.
+*/
+type SinkAlerter struct {
+	clock  Clock
+	socket *AlertTemplate
+	sinks  []*isolatedSink
+
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func NewSinkAlerter(clock Clock, socket *AlertTemplate, sinks ...AlertSink) *SinkAlerter {
+	a := &SinkAlerter{clock: clock, socket: socket}
+	for _, sink := range sinks {
+		a.wg.Add(1)
+		a.sinks = append(a.sinks, isolate(sink, &a.wg))
+	}
+	return a
+}
+
+func (a *SinkAlerter) ScheduleAlertAt(duration time.Duration, level BlindLevel, to io.Writer) AlertHandle {
+	return scheduleAlert(a.clock, duration, func() {
+		alert := BlindAlert{level, a.clock.Now()}
+		msg, err := a.socket.Render(alert)
+		if err != nil {
+			log.Println(err)
+			msg = level.String()
+		}
+		fmt.Fprintf(to, "%s\n", msg)
+
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		if a.closed {
+			return
+		}
+		for _, sink := range a.sinks {
+			sink.send(alert)
+		}
+	})
+}
+
+/**
+Close stops the sinks once they have delivered the alerts queued for them, so whatever they write to
+can then be closed. Sinks that can be stopped, like webhooks, are told to give up on retrying.
+*/
+func (a *SinkAlerter) Close() {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		for _, sink := range a.sinks {
+			close(sink.queue)
+			if stopper, ok := sink.sink.(interface{ Stop() }); ok {
+				stopper.Stop()
+			}
+		}
+	}
+	a.mu.Unlock()
+	a.wg.Wait()
+}
+
+// how many alerts a sink can fall behind by before new ones are dropped
+const sinkQueueSize = 16
+
+type isolatedSink struct {
+	sink  AlertSink
+	queue chan BlindAlert
+}
+
+// isolate delivers the alerts to the sink on a goroutine of its own, which is done once the queue is closed and emptied.
+func isolate(sink AlertSink, wg *sync.WaitGroup) *isolatedSink {
+	s := &isolatedSink{sink, make(chan BlindAlert, sinkQueueSize)}
+	go func() {
+		defer wg.Done()
+		for alert := range s.queue {
+			if err := s.sink.Deliver(alert); err != nil {
+				log.Printf("problem delivering a blind alert, %v\n", err)
+			}
+		}
+	}()
+	return s
+}
+
+func (s *isolatedSink) send(alert BlindAlert) {
+	select {
+	case s.queue <- alert:
+	default:
+		log.Printf("dropping the alert %q, the sink is %d alerts behind\n", alert, sinkQueueSize)
+	}
+}
+
+type writerSink struct {
+	out      io.Writer
+	template *AlertTemplate
+}
+
+// NewWriterSink writes every alert on a line of its own, to the console for example.
+func NewWriterSink(out io.Writer, template *AlertTemplate) AlertSink {
+	return &writerSink{out, template}
+}
+
+func (s *writerSink) Deliver(alert BlindAlert) error {
+	msg, err := s.template.Render(alert)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(s.out, "%s\n", msg)
+	return err
+}
+
+/**
+FileLogSink appends every alert to a log file, one line each starting with when it went off.
+The file is only ever appended to, alerts from earlier runs are kept.
+*/
+type FileLogSink struct {
+	file     *os.File
+	template *AlertTemplate
+}
+
+func NewFileLogSink(path string, template *AlertTemplate) (*FileLogSink, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0666)
+	if err != nil {
+		return nil, fmt.Errorf("problem opening alert log %s, %v", path, err)
+	}
+	return &FileLogSink{file, template}, nil
+}
+
+func (s *FileLogSink) Deliver(alert BlindAlert) error {
+	msg, err := s.template.Render(alert)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(s.file, "%s %s\n", alert.At.Format(time.RFC3339), msg); err != nil {
+		return fmt.Errorf("problem writing to alert log %s, %v", s.file.Name(), err)
+	}
+	return nil
+}
+
+func (s *FileLogSink) Close() error {
+	return s.file.Close()
+}
+
+// WebhookAlert is the JSON body posted to a webhook for every alert.
+type WebhookAlert struct {
+	Blind   int
+	Ante    int
+	Minutes int
+	Break   bool
+	At      time.Time
+	Message string
+}
+
+/**
+WebhookSink posts every alert to a URL as a WebhookAlert. A delivery that fails, because the
+receiver could not be reached or answered with a server error, is tried again up to Retries times,
+waiting a little longer before every attempt, unless the sink has been stopped in the meantime.
+Any other answer but a 2xx fails the delivery too, a 4xx straight away as it would only be turned
+down again.
+*/
+type WebhookSink struct {
+	URL      string
+	Retries  int
+	Backoff  time.Duration
+	client   *http.Client
+	template *AlertTemplate
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+const (
+	DefaultWebhookRetries = 3
+	DefaultWebhookBackoff = time.Second
+	webhookTimeout        = 5 * time.Second
+)
+
+func NewWebhookSink(url string, template *AlertTemplate) *WebhookSink {
+	return &WebhookSink{
+		URL:      url,
+		Retries:  DefaultWebhookRetries,
+		Backoff:  DefaultWebhookBackoff,
+		client:   &http.Client{Timeout: webhookTimeout},
+		template: template,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Stop gives up on retrying deliveries that failed, so the app is not kept waiting on a receiver that is down as it closes.
+func (s *WebhookSink) Stop() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+func (s *WebhookSink) Deliver(alert BlindAlert) error {
+	msg, err := s.template.Render(alert)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(WebhookAlert{alert.Blind, alert.Ante, alert.Minutes, alert.Break, alert.At, msg})
+	if err != nil {
+		return fmt.Errorf("problem encoding blind alert, %v", err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		err = s.post(body)
+		if _, rejected := err.(webhookRejection); err == nil || rejected || attempt >= s.Retries {
+			return err
+		}
+		select {
+		case <-s.stop:
+			return err
+		case <-time.After(time.Duration(attempt+1) * s.Backoff):
+		}
+	}
+}
+
+func (s *WebhookSink) post(body []byte) error {
+	res, err := s.client.Post(s.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("problem posting blind alert to %s, %v", s.URL, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 400 && res.StatusCode < 500 {
+		return webhookRejection{fmt.Errorf("blind alert turned down by %s, got %s", s.URL, res.Status)}
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("problem posting blind alert to %s, got %s", s.URL, res.Status)
+	}
+	return nil
+}
+
+// webhookRejection is a webhook answering with a 4xx, which it will go on doing however often the alert is posted.
+type webhookRejection struct {
+	error
+}
+
+/**
+AlertConfig is where the blind alerts go, as read from a YAML or JSON file:
+
+	template: "Blinds up! {{.Blind}}"
+	sinks:
+	  - {type: log, path: alerts.log, template: "{{.Blind}}/{{.Ante}}"}
+	  - {type: webhook, url: "http://localhost:8080/alerts", retries: 5}
+	  - {type: console}
+
+Template is what the players are sent, every sink renders its own template.
+*/
+type AlertConfig struct {
+	Template string
+	Sinks    []AlertSinkConfig
+}
+
+// AlertSinkConfig is one sink: a console, an append-only log at Path or a webhook posted to at URL.
+type AlertSinkConfig struct {
+	Type     string
+	Path     string `json:",omitempty"`
+	URL      string `json:",omitempty"`
+	Template string `json:",omitempty"`
+	Retries  int    `json:",omitempty"`
+}
+
+// The types of sink an AlertSinkConfig can be.
+const (
+	ConsoleSinkType = "console"
+	LogSinkType     = "log"
+	WebhookSinkType = "webhook"
+)
+
+func LoadAlertConfig(path string) (AlertConfig, error) {
+	var config AlertConfig
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("problem reading alert config %s, %v", path, err)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+		err = yaml.Unmarshal(data, &config)
+	case ".json":
+		err = json.Unmarshal(data, &config)
+	default:
+		return config, fmt.Errorf("unknown alert config file type %s, expecting .yml, .yaml or .json", path)
+	}
+	if err != nil {
+		return config, fmt.Errorf("problem parsing alert config %s, %v", path, err)
+	}
+	return config, nil
+}
+
+/**
+Alerter sets up the sinks of the config and returns an alerter that uses them, along with
+a func to close the sinks once the app is done, which waits for them to deliver what they
+have been given before closing the logs they write to.
+*/
+func (c AlertConfig) Alerter(clock Clock) (BlindAlerterFunc, func(), error) {
+	var sinks []AlertSink
+	var logs []*FileLogSink
+	closeLogs := func() {
+		for _, l := range logs {
+			l.Close()
+		}
+	}
+
+	socket, err := ParseAlertTemplate(c.Template)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, sc := range c.Sinks {
+		template, err := ParseAlertTemplate(sc.Template)
+		if err != nil {
+			closeLogs()
+			return nil, nil, err
+		}
+		switch sc.Type {
+		case ConsoleSinkType:
+			sinks = append(sinks, NewWriterSink(os.Stdout, template))
+		case LogSinkType:
+			l, err := NewFileLogSink(sc.Path, template)
+			if err != nil {
+				closeLogs()
+				return nil, nil, err
+			}
+			logs = append(logs, l)
+			sinks = append(sinks, l)
+		case WebhookSinkType:
+			webhook := NewWebhookSink(sc.URL, template)
+			if sc.Retries > 0 {
+				webhook.Retries = sc.Retries
+			}
+			sinks = append(sinks, webhook)
+		default:
+			closeLogs()
+			return nil, nil, fmt.Errorf("unknown alert sink %q, expecting %s, %s or %s", sc.Type, ConsoleSinkType, LogSinkType, WebhookSinkType)
+		}
+	}
+	alerter := NewSinkAlerter(clock, socket, sinks...)
+	return alerter.ScheduleAlertAt, func() {
+		alerter.Close()
+		closeLogs()
+	}, nil
+}
+
+// LoadUpAlerter is LoadAlertConfig for the apps, without a config file the alerts only go to the players.
+func LoadUpAlerter(path string, clock Clock) (BlindAlerterFunc, func(), error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return ClockAlerter(clock), func() {}, nil
+	}
+	config, err := LoadAlertConfig(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return config.Alerter(clock)
+}
@@ -0,0 +1,243 @@
+package poker_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ydsxiong/go-playground/poker-app/poker"
+)
+
+// SpyAlertSink remembers every alert delivered to it, blocking until released when told to.
+type SpyAlertSink struct {
+	mu      sync.Mutex
+	alerts  []poker.BlindAlert
+	release chan struct{}
+}
+
+func (s *SpyAlertSink) Deliver(alert poker.BlindAlert) error {
+	if s.release != nil {
+		<-s.release
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.alerts = append(s.alerts, alert)
+	return nil
+}
+
+func (s *SpyAlertSink) delivered() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.alerts)
+}
+
+func TestAlertSinks(t *testing.T) {
+	level := poker.BlindLevel{Blind: 200, Ante: 25, Minutes: 10}
+
+	t.Run("the players are sent the alert rendered with the template", func(t *testing.T) {
+		clock := poker.NewVirtualClock(time.Now())
+		template, err := poker.ParseAlertTemplate("Blinds up! {{.Blind}}/{{.Ante}} for {{.Minutes}} minutes")
+		assertNoError(t, err)
+		out := &alertBuffer{}
+
+		poker.NewSinkAlerter(clock, template).ScheduleAlertAt(time.Minute, level, out)
+		clock.Advance(time.Minute)
+
+		assertAlerted(t, out, "Blinds up! 200/25 for 10 minutes\n")
+	})
+
+	t.Run("a slow sink holds up neither the players nor the other sinks", func(t *testing.T) {
+		clock := poker.NewVirtualClock(time.Now())
+		slow := &SpyAlertSink{release: make(chan struct{})}
+		fast := &SpyAlertSink{}
+		out := &alertBuffer{}
+
+		poker.NewSinkAlerter(clock, poker.DefaultAlertTemplate, slow, fast).ScheduleAlertAt(0, level, out)
+		clock.Advance(0)
+
+		assertAlerted(t, out, "Blind is now 200, ante 25\n")
+		if !retryUntil(500*time.Millisecond, func() bool { return fast.delivered() == 1 }) {
+			t.Error("expected the fast sink to get the alert while the slow one is stuck")
+		}
+
+		close(slow.release)
+		if !retryUntil(500*time.Millisecond, func() bool { return slow.delivered() == 1 }) {
+			t.Error("expected the slow sink to get the alert in the end")
+		}
+	})
+
+	t.Run("closing waits for the sinks to deliver what they were given, and stops them", func(t *testing.T) {
+		clock := poker.NewVirtualClock(time.Now())
+		slow := &SpyAlertSink{release: make(chan struct{})}
+		alerter := poker.NewSinkAlerter(clock, poker.DefaultAlertTemplate, slow)
+		out := &alertBuffer{}
+
+		alerter.ScheduleAlertAt(0, level, out)
+		alerter.ScheduleAlertAt(time.Minute, level, out)
+		clock.Advance(0)
+
+		closed := make(chan struct{})
+		go func() {
+			alerter.Close()
+			close(closed)
+		}()
+		select {
+		case <-closed:
+			t.Fatal("expected closing to wait for the slow sink")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(slow.release)
+		select {
+		case <-closed:
+		case <-time.After(500 * time.Millisecond):
+			t.Fatal("expected closing to be done once the sink delivered its alert")
+		}
+
+		clock.Advance(time.Minute)
+		assertAlerted(t, out, "Blind is now 200, ante 25\nBlind is now 200, ante 25\n")
+		if got := slow.delivered(); got != 1 {
+			t.Errorf("expected only the alert before closing to be delivered, got %d", got)
+		}
+	})
+
+	t.Run("the log is appended to", func(t *testing.T) {
+		dir, err := ioutil.TempDir(".", "alerts")
+		assertNoError(t, err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "alerts.log")
+		ioutil.WriteFile(path, []byte("2026-01-01T00:00:00Z Blind is now 100\n"), 0666)
+
+		template, _ := poker.ParseAlertTemplate("{{.Blind}}/{{.Ante}}")
+		sink, err := poker.NewFileLogSink(path, template)
+		assertNoError(t, err)
+		defer sink.Close()
+
+		at := time.Date(2026, 1, 1, 0, 10, 0, 0, time.UTC)
+		assertNoError(t, sink.Deliver(poker.BlindAlert{BlindLevel: level, At: at}))
+
+		got, _ := ioutil.ReadFile(path)
+		if want := "2026-01-01T00:00:00Z Blind is now 100\n2026-01-01T00:10:00Z 200/25\n"; string(got) != want {
+			t.Errorf("got log %q, want %q", got, want)
+		}
+	})
+
+	t.Run("a webhook is tried again until it takes the alert", func(t *testing.T) {
+		var mu sync.Mutex
+		var attempts int
+		var received poker.WebhookAlert
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			attempts++
+			if attempts < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			json.NewDecoder(r.Body).Decode(&received)
+		}))
+		defer receiver.Close()
+
+		sink := poker.NewWebhookSink(receiver.URL, poker.DefaultAlertTemplate)
+		sink.Backoff = time.Millisecond
+		assertNoError(t, sink.Deliver(poker.BlindAlert{BlindLevel: level}))
+
+		mu.Lock()
+		defer mu.Unlock()
+		if attempts != 3 || received.Blind != 200 || received.Message != "Blind is now 200, ante 25" {
+			t.Errorf("got %+v after %d attempts", received, attempts)
+		}
+	})
+
+	t.Run("a webhook gives up after its retries", func(t *testing.T) {
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer receiver.Close()
+
+		sink := poker.NewWebhookSink(receiver.URL, poker.DefaultAlertTemplate)
+		sink.Backoff, sink.Retries = time.Millisecond, 1
+		if err := sink.Deliver(poker.BlindAlert{BlindLevel: level}); err == nil {
+			t.Error("expected an error once the retries ran out")
+		}
+	})
+
+	t.Run("a webhook that turns the alert down is not tried again", func(t *testing.T) {
+		var mu sync.Mutex
+		var attempts int
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			attempts++
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer receiver.Close()
+
+		sink := poker.NewWebhookSink(receiver.URL, poker.DefaultAlertTemplate)
+		sink.Backoff = time.Millisecond
+		if err := sink.Deliver(poker.BlindAlert{BlindLevel: level}); err == nil {
+			t.Error("expected an error from a webhook answering 404")
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if attempts != 1 {
+			t.Errorf("expected a single attempt, got %d", attempts)
+		}
+	})
+
+	t.Run("a bad template is rejected", func(t *testing.T) {
+		if _, err := poker.ParseAlertTemplate("{{.Blind"); err == nil {
+			t.Error("expected an error parsing a broken template")
+		}
+	})
+}
+
+func TestLoadAlertConfig(t *testing.T) {
+	dir, err := ioutil.TempDir(".", "alerts")
+	assertNoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "alerts.yml")
+	ioutil.WriteFile(path, []byte(`
+template: "Blinds up! {{.Blind}}"
+sinks:
+  - {type: log, path: `+filepath.Join(dir, "alerts.log")+`, template: "{{.Blind}}"}
+  - {type: webhook, url: "http://localhost:1/alerts", retries: 5}
+`), 0666)
+
+	config, err := poker.LoadAlertConfig(path)
+	assertNoError(t, err)
+	if config.Template != "Blinds up! {{.Blind}}" || len(config.Sinks) != 2 || config.Sinks[1].Retries != 5 {
+		t.Fatalf("unexpected config %+v", config)
+	}
+
+	clock := poker.NewVirtualClock(time.Now())
+	alerter, closeAlerts, err := config.Alerter(clock)
+	assertNoError(t, err)
+	defer closeAlerts()
+
+	out := &alertBuffer{}
+	alerter(0, poker.BlindLevel{Blind: 100}, out)
+	clock.Advance(0)
+	assertAlerted(t, out, "Blinds up! 100\n")
+
+	if !retryUntil(500*time.Millisecond, func() bool {
+		log, _ := ioutil.ReadFile(filepath.Join(dir, "alerts.log"))
+		return strings.HasSuffix(string(log), " 100\n")
+	}) {
+		t.Error("expected the alert in the log")
+	}
+
+	config.Sinks = append(config.Sinks, poker.AlertSinkConfig{Type: "pigeon"})
+	if _, _, err := config.Alerter(clock); err == nil {
+		t.Error("expected an error for an unknown sink")
+	}
+}
@@ -1,13 +1,23 @@
 package poker
 
 import (
-	"fmt"
 	"io"
+	"sync"
 	"time"
 )
 
 type BlindAlerter interface {
-	ScheduleAlertAt(duration time.Duration, amount int, to io.Writer)
+	ScheduleAlertAt(duration time.Duration, level BlindLevel, to io.Writer) AlertHandle
+}
+
+/**
+AlertHandle controls alerts that have been scheduled. Pausing freezes the time left until
+an alert is due and resuming carries on from there, a cancelled alert never goes off.
+*/
+type AlertHandle interface {
+	Pause()
+	Resume()
+	Cancel()
 }
 
 /**
@@ -18,14 +28,73 @@ This type will be a func which will also implement your interface.
 That way users of your interface have the option to implement your interface with just a function;
 rather than having to create an empty struct type.
 */
-type BlindAlerterFunc func(duration time.Duration, amount int, to io.Writer)
+type BlindAlerterFunc func(duration time.Duration, level BlindLevel, to io.Writer) AlertHandle
+
+func (ba BlindAlerterFunc) ScheduleAlertAt(duration time.Duration, level BlindLevel, to io.Writer) AlertHandle {
+	return ba(duration, level, to)
+}
+
+func Alerter(duration time.Duration, level BlindLevel, to io.Writer) AlertHandle {
+	return ClockAlerter(RealClock{})(duration, level, to)
+}
 
-func (ba BlindAlerterFunc) ScheduleAlertAt(duration time.Duration, amount int, to io.Writer) {
-	ba(duration, amount, to)
+// ClockAlerter writes the alerts when they fall due on the given clock rather than the wall clock.
+func ClockAlerter(clock Clock) BlindAlerterFunc {
+	return NewSinkAlerter(clock, DefaultAlertTemplate).ScheduleAlertAt
 }
 
-func Alerter(duration time.Duration, amount int, to io.Writer) {
-	time.AfterFunc(duration, func() {
-		fmt.Fprintf(to, "Blind is now %d\n", amount)
+// scheduleAlert calls fire once the duration has passed on the clock, unless the alert is cancelled first.
+func scheduleAlert(clock Clock, duration time.Duration, fire func()) AlertHandle {
+	alert := &timerAlert{clock: clock, dueAt: clock.Now().Add(duration)}
+	alert.mu.Lock()
+	defer alert.mu.Unlock()
+	alert.timer = clock.AfterFunc(duration, func() {
+		alert.mu.Lock()
+		defer alert.mu.Unlock()
+		if !alert.cancelled {
+			fire()
+		}
 	})
+	return alert
+}
+
+type timerAlert struct {
+	mu        sync.Mutex
+	clock     Clock
+	timer     Timer
+	dueAt     time.Time
+	remaining time.Duration
+	paused    bool
+	cancelled bool
+}
+
+func (a *timerAlert) Pause() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.paused || a.cancelled {
+		return
+	}
+	// an alert that has already gone off has nothing left to pause
+	if a.timer.Stop() {
+		a.remaining = a.dueAt.Sub(a.clock.Now())
+		a.paused = true
+	}
+}
+
+func (a *timerAlert) Resume() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.paused || a.cancelled {
+		return
+	}
+	a.paused = false
+	a.dueAt = a.clock.Now().Add(a.remaining)
+	a.timer.Reset(a.remaining)
+}
+
+func (a *timerAlert) Cancel() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.cancelled = true
+	a.timer.Stop()
 }
@@ -0,0 +1,87 @@
+package poker_test
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ydsxiong/go-playground/poker-app/poker"
+)
+
+// alertBuffer is written to from the alert timers while the test reads it.
+type alertBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *alertBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *alertBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestAlerter(t *testing.T) {
+	level := poker.BlindLevel{Blind: 200}
+
+	t.Run("it alerts once the time is up", func(t *testing.T) {
+		clock := poker.NewVirtualClock(time.Now())
+		out := &alertBuffer{}
+		poker.ClockAlerter(clock)(5*time.Minute, level, out)
+
+		clock.Advance(4 * time.Minute)
+		assertAlerted(t, out, "")
+		clock.Advance(time.Minute)
+		assertAlerted(t, out, "Blind is now 200\n")
+	})
+
+	t.Run("a paused alert keeps the time it had left until it is resumed", func(t *testing.T) {
+		clock := poker.NewVirtualClock(time.Now())
+		out := &alertBuffer{}
+		alert := poker.ClockAlerter(clock)(5*time.Minute, level, out)
+
+		clock.Advance(3 * time.Minute)
+		alert.Pause()
+		clock.Advance(time.Hour)
+		assertAlerted(t, out, "")
+
+		alert.Resume()
+		clock.Advance(time.Minute)
+		assertAlerted(t, out, "")
+		clock.Advance(time.Minute)
+		assertAlerted(t, out, "Blind is now 200\n")
+	})
+
+	t.Run("a cancelled alert never goes off", func(t *testing.T) {
+		clock := poker.NewVirtualClock(time.Now())
+		out := &alertBuffer{}
+		alert := poker.ClockAlerter(clock)(5*time.Minute, level, out)
+
+		alert.Cancel()
+		alert.Resume()
+		clock.Advance(time.Hour)
+		assertAlerted(t, out, "")
+	})
+
+	t.Run("it alerts on the wall clock", func(t *testing.T) {
+		out := &alertBuffer{}
+		poker.Alerter(5*time.Millisecond, level, out)
+
+		if !retryUntil(500*time.Millisecond, func() bool { return out.String() == "Blind is now 200\n" }) {
+			t.Errorf("expected the blind alert, got %q", out.String())
+		}
+	})
+}
+
+func assertAlerted(t *testing.T, out *alertBuffer, want string) {
+	t.Helper()
+	if out.String() != want {
+		t.Errorf("got alerts %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,208 @@
+package poker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+const StandardBlindStructure = "standard"
+
+var standardBlinds = []int{100, 200, 300, 400, 500, 600, 800, 1000, 2000, 4000, 8000}
+
+/**
+BlindLevel is one step of a tournament, either a blind (with an optional ante)
+played for a number of minutes, or a break of that many minutes.
+*/
+type BlindLevel struct {
+	Blind   int
+	Ante    int
+	Minutes int
+	Break   bool
+}
+
+func (l BlindLevel) Duration() time.Duration {
+	return time.Duration(l.Minutes) * time.Minute
+}
+
+func (l BlindLevel) String() string {
+	switch {
+	case l.Break:
+		return fmt.Sprintf("Break for %d minutes", l.Minutes)
+	case l.Ante > 0:
+		return fmt.Sprintf("Blind is now %d, ante %d", l.Blind, l.Ante)
+	}
+	return fmt.Sprintf("Blind is now %d", l.Blind)
+}
+
+type BlindStructure struct {
+	Name   string
+	Levels []BlindLevel
+}
+
+/**
+DefaultBlindStructure is the structure games have always been played with,
+every level lasts 5 minutes plus a minute for every player at the table.
+*/
+func DefaultBlindStructure(numberOfPlayers int) BlindStructure {
+	structure := BlindStructure{Name: StandardBlindStructure}
+	for _, blind := range standardBlinds {
+		structure.Levels = append(structure.Levels, BlindLevel{Blind: blind, Minutes: 5 + numberOfPlayers})
+	}
+	return structure
+}
+
+func (s BlindStructure) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("a blind structure needs a name")
+	}
+	blinds := 0
+	for i, l := range s.Levels {
+		if l.Minutes <= 0 {
+			return fmt.Errorf("level %d of %s must last at least a minute", i+1, s.Name)
+		}
+		if l.Break {
+			continue
+		}
+		if l.Blind <= 0 || l.Ante < 0 {
+			return fmt.Errorf("level %d of %s needs a positive blind and ante, got %d and %d", i+1, s.Name, l.Blind, l.Ante)
+		}
+		blinds++
+	}
+	if blinds == 0 {
+		return fmt.Errorf("%s has no blind levels", s.Name)
+	}
+	return nil
+}
+
+// LevelAt returns the index of the level being played after elapsed time, the last level lasts forever.
+func (s BlindStructure) LevelAt(elapsed time.Duration) int {
+	for i, l := range s.Levels {
+		if elapsed < l.Duration() {
+			return i
+		}
+		elapsed -= l.Duration()
+	}
+	return len(s.Levels) - 1
+}
+
+// BlindAt is the blind and ante in play at the given level, a break keeps the blind of the level before it.
+func (s BlindStructure) BlindAt(level int) BlindLevel {
+	for i := level; i >= 0; i-- {
+		if !s.Levels[i].Break {
+			return s.Levels[i]
+		}
+	}
+	for _, l := range s.Levels {
+		if !l.Break {
+			return l
+		}
+	}
+	return BlindLevel{}
+}
+
+/**
+BlindStructures is the library of named structures a game can be started with.
+The standard structure is always available, unless a structure of the same name replaces it.
+*/
+type BlindStructures struct {
+	mu         sync.RWMutex
+	structures map[string]BlindStructure
+}
+
+func NewBlindStructures(structures ...BlindStructure) (*BlindStructures, error) {
+	library := &BlindStructures{structures: map[string]BlindStructure{}}
+	for _, s := range structures {
+		if err := library.Add(s); err != nil {
+			return nil, err
+		}
+	}
+	return library, nil
+}
+
+/**
+LoadBlindStructures reads a list of structures from a .yml/.yaml or .json file, e.g.
+
+	- name: turbo
+	  levels:
+	    - {blind: 100, minutes: 5}
+	    - {blind: 200, ante: 25, minutes: 5}
+	    - {break: true, minutes: 10}
+*/
+func LoadBlindStructures(path string) (*BlindStructures, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("problem reading blind structures %s, %v", path, err)
+	}
+
+	var structures []BlindStructure
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+		err = yaml.Unmarshal(data, &structures)
+	case ".json":
+		err = json.Unmarshal(data, &structures)
+	default:
+		return nil, fmt.Errorf("unknown blind structure file type %s, expecting .yml, .yaml or .json", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("problem parsing blind structures %s, %v", path, err)
+	}
+	return NewBlindStructures(structures...)
+}
+
+// LoadUpBlindStructures is LoadBlindStructures for the apps, a missing file leaves only the standard structure.
+func LoadUpBlindStructures(path string) (*BlindStructures, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return NewBlindStructures()
+	}
+	return LoadBlindStructures(path)
+}
+
+func (bs *BlindStructures) Add(structure BlindStructure) error {
+	if err := structure.Validate(); err != nil {
+		return err
+	}
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	bs.structures[structure.Name] = structure
+	return nil
+}
+
+func (bs *BlindStructures) Get(name string, numberOfPlayers int) (BlindStructure, error) {
+	if name == "" {
+		name = StandardBlindStructure
+	}
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+
+	if structure, ok := bs.structures[name]; ok {
+		return structure, nil
+	}
+	if name == StandardBlindStructure {
+		return DefaultBlindStructure(numberOfPlayers), nil
+	}
+	return BlindStructure{}, fmt.Errorf("unknown blind structure %s", name)
+}
+
+// List returns the structures added to the library, sorted by name.
+func (bs *BlindStructures) List() []BlindStructure {
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+
+	var structures []BlindStructure
+	for _, s := range bs.structures {
+		structures = append(structures, s)
+	}
+	sort.Slice(structures, func(i, j int) bool {
+		return structures[i].Name < structures[j].Name
+	})
+	return structures
+}
@@ -0,0 +1,148 @@
+package poker_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ydsxiong/go-playground/poker-app/poker"
+)
+
+var turbo = poker.BlindStructure{
+	Name: "turbo",
+	Levels: []poker.BlindLevel{
+		{Blind: 100, Minutes: 5},
+		{Blind: 200, Ante: 25, Minutes: 5},
+		{Break: true, Minutes: 10},
+		{Blind: 400, Ante: 50, Minutes: 5},
+	},
+}
+
+func TestBlindStructure(t *testing.T) {
+
+	t.Run("it finds the level and the blind in play after some time", func(t *testing.T) {
+		cases := []struct {
+			elapsed time.Duration
+			level   int
+			blind   int
+		}{
+			{0, 0, 100},
+			{7 * time.Minute, 1, 200},
+			{12 * time.Minute, 2, 200},
+			{20 * time.Minute, 3, 400},
+			{3 * time.Hour, 3, 400},
+		}
+		for _, c := range cases {
+			level := turbo.LevelAt(c.elapsed)
+			if level != c.level || turbo.BlindAt(level).Blind != c.blind {
+				t.Errorf("after %v got level %d blind %d, want level %d blind %d", c.elapsed, level, turbo.BlindAt(level).Blind, c.level, c.blind)
+			}
+		}
+	})
+
+	t.Run("it rejects structures without a name, blinds or duration", func(t *testing.T) {
+		invalid := []poker.BlindStructure{
+			{Levels: turbo.Levels},
+			{Name: "breaks", Levels: []poker.BlindLevel{{Break: true, Minutes: 5}}},
+			{Name: "instant", Levels: []poker.BlindLevel{{Blind: 100}}},
+			{Name: "negative", Levels: []poker.BlindLevel{{Blind: 100, Ante: -1, Minutes: 5}}},
+		}
+		for _, s := range invalid {
+			if _, err := poker.NewBlindStructures(s); err == nil {
+				t.Errorf("expected %+v to be rejected", s)
+			}
+		}
+	})
+
+	t.Run("it keeps the standard structure unless it is replaced", func(t *testing.T) {
+		structures, _ := poker.NewBlindStructures(turbo)
+
+		standard, err := structures.Get("", 5)
+		assertNoError(t, err)
+		if len(standard.Levels) != 11 || standard.Levels[0].Duration() != 10*time.Minute {
+			t.Errorf("unexpected standard structure %+v", standard)
+		}
+
+		if _, err := structures.Get("marathon", 5); err == nil {
+			t.Error("expected an error for an unknown structure")
+		}
+	})
+}
+
+func TestLoadBlindStructures(t *testing.T) {
+	dir, err := ioutil.TempDir(".", "structures")
+	assertNoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"structures.yml": `
+- name: turbo
+  levels:
+    - {blind: 100, minutes: 5}
+    - {blind: 200, ante: 25, minutes: 5}
+    - {break: true, minutes: 10}
+    - {blind: 400, ante: 50, minutes: 5}
+`,
+		"structures.json": `[{"Name": "turbo", "Levels": [
+			{"Blind": 100, "Minutes": 5},
+			{"Blind": 200, "Ante": 25, "Minutes": 5},
+			{"Break": true, "Minutes": 10},
+			{"Blind": 400, "Ante": 50, "Minutes": 5}]}]`,
+	}
+
+	for name, content := range files {
+		t.Run("it loads "+name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			ioutil.WriteFile(path, []byte(content), 0666)
+
+			structures, err := poker.LoadBlindStructures(path)
+			assertNoError(t, err)
+
+			got, err := structures.Get("turbo", 2)
+			assertNoError(t, err)
+			if !reflect.DeepEqual(got, turbo) {
+				t.Errorf("got %+v, want %+v", got, turbo)
+			}
+		})
+	}
+
+	t.Run("it only has the standard structure when the file is missing", func(t *testing.T) {
+		structures, err := poker.LoadUpBlindStructures(filepath.Join(dir, "missing.yml"))
+		assertNoError(t, err)
+		if len(structures.List()) != 0 {
+			t.Errorf("expected no structures, got %v", structures.List())
+		}
+	})
+}
+
+func TestStructuresAPI(t *testing.T) {
+	structures, _ := poker.NewBlindStructures()
+	server, _ := poker.NewPlayerServer(poker.GetInMemoryStore(), dummyGame, poker.NewTableRegistry(func() poker.Game { return dummyGame }), dummyGameStore, structures)
+
+	t.Run("it adds a structure and serves it back", func(t *testing.T) {
+		body, _ := json.Marshal(turbo)
+		assertStatus(t, serveRequest(server, http.MethodPost, "/structures", string(body)), http.StatusCreated)
+
+		res := serveRequest(server, http.MethodGet, "/structures/turbo", "")
+		assertStatus(t, res, http.StatusOK)
+
+		var got poker.BlindStructure
+		json.NewDecoder(res.Body).Decode(&got)
+		if !reflect.DeepEqual(got, turbo) {
+			t.Errorf("got %+v, want %+v", got, turbo)
+		}
+	})
+
+	t.Run("it rejects an invalid structure", func(t *testing.T) {
+		assertStatus(t, serveRequest(server, http.MethodPost, "/structures", `{"Name": "empty"}`), http.StatusBadRequest)
+	})
+
+	t.Run("it returns 404 for an unknown structure", func(t *testing.T) {
+		assertStatus(t, serveRequest(server, http.MethodGet, "/structures/marathon", ""), http.StatusNotFound)
+	})
+}
@@ -0,0 +1,143 @@
+package poker
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// The strategies a bot can play, see NewBot.
+const (
+	RandomStrategy     = "random"
+	TightStrategy      = "tight"
+	AggressiveStrategy = "aggressive"
+)
+
+var BotStrategies = []string{RandomStrategy, TightStrategy, AggressiveStrategy}
+
+// bots stop raising once the pot is this many times the last raise, so that two bots never raise each other for ever
+const botRaiseCap = 10
+
+/**
+NewBot returns an Actor playing the named strategy:
+
+	random      does anything it is allowed to, at random
+	tight       only plays good starting hands and only bets with a made hand
+	aggressive  bets and raises whenever it can, whatever it holds
+*/
+func NewBot(strategy string, rnd *rand.Rand) (Actor, error) {
+	switch strategy {
+	case RandomStrategy:
+		return RandomBot(rnd), nil
+	case TightStrategy:
+		return TightBot(), nil
+	case AggressiveStrategy:
+		return AggressiveBot(rnd), nil
+	}
+	return nil, fmt.Errorf("unknown bot strategy %q, expecting %s, %s or %s", strategy, RandomStrategy, TightStrategy, AggressiveStrategy)
+}
+
+func RandomBot(rnd *rand.Rand) Actor {
+	return ActorFunc(func(view TableView) Action {
+		switch rnd.Intn(3) {
+		case 0:
+			return foldOrCheck(view)
+		case 1:
+			return checkOrCall(view)
+		}
+		return raiseIfAllowed(view)
+	})
+}
+
+func TightBot() Actor {
+	return ActorFunc(func(view TableView) Action {
+		switch handStrength(view) {
+		case strongHand:
+			return raiseIfAllowed(view)
+		case playableHand:
+			// a playable hand is not worth all of the bot's chips
+			if view.ToCall <= view.MinRaise && (view.Stack == 0 || view.ToCall < view.Stack) {
+				return checkOrCall(view)
+			}
+		}
+		return foldOrCheck(view)
+	})
+}
+
+func AggressiveBot(rnd *rand.Rand) Actor {
+	return ActorFunc(func(view TableView) Action {
+		if handStrength(view) == weakHand && view.ToCall > 0 && rnd.Intn(4) == 0 {
+			return Action{Kind: Fold}
+		}
+		return raiseIfAllowed(view)
+	})
+}
+
+// Bots seats a bot for every player at the table, each one acts on its own turn only.
+type Bots map[string]Actor
+
+func (b Bots) Act(view TableView) Action {
+	bot, ok := b[view.Player]
+	if !ok {
+		return Action{Kind: Fold}
+	}
+	return bot.Act(view)
+}
+
+func foldOrCheck(view TableView) Action {
+	if view.ToCall > 0 {
+		return Action{Kind: Fold}
+	}
+	return Action{Kind: Check}
+}
+
+func checkOrCall(view TableView) Action {
+	if view.ToCall > 0 {
+		return Action{Kind: Call}
+	}
+	return Action{Kind: Check}
+}
+
+// raiseIfAllowed raises the minimum, or goes all-in when the bot has no more chips than that.
+func raiseIfAllowed(view TableView) Action {
+	if view.Pot >= botRaiseCap*view.MinRaise {
+		return checkOrCall(view)
+	}
+	if view.Stack > 0 && view.ToCall+view.MinRaise >= view.Stack {
+		return Action{Kind: AllIn}
+	}
+	return Action{Kind: Raise, Amount: view.MinRaise}
+}
+
+const (
+	weakHand = iota
+	playableHand
+	strongHand
+)
+
+// handStrength rates the hole cards before the flop, and the best hand made with the board after it.
+func handStrength(view TableView) int {
+	if len(view.Community) == 0 {
+		high, low := view.Hole[0].Rank, view.Hole[1].Rank
+		if low > high {
+			high, low = low, high
+		}
+		switch {
+		case high == low && high >= Ten, high == Ace && low >= King:
+			return strongHand
+		case high == low, low >= Ten, high == Ace:
+			return playableHand
+		}
+		return weakHand
+	}
+
+	value, err := EvaluateHand(append(append([]Card(nil), view.Hole...), view.Community...))
+	switch {
+	case err != nil:
+		return weakHand
+	case value.Category >= TwoPair:
+		return strongHand
+	case value.Category == OnePair:
+		return playableHand
+	}
+	return weakHand
+}
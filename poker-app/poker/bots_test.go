@@ -0,0 +1,109 @@
+package poker_test
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/ydsxiong/go-playground/poker-app/poker"
+)
+
+func TestBots(t *testing.T) {
+
+	t.Run("a tight bot folds rubbish and raises aces", func(t *testing.T) {
+		bot, err := poker.NewBot(poker.TightStrategy, nil)
+		assertNoError(t, err)
+
+		view := poker.TableView{Player: "tight", Hole: poker.MustParseCards("7c 2d"), Pot: 150, ToCall: 100, MinRaise: 100}
+		if got := bot.Act(view); got.Kind != poker.Fold {
+			t.Errorf("expected 7-2 to be folded, got %+v", got)
+		}
+
+		view.Hole = poker.MustParseCards("As Ad")
+		if got := bot.Act(view); got.Kind != poker.Raise || got.Amount != 100 {
+			t.Errorf("expected aces to be raised, got %+v", got)
+		}
+	})
+
+	t.Run("a bot short of chips goes all-in with aces and keeps its stack with a playable hand", func(t *testing.T) {
+		bot, _ := poker.NewBot(poker.TightStrategy, nil)
+
+		view := poker.TableView{Player: "tight", Hole: poker.MustParseCards("As Ad"), Pot: 150, ToCall: 100, MinRaise: 100, Stack: 150}
+		if got := bot.Act(view); got.Kind != poker.AllIn {
+			t.Errorf("expected aces to go all-in, got %+v", got)
+		}
+
+		view.Hole, view.Stack = poker.MustParseCards("Ah 9c"), 100
+		if got := bot.Act(view); got.Kind != poker.Fold {
+			t.Errorf("expected ace-nine not to be called with every chip, got %+v", got)
+		}
+	})
+
+	t.Run("a table full of aggressive bots still finishes the hand", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(1))
+		bots := poker.Bots{}
+		for _, name := range []string{"Chris", "Cleo", "Pepper"} {
+			bots[name], _ = poker.NewBot(poker.AggressiveStrategy, rnd)
+		}
+		hand, _ := poker.NewHand([]string{"Chris", "Cleo", "Pepper"}, nil, poker.NewShuffledDeck(rnd), 100, 0)
+
+		within(t, time.Second, func() {
+			result, err := hand.Play(bots, &bytes.Buffer{})
+			assertNoError(t, err)
+
+			total := 0
+			for _, bet := range result.Bets {
+				total += bet
+			}
+			if total != result.Pot {
+				t.Errorf("expected the bets %v to add up to the pot %d", result.Bets, result.Pot)
+			}
+		})
+	})
+
+	t.Run("it does not know other strategies", func(t *testing.T) {
+		if _, err := poker.NewBot("bluffer", nil); err == nil {
+			t.Error("expected an error for an unknown strategy")
+		}
+	})
+}
+
+func TestSimulation(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	bots := poker.Bots{}
+	for _, strategy := range poker.BotStrategies {
+		bots[strategy], _ = poker.NewBot(strategy, rnd)
+	}
+
+	store := poker.GetInMemoryStore()
+	games := poker.GetInMemoryGameStore()
+	clock := poker.NewVirtualClock(time.Now())
+	game := poker.NewGame(store, poker.ClockAlerter(clock), games, dummyStructures, clock)
+
+	sim := poker.NewSimulation(game, games, clock, bots, "")
+	sim.StartingStack = 1000
+	stats, err := sim.Play(20)
+	assertNoError(t, err)
+
+	if stats.Games != 20 || stats.AverageHands() < 1 || stats.AverageLength() != time.Duration(stats.Hands)*poker.DefaultHandDuration/20 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	levels, wins := 0, 0
+	for _, n := range stats.Levels {
+		levels += n
+	}
+	for _, n := range stats.Wins {
+		wins += n
+	}
+	if levels != 20 || wins < 20 {
+		t.Errorf("expected every game to finish at a level and have a winner, got %+v", stats)
+	}
+
+	for _, p := range store.GetLeagueTable() {
+		if p.Played != 20 {
+			t.Errorf("expected every bot to have played 20 games, got %+v", p)
+		}
+	}
+}
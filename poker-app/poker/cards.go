@@ -0,0 +1,141 @@
+package poker
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+type Suit int
+
+const (
+	Clubs Suit = iota
+	Diamonds
+	Hearts
+	Spades
+)
+
+const suitSymbols = "cdhs"
+
+func (s Suit) String() string {
+	return string(suitSymbols[s])
+}
+
+// Rank runs from Two (2) to Ace (14) so that ranks can be compared directly.
+type Rank int
+
+const (
+	Two Rank = iota + 2
+	Three
+	Four
+	Five
+	Six
+	Seven
+	Eight
+	Nine
+	Ten
+	Jack
+	Queen
+	King
+	Ace
+)
+
+const rankSymbols = "23456789TJQKA"
+
+func (r Rank) String() string {
+	return string(rankSymbols[r-Two])
+}
+
+type Card struct {
+	Rank Rank
+	Suit Suit
+}
+
+func (c Card) String() string {
+	return c.Rank.String() + c.Suit.String()
+}
+
+/**
+ParseCard reads the short notation used everywhere in the app, a rank followed by a suit,
+e.g. "As" for the ace of spades or "Td" for the ten of diamonds.
+*/
+func ParseCard(s string) (Card, error) {
+	if len(s) != 2 {
+		return Card{}, fmt.Errorf("invalid card %q", s)
+	}
+	r := strings.IndexByte(rankSymbols, strings.ToUpper(s[:1])[0])
+	u := strings.IndexByte(suitSymbols, strings.ToLower(s[1:])[0])
+	if r < 0 || u < 0 {
+		return Card{}, fmt.Errorf("invalid card %q", s)
+	}
+	return Card{Rank(r) + Two, Suit(u)}, nil
+}
+
+// MustParseCards parses a space separated list of cards, panicking on bad input.
+func MustParseCards(s string) []Card {
+	var cards []Card
+	for _, f := range strings.Fields(s) {
+		c, err := ParseCard(f)
+		if err != nil {
+			panic(err)
+		}
+		cards = append(cards, c)
+	}
+	return cards
+}
+
+func formatCards(cards []Card) string {
+	s := make([]string, len(cards))
+	for i, c := range cards {
+		s[i] = c.String()
+	}
+	return strings.Join(s, " ")
+}
+
+type Deck struct {
+	cards []Card
+}
+
+// NewDeck returns the 52 cards in a fixed order, call Shuffle before dealing from it.
+func NewDeck() *Deck {
+	d := &Deck{}
+	for s := Clubs; s <= Spades; s++ {
+		for r := Two; r <= Ace; r++ {
+			d.cards = append(d.cards, Card{r, s})
+		}
+	}
+	return d
+}
+
+/**
+NewStackedDeck returns a deck that deals the given cards in order,
+which is handy for replaying a known hand.
+*/
+func NewStackedDeck(cards ...Card) *Deck {
+	return &Deck{append([]Card(nil), cards...)}
+}
+
+func NewShuffledDeck(rnd *rand.Rand) *Deck {
+	d := NewDeck()
+	d.Shuffle(rnd)
+	return d
+}
+
+func (d *Deck) Shuffle(rnd *rand.Rand) {
+	rnd.Shuffle(len(d.cards), func(i, j int) {
+		d.cards[i], d.cards[j] = d.cards[j], d.cards[i]
+	})
+}
+
+func (d *Deck) Remaining() int {
+	return len(d.cards)
+}
+
+func (d *Deck) Draw(n int) ([]Card, error) {
+	if n > len(d.cards) {
+		return nil, fmt.Errorf("cannot draw %d cards, only %d left in the deck", n, len(d.cards))
+	}
+	drawn := append([]Card(nil), d.cards[:n]...)
+	d.cards = d.cards[n:]
+	return drawn, nil
+}
@@ -6,18 +6,40 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"text/tabwriter"
+	"time"
 )
 
 const PlayerPrompt = "Please enter the number of players: "
+const PlayerNamesPrompt = "Please enter the player names, separated by commas: "
+const BadPlayerInputErrMsg = "Bad value received for number of players, please try again with a number\n"
+const BadPlayerNamesErrMsg = "Bad value received for player names, please enter one name per player\n"
+const StructurePrompt = "Please enter the blind structure, or leave it blank for the standard one: "
+const BuyInPrompt = "Please enter the buy-in and payouts, e.g. 100 50/30/20, or leave it blank to play for fun: "
+const NewPlayerPrompt = "%s is not in the league, are they a new player? (y/n) "
+const UnknownPlayerErrMsg = "Not playing without %s, check the spelling or register them first\n"
+
+const HistoryCommand = "history"
 
 type CLI struct {
-	input  *bufio.Scanner
-	output io.Writer
-	game   Game
+	input      *bufio.Scanner
+	output     io.Writer
+	game       Game
+	games      GameStore
+	store      PlayerStore
+	structures *BlindStructures
+	control    *gameControl
 }
 
-func NewPokerCLI(input io.Reader, output io.Writer, game Game) *CLI {
-	return &CLI{bufio.NewScanner(input), output, game}
+func NewPokerCLI(input io.Reader, output io.Writer, game Game, games GameStore, store PlayerStore, structures *BlindStructures) *CLI {
+	return &CLI{
+		input:      bufio.NewScanner(input),
+		output:     output,
+		game:       game,
+		games:      games,
+		store:      store,
+		structures: structures,
+	}
 }
 
 func (pc *CLI) readline() string {
@@ -25,13 +47,165 @@ func (pc *CLI) readline() string {
 	return pc.input.Text()
 }
 
+// confirm asks the user a yes or no question, anything but yes is a no.
+func (pc *CLI) confirm(question string) bool {
+	fmt.Fprint(pc.output, question)
+	answer := strings.ToLower(strings.TrimSpace(pc.readline()))
+	return answer == "y" || answer == "yes"
+}
+
+// PlayPoker plays a single game, from asking for the players through to recording the winner.
 func (pc *CLI) PlayPoker() {
 	fmt.Fprint(pc.output, PlayerPrompt)
-	numberofplayers, _ := strconv.Atoi(pc.readline())
-	pc.game.Start(numberofplayers, pc.output)
-	pc.game.Finish(extractWinner(pc.readline()))
+	userInput := strings.TrimSpace(pc.readline())
+	for userInput == HistoryCommand {
+		pc.printHistory()
+		fmt.Fprint(pc.output, PlayerPrompt)
+		userInput = strings.TrimSpace(pc.readline())
+	}
+
+	numberofplayers, err := strconv.Atoi(userInput)
+	if err != nil || numberofplayers < MinPlayersPerHand {
+		fmt.Fprint(pc.output, BadPlayerInputErrMsg)
+		return
+	}
+
+	fmt.Fprint(pc.output, PlayerNamesPrompt)
+	players := extractPlayers(pc.readline())
+	if len(players) != numberofplayers {
+		fmt.Fprint(pc.output, BadPlayerNamesErrMsg)
+		return
+	}
+	players, ok := pc.checkPlayers(players)
+	if !ok {
+		return
+	}
+
+	fmt.Fprint(pc.output, StructurePrompt)
+	structure := strings.TrimSpace(pc.readline())
+
+	fmt.Fprint(pc.output, BuyInPrompt)
+	buyIn, err := ParseBuyIn(pc.readline())
+	if err != nil {
+		fmt.Fprintf(pc.output, "problem with the buy-in, %v\n", err)
+		return
+	}
+
+	alerts, err := pc.game.Start(numberofplayers, structure, buyIn, pc.output)
+	if err != nil {
+		pc.game.Abort()
+		fmt.Fprintf(pc.output, "problem starting the game, %v\n", err)
+		return
+	}
+	defer alerts.Cancel()
+	pc.control = &gameControl{alerts: alerts}
+
+	result, err := pc.game.PlayHand(players, nil, ActorFunc(pc.askForAction), pc.output)
+	if err == ErrHandAborted {
+		pc.game.Abort()
+		fmt.Fprintln(pc.output, GameAbortedMsg)
+		return
+	}
+	if err != nil {
+		pc.game.Abort()
+		fmt.Fprintf(pc.output, "problem playing the hand, %v\n", err)
+		return
+	}
+	placings := pc.game.Finish(players, result)
+	if buyIn.Amount > 0 {
+		printPlacings(pc.output, placings)
+	}
+}
+
+/**
+checkPlayers puts the names typed in as the league knows them, an alias becomes the player's name.
+Once the league has players, a name it does not know is as likely a typo as a new player,
+so the user has to say it is a new player or the game is not played.
+*/
+func (pc *CLI) checkPlayers(names []string) ([]string, bool) {
+	league := pc.store.GetLeagueTable()
+	seen := map[string]bool{}
+	var players []string
+	for _, name := range names {
+		if player := league.Lookup(name); player != nil {
+			name = player.Name
+		} else if len(league) > 0 && !pc.confirm(fmt.Sprintf(NewPlayerPrompt, name)) {
+			fmt.Fprintf(pc.output, UnknownPlayerErrMsg, name)
+			return nil, false
+		}
+		if seen[name] {
+			fmt.Fprint(pc.output, BadPlayerNamesErrMsg)
+			return nil, false
+		}
+		seen[name] = true
+		players = append(players, name)
+	}
+	return players, true
+}
+
+func printPlacings(out io.Writer, placings []Placing) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Place\tPlayer\tPrize\tProfit")
+	for _, p := range placings {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%+d\n", p.Position, p.Name, p.Prize, p.Profit())
+	}
+	w.Flush()
+}
+
+func (pc *CLI) printHistory() {
+	games, err := pc.games.GetGames()
+	if err != nil {
+		fmt.Fprintf(pc.output, "problem loading the game history, %v\n", err)
+		return
+	}
+
+	w := tabwriter.NewWriter(pc.output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Game\tStarted\tPlayers\tBlind levels\tDuration\tWinner")
+	for _, g := range games {
+		duration := "running"
+		if g.FinishedAt != nil {
+			duration = g.Duration().Round(time.Second).String()
+		}
+		winner := g.Winner
+		if g.Aborted {
+			winner = "aborted"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%s\t%s\n",
+			g.ID, g.StartedAt.Format("2006-01-02 15:04"), g.Players, g.BlindLevels, duration, winner)
+	}
+	w.Flush()
+}
+
+/**
+askForAction shows the player whose turn it is their cards and reads their action,
+a closed input aborts the game, so it can neither hang waiting on a terminal that has gone away
+nor hand the pot to whoever happened to be left in when the input ran out.
+Pause and resume can be typed in place of an action, abort ends the game.
+*/
+func (pc *CLI) askForAction(view TableView) Action {
+	for {
+		fmt.Fprintf(pc.output, "%s: ", view)
+		if !pc.input.Scan() {
+			return Action{Kind: Abort}
+		}
+		if msg, ok := pc.control.command(pc.input.Text()); ok {
+			fmt.Fprintln(pc.output, msg)
+			continue
+		}
+		action, err := ParseAction(pc.input.Text())
+		if err == nil {
+			return action
+		}
+		fmt.Fprintln(pc.output, err)
+	}
 }
 
-func extractWinner(userInput string) string {
-	return strings.Replace(userInput, " wins", "", 1)
+func extractPlayers(userInput string) []string {
+	var players []string
+	for _, name := range strings.Split(userInput, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			players = append(players, name)
+		}
+	}
+	return players
 }
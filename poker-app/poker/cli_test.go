@@ -3,7 +3,9 @@ package poker_test
 import (
 	"bytes"
 	"io"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,19 +15,63 @@ import (
 var dummyStdIn = &bytes.Buffer{}
 var dummyStdOut = &bytes.Buffer{}
 
+// GameSpy records how it was played, under a lock as games are often played from a server's goroutine while a test is asserting.
 type GameSpy struct {
-	startedWith  int
-	finishedWith string
+	lock             sync.Mutex
+	startedWith      int
+	startedStructure string
+	startedBuyIn     poker.BuyIn
+	playedWith       []string
+	finishedPlayers  []string
+	finishedWith     string
 
 	BlindAlert []byte
+	HandWinner string
+	Alerts     SpyAlertHandle
 }
 
-func (gs *GameSpy) Start(numberOfPlayers int, to io.Writer) {
+func (gs *GameSpy) Start(numberOfPlayers int, structure string, buyIn poker.BuyIn, to io.Writer) (poker.AlertHandle, error) {
+	gs.lock.Lock()
+	defer gs.lock.Unlock()
 	gs.startedWith = numberOfPlayers
+	gs.startedStructure = structure
+	gs.startedBuyIn = buyIn
 	to.Write(gs.BlindAlert)
+	return &gs.Alerts, nil
 }
-func (gs *GameSpy) Finish(winner string) {
-	gs.finishedWith = winner
+func (gs *GameSpy) PlayHand(players []string, stacks map[string]int, actor poker.Actor, to io.Writer) (poker.HandResult, error) {
+	gs.lock.Lock()
+	defer gs.lock.Unlock()
+	gs.playedWith = players
+	return poker.HandResult{Winners: []string{gs.HandWinner}}, nil
+}
+func (gs *GameSpy) Finish(players []string, result poker.HandResult) []poker.Placing {
+	gs.lock.Lock()
+	defer gs.lock.Unlock()
+	gs.finishedPlayers = players
+	gs.finishedWith = strings.Join(result.Winners, ", ")
+	return gs.startedBuyIn.Placings(players, result)
+}
+
+func (gs *GameSpy) FinishUnranked(result poker.HandResult) {}
+func (gs *GameSpy) Abort()                                 {}
+
+func (gs *GameSpy) StartedWith() int {
+	gs.lock.Lock()
+	defer gs.lock.Unlock()
+	return gs.startedWith
+}
+
+func (gs *GameSpy) PlayedWith() []string {
+	gs.lock.Lock()
+	defer gs.lock.Unlock()
+	return gs.playedWith
+}
+
+func (gs *GameSpy) FinishedWith() string {
+	gs.lock.Lock()
+	defer gs.lock.Unlock()
+	return gs.finishedWith
 }
 
 func TestCLI(t *testing.T) {
@@ -36,36 +82,190 @@ func TestCLI(t *testing.T) {
 	}
 	defer clean()
 
-	playerStore, e := poker.NewFileSystemPlayerStore(db)
+	playerStore, e := poker.NewFileSystemPlayerStore(db, poker.RealClock{})
 	if e != nil {
 		t.Errorf("problem initialising player db file, %v", e)
 	}
 
 	t.Run("it prompts the user to enter the number of players", func(t *testing.T) {
 		stdout := &bytes.Buffer{}
-		cli := poker.NewPokerCLI(dummyStdIn, stdout, poker.NewGame(playerStore, dummySpyAlerter))
+		cli := poker.NewPokerCLI(dummyStdIn, stdout, poker.NewGame(playerStore, dummySpyAlerter, dummyGameStore, dummyStructures, poker.RealClock{}), dummyGameStore, dummyPlayerStore, dummyStructures)
 		cli.PlayPoker()
 
 		got := stdout.String()
-		want := poker.PlayerPrompt //"Please enter the number of players: "
+		want := poker.PlayerPrompt + poker.BadPlayerInputErrMsg
 
 		if got != want {
 			t.Errorf("got %q, want %q", got, want)
 		}
 	})
 
-	t.Run("start game with 3 players and finish game with 'Chris' as winner", func(t *testing.T) {
-		game := &GameSpy{}
+	t.Run("start game with 3 players and finish game with the hand winner 'Chris'", func(t *testing.T) {
+		game := &GameSpy{HandWinner: "Chris"}
 
 		out := &bytes.Buffer{}
-		in := userSends("3", "Chris wins")
+		in := userSends("3", "Cleo, Chris, Pepper", "turbo")
 
-		poker.NewPokerCLI(in, out, game).PlayPoker()
+		poker.NewPokerCLI(in, out, game, dummyGameStore, dummyPlayerStore, dummyStructures).PlayPoker()
 
-		assertMessagesSentToUser(t, out, poker.PlayerPrompt)
+		assertMessagesSentToUser(t, out, poker.PlayerPrompt, poker.PlayerNamesPrompt, poker.StructurePrompt, poker.BuyInPrompt)
 		assertGameStartedWith(t, game, 3)
+		if game.startedStructure != "turbo" {
+			t.Errorf("wanted the game started with the turbo structure, got %q", game.startedStructure)
+		}
+		assertPlayedWith(t, game, "Cleo", "Chris", "Pepper")
 		assertFinishCalledWith(t, game, "Chris")
 	})
+
+	t.Run("it does not start a game when the names do not match the number of players", func(t *testing.T) {
+		game := &GameSpy{}
+
+		out := &bytes.Buffer{}
+		in := userSends("3", "Cleo, Chris")
+
+		poker.NewPokerCLI(in, out, game, dummyGameStore, dummyPlayerStore, dummyStructures).PlayPoker()
+
+		assertMessagesSentToUser(t, out, poker.PlayerPrompt, poker.PlayerNamesPrompt, poker.BadPlayerNamesErrMsg)
+		if game.StartedWith() != 0 {
+			t.Errorf("game should not have started, but was started with %d players", game.StartedWith())
+		}
+	})
+
+	t.Run("it plays the hand with the actions typed in and records the winner", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		in := userSends("2", "Chris, Cleo", "", "", "check", "fold")
+
+		poker.NewPokerCLI(in, out, poker.NewGame(playerStore, dummySpyAlerter, dummyGameStore, dummyStructures, poker.RealClock{}), dummyGameStore, dummyPlayerStore, dummyStructures).PlayPoker()
+
+		if !strings.Contains(out.String(), "cannot check") {
+			t.Errorf("expected the invalid check to be reported, got %q", out.String())
+		}
+		assertPlayerWin(t, playerStore, "Cleo")
+	})
+}
+
+func TestCLIHistory(t *testing.T) {
+	games := poker.GetInMemoryGameStore()
+	started := time.Date(2026, time.October, 1, 19, 0, 0, 0, time.UTC)
+	id, _ := games.StartGame(started, 3)
+	games.FinishGame(id, started.Add(45*time.Minute), 2, "Chris")
+	id, _ = games.StartGame(started.Add(time.Hour), 2)
+	games.AbortGame(id, started.Add(70*time.Minute), 1)
+
+	out := &bytes.Buffer{}
+	in := userSends("history")
+
+	poker.NewPokerCLI(in, out, &GameSpy{}, games, dummyPlayerStore, dummyStructures).PlayPoker()
+
+	want := poker.PlayerPrompt +
+		"Game  Started           Players  Blind levels  Duration  Winner\n" +
+		"1     2026-10-01 19:00  3        2             45m0s     Chris\n" +
+		"2     2026-10-01 20:00  2        1             10m0s     aborted\n" +
+		poker.PlayerPrompt + poker.BadPlayerInputErrMsg
+	if out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+}
+
+func TestCLIBlindStructures(t *testing.T) {
+	structures, _ := poker.NewBlindStructures(poker.BlindStructure{
+		Name:   "turbo",
+		Levels: []poker.BlindLevel{{Blind: 100, Minutes: 3}, {Break: true, Minutes: 5}, {Blind: 200, Ante: 25, Minutes: 3}},
+	})
+
+	t.Run("it schedules the levels of the chosen structure", func(t *testing.T) {
+		blindAlerter := &SpyBlindAlerter{}
+		game := poker.NewGame(poker.GetInMemoryStore(), blindAlerter, dummyGameStore, structures, poker.RealClock{})
+
+		poker.NewPokerCLI(userSends("2", "Chris, Cleo", "turbo"), dummyStdOut, game, dummyGameStore, dummyPlayerStore, dummyStructures).PlayPoker()
+
+		want := []scheduleAlert{{0, 100}, {3 * time.Minute, 0}, {8 * time.Minute, 200}}
+		if !reflect.DeepEqual(blindAlerter.alert, alerts(want)) {
+			t.Errorf("got alerts %v, want %v", blindAlerter.alert, want)
+		}
+	})
+
+	t.Run("it reports an unknown structure", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		game := poker.NewGame(poker.GetInMemoryStore(), dummySpyAlerter, dummyGameStore, structures, poker.RealClock{})
+
+		poker.NewPokerCLI(userSends("2", "Chris, Cleo", "marathon"), out, game, dummyGameStore, dummyPlayerStore, dummyStructures).PlayPoker()
+
+		if !strings.Contains(out.String(), "unknown blind structure marathon") {
+			t.Errorf("expected the unknown structure to be reported, got %q", out.String())
+		}
+	})
+}
+
+func TestCLIPauseResumeAbort(t *testing.T) {
+
+	t.Run("it pauses the blinds and refuses actions until the game is resumed", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		blindAlerter := &SpyBlindAlerter{}
+		store := poker.GetInMemoryStore()
+		game := poker.NewGame(store, blindAlerter, dummyGameStore, dummyStructures, poker.RealClock{})
+
+		poker.NewPokerCLI(userSends("2", "Chris, Cleo", "", "", "pause", "fold", "resume", "fold"), out, game, dummyGameStore, dummyPlayerStore, dummyStructures).PlayPoker()
+
+		if strings.Count(out.String(), poker.GamePausedMsg) != 2 || !strings.Contains(out.String(), poker.GameResumedMsg) {
+			t.Errorf("expected the game to be paused until resumed, got %q", out.String())
+		}
+		for _, h := range blindAlerter.handles {
+			if h.paused != 1 || h.resumed != 1 || h.cancelled != 1 {
+				t.Fatalf("expected every alert paused, resumed and finally cancelled once, got paused %d, resumed %d, cancelled %d", h.paused, h.resumed, h.cancelled)
+			}
+		}
+		assertScore(t, store, "Cleo", 1)
+	})
+
+	t.Run("abort cancels the blinds and records no result", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		blindAlerter := &SpyBlindAlerter{}
+		store := poker.GetInMemoryStore()
+		games := poker.GetInMemoryGameStore()
+		game := poker.NewGame(store, blindAlerter, games, dummyStructures, poker.RealClock{})
+
+		poker.NewPokerCLI(userSends("2", "Chris, Cleo", "", "", "abort"), out, game, games, dummyPlayerStore, dummyStructures).PlayPoker()
+
+		if !strings.HasSuffix(out.String(), poker.GameAbortedMsg+"\n") {
+			t.Errorf("expected the game to be aborted, got %q", out.String())
+		}
+		if blindAlerter.handles[0].cancelled != 1 {
+			t.Error("expected the blind alerts to be cancelled")
+		}
+		if len(store.GetLeagueTable()) != 0 {
+			t.Errorf("expected no result recorded, got %v", store.GetLeagueTable())
+		}
+		if record, _ := games.GetGame(1); !record.Aborted || record.FinishedAt == nil {
+			t.Errorf("expected the game to be recorded as aborted, got %+v", record)
+		}
+	})
+
+	t.Run("input that runs out mid hand aborts the game and records no win", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		store := poker.GetInMemoryStore()
+		games := poker.GetInMemoryGameStore()
+		game := poker.NewGame(store, dummySpyAlerter, games, dummyStructures, poker.RealClock{})
+
+		poker.NewPokerCLI(userSends("2", "Chris, Cleo", "", "", "call"), out, game, games, dummyPlayerStore, dummyStructures).PlayPoker()
+
+		if !strings.HasSuffix(out.String(), poker.GameAbortedMsg+"\n") {
+			t.Errorf("expected the game to be aborted, got %q", out.String())
+		}
+		if len(store.GetLeagueTable()) != 0 {
+			t.Errorf("expected no win recorded, got %v", store.GetLeagueTable())
+		}
+		if record, _ := games.GetGame(1); !record.Aborted {
+			t.Errorf("expected the game to be recorded as aborted, got %+v", record)
+		}
+	})
+}
+
+func assertPlayedWith(t *testing.T, game *GameSpy, players ...string) {
+	t.Helper()
+	if !reflect.DeepEqual(game.PlayedWith(), players) {
+		t.Errorf("wanted hand played with %v but got %v", players, game.PlayedWith())
+	}
 }
 
 func userSends(messages ...string) io.Reader {
@@ -75,20 +275,20 @@ func userSends(messages ...string) io.Reader {
 func assertGameStartedWith(t *testing.T, game *GameSpy, numberOfPlayersWanted int) {
 	t.Helper()
 	passed := retryUntil(500*time.Millisecond, func() bool {
-		return game.startedWith == numberOfPlayersWanted
+		return game.StartedWith() == numberOfPlayersWanted
 	})
 	if !passed {
-		t.Errorf("wanted Start called with %d but got %d", numberOfPlayersWanted, game.startedWith)
+		t.Errorf("wanted Start called with %d but got %d", numberOfPlayersWanted, game.StartedWith())
 	}
 }
 
 func assertFinishCalledWith(t *testing.T, game *GameSpy, winner string) {
 	t.Helper()
 	passed := retryUntil(500*time.Millisecond, func() bool {
-		return game.finishedWith == winner
+		return game.FinishedWith() == winner
 	})
 	if !passed {
-		t.Errorf("expected finish called with %q but got %q", winner, game.finishedWith)
+		t.Errorf("expected finish called with %q but got %q", winner, game.FinishedWith())
 	}
 }
 
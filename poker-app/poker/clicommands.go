@@ -0,0 +1,376 @@
+package poker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const CommandPrompt = "poker> "
+const UnknownCommandErrMsg = "unknown command %q, type help to see what you can do\n"
+const GoodbyeMsg = "Bye for now\n"
+
+/**
+cliCommand is one of the commands of the REPL, args are whatever was typed after its name.
+The commands are looked up by name in Run, help lists them in the order they are returned here.
+*/
+type cliCommand struct {
+	name  string
+	args  string
+	about string
+	run   func(pc *CLI, args []string) error
+}
+
+func cliCommands() []cliCommand {
+	return []cliCommand{
+		{"start", "", "start a game", func(pc *CLI, args []string) error { pc.PlayPoker(); return nil }},
+		{"league", "[wins|rating|cashes|profit] [season]", "show the league table of all time or of a season like 2026-Q3, ranked by wins unless told otherwise", (*CLI).printLeague},
+		{"champions", "", "show the champion of every season that is over", (*CLI).printChampions},
+		{"score", "<name>", "show how many games a player has won", (*CLI).printScore},
+		{"profit", "<name>", "show what a player has paid in, won and is up or down overall", (*CLI).printProfit},
+		{"undo", "", "undo the last change to the league, a result or a change to its players", (*CLI).undoLastWin},
+		{"players", "", "list everybody who has played", (*CLI).printPlayers},
+		{"register", "<name> [alias...]", "add a player to the league, or give one that is in it more aliases", (*CLI).registerPlayer},
+		{"rename", "<name> <new name>", "rename a player, the old name stays on as an alias", (*CLI).renamePlayer},
+		{"merge", "<name> <into>", "add a player's record to another's, for when somebody played under two names", (*CLI).mergePlayers},
+		{"delete", "<name>", "take a player out of the league", (*CLI).deletePlayer},
+		{"export", "<file>", "write the league to a .csv or .json file", (*CLI).exportLeague},
+		{"import", "<file> [sum|replace|max]", "merge a league from a .csv or .json file, adding up results unless told otherwise", (*CLI).importLeague},
+		{"structure", "[name]", "list the blind structures, or show the levels of one", (*CLI).printStructure},
+		{HistoryCommand, "", "show the games played so far", func(pc *CLI, args []string) error { pc.printHistory(); return nil }},
+		{"help", "", "show this list", (*CLI).printHelp},
+		{"quit", "", "leave", nil},
+	}
+}
+
+/**
+Run reads commands until the input runs out or the user quits. A command that fails
+reports why and the loop carries on, nothing typed at the prompt can end it by mistake.
+*/
+func (pc *CLI) Run() {
+	for {
+		fmt.Fprint(pc.output, CommandPrompt)
+		if !pc.input.Scan() {
+			fmt.Fprint(pc.output, "\n"+GoodbyeMsg)
+			return
+		}
+		fields := strings.Fields(pc.input.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		command, ok := findCommand(strings.ToLower(fields[0]))
+		switch {
+		case !ok:
+			fmt.Fprintf(pc.output, UnknownCommandErrMsg, fields[0])
+		case command.run == nil:
+			fmt.Fprint(pc.output, GoodbyeMsg)
+			return
+		default:
+			if err := command.run(pc, fields[1:]); err != nil {
+				fmt.Fprintf(pc.output, "%s: %v\n", command.name, err)
+			}
+		}
+	}
+}
+
+func findCommand(name string) (cliCommand, bool) {
+	for _, c := range cliCommands() {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return cliCommand{}, false
+}
+
+func (pc *CLI) printLeague(args []string) error {
+	rank, season := "", ""
+	for _, arg := range args {
+		if _, err := ParseSeason(arg); err == nil || arg == AllTime || arg == CurrentSeason {
+			season = arg
+		} else {
+			rank = arg
+		}
+	}
+	league, err := SeasonLeague(pc.store, season, time.Now())
+	if err != nil {
+		return err
+	}
+	standings, err := league.Standings(rank)
+	if err != nil {
+		return err
+	}
+	if len(standings) == 0 {
+		fmt.Fprintln(pc.output, "Nobody has played yet")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(pc.output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tName\tWins\tPlayed\tWin %\tRating\tCashes\tProfit")
+	for i, s := range standings {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%.1f\t%.1f\t%d\t%+d\n", i+1, s.Name, s.Wins, s.Played, s.WinPercentage, s.Rating, s.Cashes, s.Profit)
+	}
+	return w.Flush()
+}
+
+func (pc *CLI) printChampions(args []string) error {
+	champions := Champions(pc.store.GetResults(), time.Now())
+	if len(champions) == 0 {
+		fmt.Fprintln(pc.output, "No season has been won yet")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(pc.output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Season\tChampion\tWins\tPlayed")
+	for _, c := range champions {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\n", c.Season, c.Name, c.Wins, c.Played)
+	}
+	return w.Flush()
+}
+
+func (pc *CLI) printScore(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expecting one player name, e.g. score Chris")
+	}
+	player := pc.store.GetLeagueTable().Lookup(args[0])
+	if player == nil {
+		return fmt.Errorf("unknown player %s", args[0])
+	}
+	fmt.Fprintf(pc.output, "%s has won %d of %d games\n", player.Name, player.Wins, player.GamesPlayed())
+	return nil
+}
+
+func (pc *CLI) printProfit(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expecting one player name, e.g. profit Chris")
+	}
+	player := pc.store.GetLeagueTable().Lookup(args[0])
+	if player == nil {
+		return fmt.Errorf("unknown player %s", args[0])
+	}
+	fmt.Fprintf(pc.output, "%s has paid in %d, won %d and cashed %d times, %+d overall\n",
+		player.Name, player.BuyIns, player.Winnings, player.Cashes, player.Profit())
+	return nil
+}
+
+func (pc *CLI) undoLastWin(args []string) error {
+	if err := pc.store.UndoLastWin(); err != nil {
+		return err
+	}
+	fmt.Fprintln(pc.output, "The last change to the league has been undone")
+	return nil
+}
+
+func (pc *CLI) registerPlayer(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("expecting a player name and maybe some aliases, e.g. register Chris CJ")
+	}
+	if err := pc.store.RegisterPlayer(args[0], "", args[1:]...); err != nil {
+		return err
+	}
+	fmt.Fprintf(pc.output, "%s is registered\n", args[0])
+	return nil
+}
+
+func (pc *CLI) renamePlayer(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("expecting the player and their new name, e.g. rename Chirs Chris")
+	}
+	if err := pc.store.RenamePlayer(args[0], args[1]); err != nil {
+		return err
+	}
+	fmt.Fprintf(pc.output, "%s is now %s\n", args[0], args[1])
+	return nil
+}
+
+func (pc *CLI) mergePlayers(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("expecting the player to merge and who into, e.g. merge Chirs Chris")
+	}
+	if err := pc.store.MergePlayers(args[0], args[1]); err != nil {
+		return err
+	}
+	fmt.Fprintf(pc.output, "%s has been merged into %s\n", args[0], args[1])
+	return nil
+}
+
+func (pc *CLI) deletePlayer(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expecting one player name, e.g. delete Chris")
+	}
+	if err := pc.store.DeletePlayer(args[0]); err != nil {
+		return err
+	}
+	fmt.Fprintf(pc.output, "%s has been taken out of the league, undo puts them back\n", args[0])
+	return nil
+}
+
+// leagueFileFormat tells the format of a league file from its extension, anything but .csv is JSON.
+func leagueFileFormat(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return CSVFormat
+	}
+	return JSONFormat
+}
+
+func (pc *CLI) exportLeague(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expecting a file to export to, e.g. export league.csv")
+	}
+	file, err := os.Create(args[0])
+	if err != nil {
+		return fmt.Errorf("problem creating %s, %v", args[0], err)
+	}
+	defer file.Close()
+
+	league := pc.store.GetLeagueTable()
+	sortLeague(league)
+	if leagueFileFormat(args[0]) == CSVFormat {
+		err = WriteLeagueCSV(file, league)
+	} else {
+		err = json.NewEncoder(file).Encode(league)
+	}
+	if err != nil {
+		return fmt.Errorf("problem writing %s, %v", args[0], err)
+	}
+	fmt.Fprintf(pc.output, "Exported %d players to %s\n", len(league), args[0])
+	return nil
+}
+
+/**
+importLeague shows what merging the league in the file would do before doing it,
+nothing is changed unless the user says yes.
+*/
+func (pc *CLI) importLeague(args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("expecting a file to import and maybe how to merge it, e.g. import league.csv max")
+	}
+	strategy := MergeSum
+	if len(args) == 2 {
+		strategy = strings.ToLower(args[1])
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("problem opening %s, %v", args[0], err)
+	}
+	imported, err := ReadLeague(file, leagueFileFormat(args[0]))
+	file.Close()
+	if err != nil {
+		return err
+	}
+
+	_, report, err := MergeLeagues(pc.store.GetLeagueTable(), imported, strategy)
+	if err != nil {
+		return err
+	}
+	pc.printMergeReport(report)
+	if len(report.Added) == 0 && len(report.Conflicts) == 0 {
+		fmt.Fprintln(pc.output, "Nothing to import")
+		return nil
+	}
+
+	if !pc.confirm("Import? (y/n) ") {
+		fmt.Fprintln(pc.output, "Nothing has been imported")
+		return nil
+	}
+	if _, err := pc.store.ImportLeague(imported, strategy); err != nil {
+		return err
+	}
+	fmt.Fprintln(pc.output, "The league has been imported")
+	return nil
+}
+
+func (pc *CLI) printMergeReport(report MergeReport) {
+	fmt.Fprintf(pc.output, "Merging with %s: %d new, %d changed, %d unchanged\n",
+		report.Strategy, len(report.Added), len(report.Conflicts), report.Unchanged)
+	if len(report.Added) > 0 {
+		fmt.Fprintf(pc.output, "New players: %s\n", strings.Join(report.Added, ", "))
+	}
+	if len(report.Conflicts) == 0 {
+		return
+	}
+	w := tabwriter.NewWriter(pc.output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Name\tWins\tPlayed\tRating\tCashes\tBuyIns\tWinnings")
+	for _, c := range report.Conflicts {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", c.Name,
+			mergedField(c.Ours.Wins, c.Merged.Wins),
+			mergedField(c.Ours.Played, c.Merged.Played),
+			mergedField(c.Ours.Rating, c.Merged.Rating),
+			mergedField(c.Ours.Cashes, c.Merged.Cashes),
+			mergedField(c.Ours.BuyIns, c.Merged.BuyIns),
+			mergedField(c.Ours.Winnings, c.Merged.Winnings))
+	}
+	w.Flush()
+}
+
+// mergedField shows a number a merge changes as "before -> after"
+func mergedField(ours, merged interface{}) string {
+	if ours == merged {
+		return fmt.Sprint(ours)
+	}
+	return fmt.Sprintf("%v -> %v", ours, merged)
+}
+
+func (pc *CLI) printPlayers(args []string) error {
+	league := pc.store.GetLeagueTable()
+	if len(league) == 0 {
+		fmt.Fprintln(pc.output, "Nobody has played yet")
+		return nil
+	}
+	var names []string
+	for _, p := range league {
+		names = append(names, p.Name)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(pc.output, strings.Join(names, "\n"))
+	return nil
+}
+
+func (pc *CLI) printStructure(args []string) error {
+	if len(args) == 0 {
+		w := tabwriter.NewWriter(pc.output, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "Structure\tLevels")
+		fmt.Fprintf(w, "%s\t%d\n", StandardBlindStructure, len(standardBlinds))
+		for _, s := range pc.structures.List() {
+			if s.Name != StandardBlindStructure {
+				fmt.Fprintf(w, "%s\t%d\n", s.Name, len(s.Levels))
+			}
+		}
+		return w.Flush()
+	}
+
+	// the standard structure depends on the number of players, it is shown for a full table
+	structure, err := pc.structures.Get(args[0], MaxPlayersPerHand)
+	if err != nil {
+		return err
+	}
+	return printLevels(pc.output, structure)
+}
+
+func printLevels(out io.Writer, structure BlindStructure) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Level\tBlind\tAnte\tMinutes")
+	for i, l := range structure.Levels {
+		if l.Break {
+			fmt.Fprintf(w, "%d\tbreak\t\t%d\n", i+1, l.Minutes)
+			continue
+		}
+		fmt.Fprintf(w, "%d\t%d\t%d\t%d\n", i+1, l.Blind, l.Ante, l.Minutes)
+	}
+	return w.Flush()
+}
+
+func (pc *CLI) printHelp(args []string) error {
+	w := tabwriter.NewWriter(pc.output, 0, 0, 2, ' ', 0)
+	for _, c := range cliCommands() {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", c.name, c.args, c.about)
+	}
+	return w.Flush()
+}
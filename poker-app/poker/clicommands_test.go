@@ -0,0 +1,159 @@
+package poker_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ydsxiong/go-playground/poker-app/poker"
+)
+
+func TestCLICommands(t *testing.T) {
+	structures, _ := poker.NewBlindStructures(poker.BlindStructure{
+		Name:   "turbo",
+		Levels: []poker.BlindLevel{{Blind: 100, Minutes: 3}, {Break: true, Minutes: 5}, {Blind: 200, Ante: 25, Minutes: 3}},
+	})
+
+	runCommands := func(store poker.PlayerStore, game poker.Game, commands ...string) string {
+		out := &bytes.Buffer{}
+		poker.NewPokerCLI(userSends(commands...), out, game, dummyGameStore, store, structures).Run()
+		return out.String()
+	}
+
+	t.Run("it prints the league as a table", func(t *testing.T) {
+		store := poker.GetInMemoryStore()
+		store.RecordGame([]string{"Chris", "Cleo"}, []string{"Cleo"})
+
+		got := runCommands(store, &GameSpy{}, "league")
+
+		want := poker.CommandPrompt +
+			"#  Name   Wins  Played  Win %  Rating  Cashes  Profit\n" +
+			"1  Cleo   1     1       100.0  1516.0  0       +0\n" +
+			"2  Chris  0     1       0.0    1484.0  0       +0\n" +
+			poker.CommandPrompt + "\n" + poker.GoodbyeMsg
+		if got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("it shows the score of a player and reports unknown ones", func(t *testing.T) {
+		store := poker.GetInMemoryStore(map[string]int{"Pepper": 3})
+
+		got := runCommands(store, &GameSpy{}, "score Pepper", "score Floyd", "score")
+
+		for _, want := range []string{"Pepper has won 3 of 3 games\n", "score: unknown player Floyd\n", "score: expecting one player name"} {
+			if !strings.Contains(got, want) {
+				t.Errorf("expected %q in %q", want, got)
+			}
+		}
+	})
+
+	t.Run("it finds a player by an alias, whatever the case", func(t *testing.T) {
+		store := poker.GetInMemoryStore(map[string]int{"Chris": 2})
+		assertNoError(t, store.RegisterPlayer("Chris", "Chris James", "CJ"))
+
+		got := runCommands(store, &GameSpy{}, "score cj", "score CHRIS", "profit CJ")
+
+		if strings.Count(got, "Chris has won 2 of 2 games\n") != 2 {
+			t.Errorf("expected the score to be found by the alias and by the name in any case, got %q", got)
+		}
+		if want := "Chris has paid in 0, won 0 and cashed 0 times, +0 overall\n"; !strings.Contains(got, want) {
+			t.Errorf("expected %q in %q", want, got)
+		}
+	})
+
+	t.Run("undo takes back the last win and says when there is nothing left", func(t *testing.T) {
+		store := poker.GetInMemoryStore()
+		store.RecordWin("Chris")
+		store.RecordWin("Chris")
+
+		got := runCommands(store, &GameSpy{}, "undo", "undo", "undo")
+
+		if len(store.GetLeagueTable()) != 0 {
+			t.Errorf("expected both wins taken back, got %v", store.GetLeagueTable())
+		}
+		if strings.Count(got, "The last change to the league has been undone\n") != 2 {
+			t.Errorf("expected both undos to be reported, got %q", got)
+		}
+		if !strings.Contains(got, "undo: "+poker.ErrNothingToUndo.Error()) {
+			t.Errorf("expected the third undo to fail, got %q", got)
+		}
+	})
+
+	t.Run("it exports the league and imports it once the user agrees", func(t *testing.T) {
+		dir, err := ioutil.TempDir(".", "league")
+		assertNoError(t, err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "league.csv")
+
+		office := poker.GetInMemoryStore(map[string]int{"Chris": 2, "Pepper": 4})
+		runCommands(office, &GameSpy{}, "export "+path)
+
+		store := poker.GetInMemoryStore(map[string]int{"Chris": 3})
+		got := runCommands(store, &GameSpy{}, "import "+path, "n")
+		if !strings.Contains(got, "Merging with sum: 1 new, 1 changed, 0 unchanged\nNew players: Pepper\n") ||
+			!strings.Contains(got, "Chris  3 -> 5") || !strings.Contains(got, "Nothing has been imported") {
+			t.Errorf("expected the import to be shown and not done, got %q", got)
+		}
+		assertScore(t, store, "Chris", 3)
+
+		runCommands(store, &GameSpy{}, "import "+path+" max", "y")
+		assertScore(t, store, "Chris", 3)
+		assertScore(t, store, "Pepper", 4)
+	})
+
+	t.Run("it lists the players in alphabetical order", func(t *testing.T) {
+		store := poker.GetInMemoryStore(map[string]int{"Pepper": 3, "Cleo": 1, "Ruth": 2})
+
+		got := runCommands(store, &GameSpy{}, "players", "quit")
+
+		want := poker.CommandPrompt + "Cleo\nPepper\nRuth\n" + poker.CommandPrompt + poker.GoodbyeMsg
+		if got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("it lists the structures and shows the levels of one", func(t *testing.T) {
+		got := runCommands(poker.GetInMemoryStore(), &GameSpy{}, "structure", "structure turbo", "structure marathon")
+
+		want := poker.CommandPrompt +
+			"Structure  Levels\n" +
+			"standard   11\n" +
+			"turbo      3\n" +
+			poker.CommandPrompt +
+			"Level  Blind  Ante  Minutes\n" +
+			"1      100    0     3\n" +
+			"2      break        5\n" +
+			"3      200    25    3\n" +
+			poker.CommandPrompt + "structure: unknown blind structure marathon\n" +
+			poker.CommandPrompt + "\n" + poker.GoodbyeMsg
+		if got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("start plays a game and then carries on taking commands", func(t *testing.T) {
+		game := &GameSpy{HandWinner: "Cleo"}
+
+		got := runCommands(poker.GetInMemoryStore(), game, "start", "2", "Chris, Cleo", "", "", "help")
+
+		assertGameStartedWith(t, game, 2)
+		assertFinishCalledWith(t, game, "Cleo")
+		if !strings.HasSuffix(got, "leave\n"+poker.CommandPrompt+"\n"+poker.GoodbyeMsg) {
+			t.Errorf("expected help after the game, got %q", got)
+		}
+	})
+
+	t.Run("it reports unknown commands and ignores blank lines", func(t *testing.T) {
+		got := runCommands(poker.GetInMemoryStore(), &GameSpy{}, "", "deal")
+
+		want := poker.CommandPrompt + poker.CommandPrompt + "unknown command \"deal\", type help to see what you can do\n" +
+			poker.CommandPrompt + "\n" + poker.GoodbyeMsg
+		if got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+}
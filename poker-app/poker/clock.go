@@ -0,0 +1,163 @@
+package poker
+
+import (
+	"sync"
+	"time"
+)
+
+/**
+Clock is where the blind alerts and the game get the time from. RealClock is the wall clock,
+a VirtualClock only moves when it is told to, so a whole tournament can be played through in
+the blink of an eye, and a scaled clock runs the wall clock faster for demos.
+*/
+type Clock interface {
+	Now() time.Time
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is the part of *time.Timer the alerts need, a timer that was stopped can be reset to go off again.
+type Timer interface {
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+type RealClock struct{}
+
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+func (RealClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+/**
+NewScaledClock returns a clock that runs speed times faster than the wall clock,
+with a speed of 60 a five minute blind level lasts five seconds.
+*/
+func NewScaledClock(speed float64) Clock {
+	return &scaledClock{speed: speed, start: time.Now()}
+}
+
+type scaledClock struct {
+	speed float64
+	start time.Time
+}
+
+func (c *scaledClock) Now() time.Time {
+	return c.start.Add(time.Duration(float64(time.Since(c.start)) * c.speed))
+}
+
+func (c *scaledClock) AfterFunc(d time.Duration, f func()) Timer {
+	return &scaledTimer{time.AfterFunc(c.scale(d), f), c}
+}
+
+func (c *scaledClock) scale(d time.Duration) time.Duration {
+	return time.Duration(float64(d) / c.speed)
+}
+
+type scaledTimer struct {
+	*time.Timer
+	clock *scaledClock
+}
+
+func (t *scaledTimer) Reset(d time.Duration) bool {
+	return t.Timer.Reset(t.clock.scale(d))
+}
+
+/**
+VirtualClock stands still until Advance is called, the timers that fall due while it is advanced
+go off one after the other in the order they are due, on the goroutine calling Advance.
+A timer for no time at all still waits for the next Advance, Advance(0) sets it off.
+*/
+type VirtualClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*virtualTimer
+}
+
+func NewVirtualClock(start time.Time) *VirtualClock {
+	return &VirtualClock{now: start}
+}
+
+func (c *VirtualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *VirtualClock) AfterFunc(d time.Duration, f func()) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &virtualTimer{clock: c, f: f}
+	c.schedule(t, d)
+	return t
+}
+
+// Advance moves the clock on by d, setting off every timer due by then.
+func (c *VirtualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	until := c.now.Add(d)
+	c.mu.Unlock()
+
+	for {
+		c.mu.Lock()
+		next := c.next(until)
+		if next == nil {
+			c.now = until
+			c.mu.Unlock()
+			return
+		}
+		c.now = next.at
+		c.remove(next)
+		c.mu.Unlock()
+
+		next.f()
+	}
+}
+
+// schedule, next and remove must be called holding the lock.
+func (c *VirtualClock) schedule(t *virtualTimer, d time.Duration) {
+	t.at = c.now.Add(d)
+	c.timers = append(c.timers, t)
+}
+
+func (c *VirtualClock) next(until time.Time) *virtualTimer {
+	var next *virtualTimer
+	for _, t := range c.timers {
+		if !t.at.After(until) && (next == nil || t.at.Before(next.at)) {
+			next = t
+		}
+	}
+	return next
+}
+
+func (c *VirtualClock) remove(t *virtualTimer) bool {
+	for i, scheduled := range c.timers {
+		if scheduled == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+type virtualTimer struct {
+	clock *VirtualClock
+	at    time.Time
+	f     func()
+}
+
+func (t *virtualTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.clock.remove(t)
+}
+
+func (t *virtualTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	active := t.clock.remove(t)
+	t.clock.schedule(t, d)
+	return active
+}
@@ -0,0 +1,97 @@
+package poker_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ydsxiong/go-playground/poker-app/poker"
+)
+
+func TestVirtualClock(t *testing.T) {
+	start := time.Date(2026, time.October, 1, 19, 0, 0, 0, time.UTC)
+
+	t.Run("timers go off in the order they are due as the clock is advanced", func(t *testing.T) {
+		clock := poker.NewVirtualClock(start)
+		var fired []string
+		clock.AfterFunc(10*time.Minute, func() { fired = append(fired, "second") })
+		clock.AfterFunc(5*time.Minute, func() { fired = append(fired, "first") })
+		stopped := clock.AfterFunc(7*time.Minute, func() { fired = append(fired, "stopped") })
+		stopped.Stop()
+
+		clock.Advance(time.Hour)
+
+		if strings.Join(fired, ",") != "first,second" {
+			t.Errorf("got timers %v", fired)
+		}
+		if !clock.Now().Equal(start.Add(time.Hour)) {
+			t.Errorf("got time %v, want %v", clock.Now(), start.Add(time.Hour))
+		}
+	})
+
+	t.Run("a timer set while the clock is advanced goes off if it falls due in time", func(t *testing.T) {
+		clock := poker.NewVirtualClock(start)
+		var at []time.Time
+		clock.AfterFunc(time.Minute, func() {
+			at = append(at, clock.Now())
+			clock.AfterFunc(time.Minute, func() { at = append(at, clock.Now()) })
+		})
+
+		clock.Advance(5 * time.Minute)
+
+		if len(at) != 2 || !at[1].Equal(start.Add(2*time.Minute)) {
+			t.Errorf("got timers going off at %v", at)
+		}
+	})
+
+	t.Run("a game plays through a three hour structure in no time", func(t *testing.T) {
+		clock := poker.NewVirtualClock(start)
+		out := &bytes.Buffer{}
+		game := poker.NewGame(poker.GetInMemoryStore(), poker.ClockAlerter(clock), poker.GetInMemoryGameStore(), dummyStructures, clock)
+
+		began := time.Now()
+		game.Start(8, "", poker.BuyIn{}, out)
+		clock.Advance(3 * time.Hour)
+
+		// the standard structure for 8 players is 11 levels of 13 minutes
+		if got := strings.Count(out.String(), "Blind is now"); got != 11 {
+			t.Errorf("got %d blind alerts, want 11: %q", got, out.String())
+		}
+		if !strings.HasSuffix(out.String(), "Blind is now 8000\n") {
+			t.Errorf("expected the last level to be reached, got %q", out.String())
+		}
+		if time.Since(began) > time.Second {
+			t.Errorf("simulating the structure took %v", time.Since(began))
+		}
+	})
+
+	t.Run("pausing the game holds the blinds on the virtual clock", func(t *testing.T) {
+		clock := poker.NewVirtualClock(start)
+		out := &bytes.Buffer{}
+		game := poker.NewGame(poker.GetInMemoryStore(), poker.ClockAlerter(clock), poker.GetInMemoryGameStore(), dummyStructures, clock)
+
+		alerts, _ := game.Start(5, "", poker.BuyIn{}, out)
+		clock.Advance(0)
+		alerts.Pause()
+		clock.Advance(time.Hour)
+		alerts.Resume()
+		clock.Advance(10 * time.Minute)
+
+		if out.String() != "Blind is now 100\nBlind is now 200\n" {
+			t.Errorf("got %q", out.String())
+		}
+	})
+}
+
+func TestScaledClock(t *testing.T) {
+	clock := poker.NewScaledClock(60 * 60)
+	fired := make(chan struct{})
+	clock.AfterFunc(5*time.Minute, func() { close(fired) })
+
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Error("expected five minutes to go by in well under a second at 3600 times the speed")
+	}
+}
@@ -2,33 +2,253 @@ package poker
 
 import (
 	"io"
+	"log"
+	"math/rand"
+	"strings"
+	"sync"
 	"time"
 )
 
+// Commands players can type instead of an action, abort is parsed as an action as it ends the hand.
+const (
+	PauseCommand  = "pause"
+	ResumeCommand = "resume"
+	AbortCommand  = "abort"
+)
+
+const GamePausedMsg = "Game paused, type resume to carry on"
+const GameResumedMsg = "Game resumed"
+const GameAbortedMsg = "Game aborted"
+
 type Game interface {
-	Start(numberOfPlayers int, to io.Writer)
-	Finish(winner string)
+	Start(numberOfPlayers int, structure string, buyIn BuyIn, to io.Writer) (AlertHandle, error)
+	PlayHand(players []string, stacks map[string]int, actor Actor, to io.Writer) (HandResult, error)
+	Finish(players []string, result HandResult) []Placing
+	FinishUnranked(result HandResult)
+	Abort()
 }
 
 type pokerGame struct {
-	store PlayerStore
-	alert BlindAlerter
+	store      PlayerStore
+	alert      BlindAlerter
+	games      GameStore
+	structures *BlindStructures
+	clock      Clock
+
+	// the tables of a tournament deal their hands from the same game at the same time
+	rndLock sync.Mutex
+	rnd     *rand.Rand
+
+	gameID    int
+	startedAt time.Time
+	structure BlindStructure
+	buyIn     BuyIn
+	alerts    *gameAlerts
 }
 
-func NewGame(store PlayerStore, alert BlindAlerter) *pokerGame {
-	return &pokerGame{store, alert}
+/**
+NewGame plays games whose blinds go up by the given clock, it has to be the clock
+the alerter schedules its alerts on for the blinds in play to match the alerts.
+*/
+func NewGame(store PlayerStore, alert BlindAlerter, games GameStore, structures *BlindStructures, clock Clock) *pokerGame {
+	return &pokerGame{
+		store:      store,
+		alert:      alert,
+		games:      games,
+		structures: structures,
+		clock:      clock,
+		rnd:        rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
 }
 
-func (g *pokerGame) Start(numberofplayers int, to io.Writer) {
-	blindIncrement := time.Duration(5+numberofplayers) * time.Second
-	blinds := []int{100, 200, 300, 400, 500, 600, 800, 1000, 2000, 4000, 8000}
+/**
+Start schedules an alert for every level of the named blind structure, or the standard one if no name is given.
+The handle it returns pauses, resumes or cancels all of them, pausing also stops the blinds from going up.
+Every player pays the buy-in, which is paid out to the places in its payout table once the game finishes.
+*/
+func (g *pokerGame) Start(numberofplayers int, structure string, buyIn BuyIn, to io.Writer) (AlertHandle, error) {
+	g.gameID = 0
+	blinds, err := g.structures.Get(structure, numberofplayers)
+	if err != nil {
+		return nil, err
+	}
+	if err := buyIn.Validate(numberofplayers); err != nil {
+		return nil, err
+	}
+	g.structure = blinds
+	g.buyIn = buyIn
+	g.startedAt = g.clock.Now()
+
+	id, err := g.games.StartGame(g.startedAt, numberofplayers)
+	if err != nil {
+		log.Printf("problem recording the start of a game, %v\n", err)
+	}
+	g.gameID = id
+
+	g.alerts = &gameAlerts{clock: g.clock}
 	blindTime := 0 * time.Second
-	for _, blind := range blinds {
-		g.alert.ScheduleAlertAt(blindTime, blind, to)
-		blindTime = blindTime + blindIncrement
+	for _, level := range g.structure.Levels {
+		g.alerts.handles = append(g.alerts.handles, g.alert.ScheduleAlertAt(blindTime, level, to))
+		blindTime = blindTime + level.Duration()
+	}
+	return g.alerts, nil
+}
+
+/**
+PlayHand deals a hand from a freshly shuffled deck at the blind level the game has reached,
+lets the actor drive the betting and leaves it to the evaluator to decide who won.
+Nobody can bet more than their stack, unless the stacks are nil and every bet is covered.
+*/
+func (g *pokerGame) PlayHand(players []string, stacks map[string]int, actor Actor, to io.Writer) (HandResult, error) {
+	blind := g.currentBlind()
+	g.rndLock.Lock()
+	deck := NewShuffledDeck(g.rnd)
+	g.rndLock.Unlock()
+	hand, err := NewHand(players, stacks, deck, blind.Blind, blind.Ante)
+	if err != nil {
+		return HandResult{}, err
+	}
+	return hand.Play(actor, to)
+}
+
+func (g *pokerGame) currentBlind() BlindLevel {
+	if len(g.structure.Levels) == 0 {
+		return BlindLevel{Blind: standardBlinds[0]}
+	}
+	return g.structure.BlindAt(g.structure.LevelAt(g.alerts.elapsed(g.startedAt)))
+}
+
+// blindLevel counts the blind levels reached so far, the first level being level 1.
+func (g *pokerGame) blindLevel() int {
+	if len(g.structure.Levels) == 0 {
+		return 0
+	}
+	return g.structure.LevelAt(g.alerts.elapsed(g.startedAt)) + 1
+}
+
+/**
+Finish records the result for everybody who took part, not just the winners,
+so that the losers' ratings go down as well. A split pot has more than one winner.
+It returns where everybody placed, for a game with a buy-in the prizes are
+added to the players' bankrolls along with the result.
+*/
+func (g *pokerGame) Finish(players []string, result HandResult) []Placing {
+	placings := g.buyIn.Placings(players, result)
+	if g.buyIn.Amount > 0 {
+		g.store.RecordPlacings(placings)
+	} else {
+		g.store.RecordGame(players, result.Winners)
+	}
+	g.FinishUnranked(result)
+	return placings
+}
+
+/**
+FinishUnranked finishes the history record of a game whose result does not count in the league,
+such as one played by bots, with who won it and the blind level it got to.
+*/
+func (g *pokerGame) FinishUnranked(result HandResult) {
+	err := g.games.FinishGame(g.gameID, g.clock.Now(), g.blindLevel(), strings.Join(result.Winners, ", "))
+	if err != nil {
+		log.Printf("problem recording the end of game %d, %v\n", g.gameID, err)
+	}
+	g.gameID = 0
+}
+
+/**
+Abort closes the history record of a game that ended without being played to the end,
+nobody wins it and the league is left alone. A game that never started has no record to close.
+*/
+func (g *pokerGame) Abort() {
+	if g.gameID == 0 {
+		return
 	}
+	if err := g.games.AbortGame(g.gameID, g.clock.Now(), g.blindLevel()); err != nil {
+		log.Printf("problem recording game %d as aborted, %v\n", g.gameID, err)
+	}
+	g.gameID = 0
+}
+
+// gameAlerts controls all the blind alerts of a game together and keeps track of how long it has been paused for.
+type gameAlerts struct {
+	mu        sync.Mutex
+	clock     Clock
+	handles   []AlertHandle
+	pausedAt  time.Time
+	pausedFor time.Duration
+	cancelled bool
 }
 
-func (g *pokerGame) Finish(winner string) {
-	g.store.RecordWin(winner)
+func (ga *gameAlerts) Pause() {
+	ga.mu.Lock()
+	defer ga.mu.Unlock()
+	if ga.cancelled || !ga.pausedAt.IsZero() {
+		return
+	}
+	ga.pausedAt = ga.clock.Now()
+	for _, h := range ga.handles {
+		h.Pause()
+	}
+}
+
+func (ga *gameAlerts) Resume() {
+	ga.mu.Lock()
+	defer ga.mu.Unlock()
+	if ga.cancelled || ga.pausedAt.IsZero() {
+		return
+	}
+	ga.pausedFor += ga.clock.Now().Sub(ga.pausedAt)
+	ga.pausedAt = time.Time{}
+	for _, h := range ga.handles {
+		h.Resume()
+	}
+}
+
+func (ga *gameAlerts) Cancel() {
+	ga.mu.Lock()
+	defer ga.mu.Unlock()
+	ga.cancelled = true
+	for _, h := range ga.handles {
+		h.Cancel()
+	}
+}
+
+// elapsed is how long the game has been played for since it started, not counting pauses.
+func (ga *gameAlerts) elapsed(startedAt time.Time) time.Duration {
+	ga.mu.Lock()
+	defer ga.mu.Unlock()
+	now := ga.clock.Now()
+	if !ga.pausedAt.IsZero() {
+		now = ga.pausedAt
+	}
+	return now.Sub(startedAt) - ga.pausedFor
+}
+
+/**
+gameControl handles the pause and resume commands players type instead of an action.
+While the game is paused nothing but resume or abort is accepted.
+*/
+type gameControl struct {
+	alerts AlertHandle
+	paused bool
+}
+
+// command returns what to tell the players when the input was taken as a command rather than an action.
+func (gc *gameControl) command(input string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case PauseCommand:
+		gc.alerts.Pause()
+		gc.paused = true
+		return GamePausedMsg, true
+	case ResumeCommand:
+		gc.alerts.Resume()
+		gc.paused = false
+		return GameResumedMsg, true
+	case AbortCommand:
+		return "", false
+	}
+	if gc.paused {
+		return GamePausedMsg, true
+	}
+	return "", false
 }
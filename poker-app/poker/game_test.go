@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -17,15 +18,50 @@ type scheduleAlert struct {
 
 type alerts []scheduleAlert
 
+// SpyBlindAlerter and its handles are locked, the alerts of a game played over a websocket being scheduled and cancelled from the server's goroutines.
 type SpyBlindAlerter struct {
-	alert alerts
+	lock    sync.Mutex
+	alert   alerts
+	handles []*SpyAlertHandle
 }
 
-func (ba *SpyBlindAlerter) ScheduleAlertAt(duration time.Duration, amount int, to io.Writer) {
-	ba.alert = append(ba.alert, scheduleAlert{duration, amount})
+func (ba *SpyBlindAlerter) ScheduleAlertAt(duration time.Duration, level poker.BlindLevel, to io.Writer) poker.AlertHandle {
+	ba.lock.Lock()
+	defer ba.lock.Unlock()
+	ba.alert = append(ba.alert, scheduleAlert{duration, level.Blind})
+	handle := &SpyAlertHandle{}
+	ba.handles = append(ba.handles, handle)
+	return handle
+}
+
+// firstCancelled is whether the first alert scheduled has been cancelled.
+func (ba *SpyBlindAlerter) firstCancelled() bool {
+	ba.lock.Lock()
+	defer ba.lock.Unlock()
+	return len(ba.handles) > 0 && ba.handles[0].Cancelled() == 1
+}
+
+type SpyAlertHandle struct {
+	lock      sync.Mutex
+	paused    int
+	resumed   int
+	cancelled int
+}
+
+func (h *SpyAlertHandle) Pause()  { h.lock.Lock(); h.paused++; h.lock.Unlock() }
+func (h *SpyAlertHandle) Resume() { h.lock.Lock(); h.resumed++; h.lock.Unlock() }
+func (h *SpyAlertHandle) Cancel() { h.lock.Lock(); h.cancelled++; h.lock.Unlock() }
+
+func (h *SpyAlertHandle) Cancelled() int {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.cancelled
 }
 
 var dummySpyAlerter = &SpyBlindAlerter{}
+var dummyGameStore = poker.GetInMemoryGameStore()
+var dummyStructures, _ = poker.NewBlindStructures()
+var dummyPlayerStore = poker.GetInMemoryStore()
 
 func TestGame(t *testing.T) {
 
@@ -35,15 +71,16 @@ func TestGame(t *testing.T) {
 	}
 	defer clean()
 
-	playerStore, e := poker.NewFileSystemPlayerStore(db)
+	playerStore, e := poker.NewFileSystemPlayerStore(db, poker.RealClock{})
 	if e != nil {
 		t.Errorf("problem initialising player db file, %v", e)
 	}
 
 	for _, name := range []string{"Chris", "pepper"} {
 		t.Run("test recording player win", func(t *testing.T) {
-			in := strings.NewReader("1\n" + name + " wins\n")
-			cli := poker.NewPokerCLI(in, dummyStdOut, poker.NewGame(playerStore, dummySpyAlerter))
+			// heads up the small blind acts first, so folding it hands the pot to the other player
+			in := strings.NewReader("2\nloser, " + name + "\n\n\nfold\n")
+			cli := poker.NewPokerCLI(in, dummyStdOut, poker.NewGame(playerStore, dummySpyAlerter, dummyGameStore, dummyStructures, poker.RealClock{}), dummyGameStore, dummyPlayerStore, dummyStructures)
 			cli.PlayPoker()
 
 			assertPlayerWin(t, playerStore, name)
@@ -51,10 +88,10 @@ func TestGame(t *testing.T) {
 	}
 
 	t.Run("it schedules printing of blind values", func(t *testing.T) {
-		in := strings.NewReader("5\n" + "Chris wins\n")
+		in := strings.NewReader("5\n" + "Chris, Cleo, Pepper, Ruth, Floyd\n")
 		blindAlerter := &SpyBlindAlerter{}
 
-		cli := poker.NewPokerCLI(in, dummyStdOut, poker.NewGame(playerStore, blindAlerter))
+		cli := poker.NewPokerCLI(in, dummyStdOut, poker.NewGame(playerStore, blindAlerter, dummyGameStore, dummyStructures, poker.RealClock{}), dummyGameStore, dummyPlayerStore, dummyStructures)
 		cli.PlayPoker()
 
 		cases := []struct {
@@ -89,6 +126,23 @@ func TestGame(t *testing.T) {
 	})
 }
 
+func TestGameHistory(t *testing.T) {
+	games := poker.GetInMemoryGameStore()
+	game := poker.NewGame(poker.GetInMemoryStore(), dummySpyAlerter, games, dummyStructures, poker.RealClock{})
+
+	in := strings.NewReader("2\nChris, Cleo\n\n\nfold\n")
+	poker.NewPokerCLI(in, dummyStdOut, game, games, dummyPlayerStore, dummyStructures).PlayPoker()
+
+	history, _ := games.GetGames()
+	if len(history) != 1 {
+		t.Fatalf("expected one game in the history, got %d", len(history))
+	}
+	got := history[0]
+	if got.Players != 2 || got.Winner != "Cleo" || got.FinishedAt == nil || got.BlindLevels != 1 {
+		t.Errorf("expected a finished 2 player game won by Cleo at the first blind level, got %+v", got)
+	}
+}
+
 func assertPlayerWin(t *testing.T, store poker.PlayerStore, name string) {
 	t.Helper()
 
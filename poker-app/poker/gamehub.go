@@ -0,0 +1,207 @@
+package poker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+)
+
+// spectatorBuffer is how many events a spectator can fall behind by before it is taken for dead and dropped.
+const spectatorBuffer = 16
+
+// The events spectators are sent, the state event is what a spectator gets first when it connects.
+const (
+	GameStartedEvent = "start"
+	BlindEvent       = "blind"
+	WinnerEvent      = "winner"
+	GameAbortedEvent = "aborted"
+	GameStateEvent   = "state"
+)
+
+/**
+GameEvent is one message in a game's event stream. A state event carries everything
+a spectator who has just connected needs to catch up: who is playing, with which
+structure, the current blind and, once the game is over, who won it.
+*/
+type GameEvent struct {
+	Type      string
+	Players   []string `json:",omitempty"`
+	Structure string   `json:",omitempty"`
+	Blind     string   `json:",omitempty"`
+	Winners   []string `json:",omitempty"`
+	Playing   bool     `json:",omitempty"`
+}
+
+/**
+GameHub broadcasts the events of a game to any number of spectators. Only one connection
+can host the game at a time, spectators only ever receive, they have no say in the game.
+
+The hub is an io.Writer so the blind alerts of the game can be written to it alongside the host,
+spectators are never sent what is written to the host as that includes the players' hole cards.
+
+Every spectator is written to from a goroutine of its own, so a slow one never holds up the game,
+its blind alerts or the other spectators. One that falls spectatorBuffer events behind is dropped,
+and closed if it is an io.Closer so whoever is serving it knows it is over.
+*/
+type GameHub struct {
+	mu         sync.Mutex
+	hosted     bool
+	state      GameEvent
+	spectators map[io.Writer]*spectatorQueue
+
+	// pending is a blind the hosted game alerted before it was announced with Start
+	pending string
+}
+
+// spectatorQueue holds the events waiting to be written to a spectator, done is closed once it is dropped or unsubscribed.
+type spectatorQueue struct {
+	events chan []byte
+	done   chan struct{}
+}
+
+func NewGameHub() *GameHub {
+	return &GameHub{spectators: map[io.Writer]*spectatorQueue{}}
+}
+
+// Host makes the caller the only connection in control of the game until it calls Release.
+func (h *GameHub) Host() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.hosted {
+		return fmt.Errorf("a game is already being played, watch it instead")
+	}
+	h.hosted = true
+	return nil
+}
+
+// Release ends hosting, a game that is still playing at that point was abandoned.
+func (h *GameHub) Release() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.state.Playing {
+		h.state.Playing = false
+		h.broadcast(GameEvent{Type: GameAbortedEvent})
+	}
+	h.hosted = false
+	h.pending = ""
+}
+
+/**
+Start announces the game to the spectators. A host can start its game before announcing it, so that
+spectators never hear of a game that failed to start, the blind that game alerted in the meantime
+is held back until here and follows the start.
+*/
+func (h *GameHub) Start(players []string, structure string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if structure == "" {
+		structure = StandardBlindStructure
+	}
+	h.state = GameEvent{Type: GameStateEvent, Players: players, Structure: structure, Blind: h.pending, Playing: true}
+	h.broadcast(GameEvent{Type: GameStartedEvent, Players: players, Structure: structure})
+	if h.pending != "" {
+		h.broadcast(GameEvent{Type: BlindEvent, Blind: h.pending})
+		h.pending = ""
+	}
+}
+
+func (h *GameHub) Write(p []byte) (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	blind := strings.TrimSpace(string(p))
+	if h.hosted && !h.state.Playing {
+		h.pending = blind
+		return len(p), nil
+	}
+	h.state.Blind = blind
+	h.broadcast(GameEvent{Type: BlindEvent, Blind: blind})
+	return len(p), nil
+}
+
+func (h *GameHub) Finish(winners []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.state.Playing = false
+	h.state.Winners = winners
+	h.broadcast(GameEvent{Type: WinnerEvent, Winners: winners})
+}
+
+/**
+Subscribe sends the spectator the current state of the game and then every event from here on,
+until the returned func is called.
+*/
+func (h *GameHub) Subscribe(spectator io.Writer) func() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if queue, ok := h.spectators[spectator]; ok {
+		h.unsubscribe(spectator, queue)
+	}
+	queue := &spectatorQueue{events: make(chan []byte, spectatorBuffer), done: make(chan struct{})}
+	h.spectators[spectator] = queue
+	go h.write(spectator, queue)
+
+	state := h.state
+	state.Type = GameStateEvent
+	h.send(spectator, queue, state)
+	return func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.unsubscribe(spectator, queue)
+	}
+}
+
+// write sends the spectator its events one at a time until it is dropped, dropping it when it cannot be written to.
+func (h *GameHub) write(spectator io.Writer, queue *spectatorQueue) {
+	for {
+		select {
+		case <-queue.done:
+			return
+		case msg := <-queue.events:
+			if _, err := spectator.Write(msg); err != nil {
+				log.Printf("dropping spectator, %v\n", err)
+				h.mu.Lock()
+				h.unsubscribe(spectator, queue)
+				h.mu.Unlock()
+				return
+			}
+		}
+	}
+}
+
+// unsubscribe must be called holding the lock, it stops writing to the spectator if it was still subscribed.
+func (h *GameHub) unsubscribe(spectator io.Writer, queue *spectatorQueue) {
+	if h.spectators[spectator] != queue {
+		return
+	}
+	delete(h.spectators, spectator)
+	close(queue.done)
+}
+
+// broadcast must be called holding the lock, it never waits on a spectator.
+func (h *GameHub) broadcast(event GameEvent) {
+	for spectator, queue := range h.spectators {
+		h.send(spectator, queue, event)
+	}
+}
+
+// send must be called holding the lock, a spectator whose queue is full has stopped keeping up and is dropped.
+func (h *GameHub) send(spectator io.Writer, queue *spectatorQueue, event GameEvent) {
+	msg, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("problem encoding the event %+v, %v\n", event, err)
+		return
+	}
+	select {
+	case queue.events <- msg:
+	default:
+		log.Printf("dropping spectator, it is %d events behind\n", spectatorBuffer)
+		h.unsubscribe(spectator, queue)
+		if closer, ok := spectator.(io.Closer); ok {
+			go closer.Close()
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package poker_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ydsxiong/go-playground/poker-app/poker"
+)
+
+// SpySpectator keeps every event it is sent, the hub writing to it from a goroutine of its own.
+type SpySpectator struct {
+	lock   sync.Mutex
+	events []poker.GameEvent
+	writes int
+	broken bool
+}
+
+func (s *SpySpectator) Write(p []byte) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.writes++
+	if s.broken {
+		return 0, errors.New("connection gone")
+	}
+	var event poker.GameEvent
+	if err := json.Unmarshal(p, &event); err != nil {
+		return 0, err
+	}
+	s.events = append(s.events, event)
+	return len(p), nil
+}
+
+func (s *SpySpectator) setBroken(broken bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.broken = broken
+}
+
+// received waits for the spectator to have been written to n times, and gives back the events it has got.
+func (s *SpySpectator) received(t *testing.T, n int) []poker.GameEvent {
+	t.Helper()
+	var events []poker.GameEvent
+	passed := retryUntil(500*time.Millisecond, func() bool {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		events = append([]poker.GameEvent(nil), s.events...)
+		return s.writes >= n
+	})
+	if !passed {
+		t.Fatalf("expected %d writes to the spectator, got events %+v", n, events)
+	}
+	return events
+}
+
+// StuckSpectator never gets past the first event it is sent, like a browser that has stopped reading.
+type StuckSpectator struct {
+	stuck  chan struct{}
+	closed chan struct{}
+}
+
+func (s *StuckSpectator) Write(p []byte) (int, error) {
+	<-s.stuck
+	return len(p), nil
+}
+
+func (s *StuckSpectator) Close() error {
+	close(s.closed)
+	return nil
+}
+
+func TestGameHub(t *testing.T) {
+
+	t.Run("spectators get the start, blinds and winner of the game", func(t *testing.T) {
+		hub := poker.NewGameHub()
+		spectator := &SpySpectator{}
+		hub.Subscribe(spectator)
+
+		hub.Start([]string{"Chris", "Cleo"}, "")
+		hub.Write([]byte("Blind is now 100\n"))
+		hub.Finish([]string{"Cleo"})
+
+		want := []poker.GameEvent{
+			{Type: poker.GameStateEvent},
+			{Type: poker.GameStartedEvent, Players: []string{"Chris", "Cleo"}, Structure: poker.StandardBlindStructure},
+			{Type: poker.BlindEvent, Blind: "Blind is now 100"},
+			{Type: poker.WinnerEvent, Winners: []string{"Cleo"}},
+		}
+		if got := spectator.received(t, len(want)); !reflect.DeepEqual(got, want) {
+			t.Errorf("got events %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("a late joiner is sent the current state first", func(t *testing.T) {
+		hub := poker.NewGameHub()
+		hub.Start([]string{"Chris", "Cleo"}, "turbo")
+		hub.Write([]byte("Blind is now 200, ante 25\n"))
+
+		spectator := &SpySpectator{}
+		hub.Subscribe(spectator)
+
+		want := poker.GameEvent{Type: poker.GameStateEvent, Players: []string{"Chris", "Cleo"}, Structure: "turbo", Blind: "Blind is now 200, ante 25", Playing: true}
+		if got := spectator.received(t, 1); len(got) != 1 || !reflect.DeepEqual(got[0], want) {
+			t.Errorf("got events %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("a blind the host alerted before starting the game follows the start", func(t *testing.T) {
+		hub := poker.NewGameHub()
+		spectator := &SpySpectator{}
+		hub.Subscribe(spectator)
+
+		assertNoError(t, hub.Host())
+		hub.Write([]byte("Blind is now 100\n"))
+		hub.Start([]string{"Chris", "Cleo"}, "")
+
+		want := []poker.GameEvent{
+			{Type: poker.GameStateEvent},
+			{Type: poker.GameStartedEvent, Players: []string{"Chris", "Cleo"}, Structure: poker.StandardBlindStructure},
+			{Type: poker.BlindEvent, Blind: "Blind is now 100"},
+		}
+		if got := spectator.received(t, len(want)); !reflect.DeepEqual(got, want) {
+			t.Errorf("got events %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("a game released before it finished was aborted", func(t *testing.T) {
+		hub := poker.NewGameHub()
+		spectator := &SpySpectator{}
+		hub.Subscribe(spectator)
+
+		assertNoError(t, hub.Host())
+		hub.Start([]string{"Chris", "Cleo"}, "")
+		hub.Release()
+
+		events := spectator.received(t, 3)
+		if last := events[len(events)-1]; last.Type != poker.GameAbortedEvent {
+			t.Errorf("expected the game to be aborted, got %+v", last)
+		}
+	})
+
+	t.Run("only one connection can host at a time", func(t *testing.T) {
+		hub := poker.NewGameHub()
+		assertNoError(t, hub.Host())
+		if hub.Host() == nil {
+			t.Error("expected a second host to be refused")
+		}
+		hub.Release()
+		assertNoError(t, hub.Host())
+	})
+
+	t.Run("spectators that went away are dropped", func(t *testing.T) {
+		hub := poker.NewGameHub()
+		gone, watching := &SpySpectator{}, &SpySpectator{}
+		hub.Subscribe(gone)
+		hub.Subscribe(watching)
+		gone.received(t, 1)
+		gone.setBroken(true)
+
+		hub.Start([]string{"Chris", "Cleo"}, "")
+		gone.received(t, 2)
+		gone.setBroken(false)
+		hub.Finish([]string{"Chris"})
+
+		watching.received(t, 3)
+		if got := gone.received(t, 2); len(got) != 1 {
+			t.Errorf("expected only the state before the spectator went away, got %+v", got)
+		}
+	})
+
+	t.Run("unsubscribed spectators get nothing more", func(t *testing.T) {
+		hub := poker.NewGameHub()
+		spectator, watching := &SpySpectator{}, &SpySpectator{}
+		unsubscribe := hub.Subscribe(spectator)
+		hub.Subscribe(watching)
+		spectator.received(t, 1)
+		unsubscribe()
+
+		hub.Start([]string{"Chris", "Cleo"}, "")
+		watching.received(t, 2)
+		if got := spectator.received(t, 1); len(got) != 1 {
+			t.Errorf("expected only the state, got %+v", got)
+		}
+	})
+
+	t.Run("a spectator that stops reading holds up neither the game nor anybody else", func(t *testing.T) {
+		hub := poker.NewGameHub()
+		stuck := &StuckSpectator{stuck: make(chan struct{}), closed: make(chan struct{})}
+		defer close(stuck.stuck)
+		watching := &SpySpectator{}
+		hub.Subscribe(stuck)
+		hub.Subscribe(watching)
+
+		for blinds := 1; blinds <= 40; blinds++ {
+			within(t, time.Second, func() { hub.Write([]byte("Blind is now 100\n")) })
+			watching.received(t, blinds+1)
+		}
+		select {
+		case <-stuck.closed:
+		case <-time.After(time.Second):
+			t.Error("expected the spectator that stopped reading to be dropped and closed")
+		}
+	})
+}
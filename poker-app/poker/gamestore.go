@@ -0,0 +1,35 @@
+package poker
+
+import (
+	"time"
+)
+
+/**
+GameRecord is the history of one game: when it started and finished, how many players took part,
+how far up the blind structure it got and who won. FinishedAt stays nil while the game is running,
+a game that was aborted is finished without a winner and marked Aborted.
+*/
+type GameRecord struct {
+	ID          int
+	StartedAt   time.Time
+	FinishedAt  *time.Time `json:",omitempty"`
+	Players     int
+	BlindLevels int
+	Winner      string
+	Aborted     bool `json:",omitempty"`
+}
+
+func (g GameRecord) Duration() time.Duration {
+	if g.FinishedAt == nil {
+		return 0
+	}
+	return g.FinishedAt.Sub(g.StartedAt)
+}
+
+type GameStore interface {
+	StartGame(startedAt time.Time, numberOfPlayers int) (int, error)
+	FinishGame(id int, finishedAt time.Time, blindLevels int, winner string) error
+	AbortGame(id int, abortedAt time.Time, blindLevels int) error
+	GetGame(id int) (GameRecord, error)
+	GetGames() ([]GameRecord, error)
+}
@@ -0,0 +1,71 @@
+package poker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+type databaseGameStore struct {
+	db *gorm.DB
+}
+
+/**
+NewDatabaseGameStore keeps the game history in a game_records table,
+which is created or migrated to the current GameRecord columns on the way in.
+*/
+func NewDatabaseGameStore(gormdb *gorm.DB) (GameStore, error) {
+	if err := gormdb.AutoMigrate(&GameRecord{}).Error; err != nil {
+		return nil, fmt.Errorf("problem migrating game history table, %v", err)
+	}
+	return &databaseGameStore{gormdb}, nil
+}
+
+/**
+id will have been generated by the database insert operation.
+*/
+func (ds *databaseGameStore) StartGame(startedAt time.Time, numberOfPlayers int) (int, error) {
+	game := GameRecord{StartedAt: startedAt, Players: numberOfPlayers}
+	if err := ds.db.Create(&game).Error; err != nil {
+		return 0, err
+	}
+	return game.ID, nil
+}
+
+func (ds *databaseGameStore) FinishGame(id int, finishedAt time.Time, blindLevels int, winner string) error {
+	game, err := ds.GetGame(id)
+	if err != nil {
+		return err
+	}
+	finishGame(&game, finishedAt, blindLevels, winner)
+	return ds.db.Save(&game).Error
+}
+
+func (ds *databaseGameStore) AbortGame(id int, abortedAt time.Time, blindLevels int) error {
+	game, err := ds.GetGame(id)
+	if err != nil {
+		return err
+	}
+	abortGame(&game, abortedAt, blindLevels)
+	return ds.db.Save(&game).Error
+}
+
+func (ds *databaseGameStore) GetGame(id int) (GameRecord, error) {
+	game := GameRecord{}
+	if err := ds.db.First(&game, id).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return game, fmt.Errorf("Unknown game %d", id)
+		}
+		return game, err
+	}
+	return game, nil
+}
+
+func (ds *databaseGameStore) GetGames() ([]GameRecord, error) {
+	var games []GameRecord
+	if err := ds.db.Order("id").Find(&games).Error; err != nil {
+		return nil, err
+	}
+	return games, nil
+}
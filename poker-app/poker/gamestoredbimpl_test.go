@@ -0,0 +1,89 @@
+package poker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/ydsxiong/go-playground/poker-app/poker"
+)
+
+func TestDatabaseGameStore(t *testing.T) {
+
+	database, err, clearDatabase := createTempFile("")
+	if err != nil {
+		t.Fatalf("could not create temp file %v", err)
+	}
+	defer clearDatabase()
+
+	gormdb, err := gorm.Open("sqlite3", database.Name())
+	if err != nil {
+		t.Fatalf("Problem with opening game history database, %v", err)
+	}
+	defer gormdb.Close()
+
+	store, err := poker.NewDatabaseGameStore(gormdb)
+	if err != nil {
+		t.Fatalf("Problem with setting up game history table, %v", err)
+	}
+
+	started := time.Date(2026, time.October, 1, 19, 0, 0, 0, time.UTC)
+	finished := started.Add(90 * time.Minute)
+
+	t.Run("it records a game from start to finish, under the id the insert generated", func(t *testing.T) {
+		id, err := store.StartGame(started, 5)
+		assertNoError(t, err)
+		if id != 1 {
+			t.Errorf("expected the first game to get id 1, got %d", id)
+		}
+
+		game, _ := store.GetGame(id)
+		if game.FinishedAt != nil {
+			t.Errorf("did not expect a running game to be finished, got %v", game.FinishedAt)
+		}
+
+		assertNoError(t, store.FinishGame(id, finished, 4, "Chris"))
+
+		game, _ = store.GetGame(id)
+		assertGameRecord(t, game, poker.GameRecord{ID: id, StartedAt: started, FinishedAt: &finished, Players: 5, BlindLevels: 4, Winner: "Chris"})
+	})
+
+	t.Run("it reloads the history from the database", func(t *testing.T) {
+		reloaded, err := poker.NewDatabaseGameStore(gormdb)
+		assertNoError(t, err)
+
+		games, _ := reloaded.GetGames()
+		if len(games) != 1 {
+			t.Fatalf("expected 1 game, got %d", len(games))
+		}
+		assertGameRecord(t, games[0], poker.GameRecord{ID: 1, StartedAt: started, FinishedAt: &finished, Players: 5, BlindLevels: 4, Winner: "Chris"})
+	})
+
+	t.Run("it records an aborted game without a winner", func(t *testing.T) {
+		id, err := store.StartGame(started, 3)
+		assertNoError(t, err)
+		if id != 2 {
+			t.Errorf("expected the second game to get id 2, got %d", id)
+		}
+		assertNoError(t, store.AbortGame(id, finished, 2))
+
+		game, _ := store.GetGame(id)
+		assertGameRecord(t, game, poker.GameRecord{ID: id, StartedAt: started, FinishedAt: &finished, Players: 3, BlindLevels: 2})
+		if !game.Aborted {
+			t.Errorf("expected the game to be aborted, got %+v", game)
+		}
+	})
+
+	t.Run("it refuses to finish an unknown game", func(t *testing.T) {
+		if err := store.FinishGame(42, finished, 1, "Chris"); err == nil || err.Error() != "Unknown game 42" {
+			t.Errorf("expected an unknown game error finishing game 42, got %v", err)
+		}
+		if err := store.AbortGame(42, finished, 1); err == nil || err.Error() != "Unknown game 42" {
+			t.Errorf("expected an unknown game error aborting game 42, got %v", err)
+		}
+		if _, err := store.GetGame(42); err == nil || err.Error() != "Unknown game 42" {
+			t.Errorf("expected an unknown game error getting game 42, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,127 @@
+package poker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+/**
+FileSystemGameStore keeps the game history as a JSON array in a file, the same way
+FileSystemPlayerStore keeps the league, rewriting the whole file through a Tape on every change.
+*/
+type FileSystemGameStore struct {
+	mu       sync.Mutex
+	games    []GameRecord
+	database *json.Encoder
+}
+
+func LoadUpGameFileStore(filestorepath string) (*FileSystemGameStore, func(), error) {
+	db, err := os.OpenFile(filestorepath, os.O_RDWR|os.O_CREATE, 0666)
+	if err != nil {
+		return nil, nil, fmt.Errorf("problem opening %s %v", filestorepath, err)
+	}
+	closeFunc := func() {
+		db.Close()
+	}
+
+	store, err := NewFileSystemGameStore(db)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Problem with opening game history file, %v", err)
+	}
+
+	return store, closeFunc, nil
+}
+
+func NewFileSystemGameStore(file *os.File) (*FileSystemGameStore, error) {
+
+	e := initialisePlayerDBFile(file)
+	if e != nil {
+		return nil, fmt.Errorf("problem initialising game history file, %v", e)
+	}
+
+	games, err := newGameHistory(file)
+	if err != nil {
+		return nil, fmt.Errorf("problem loading game history from file %s, %v", file.Name(), err)
+	}
+
+	return &FileSystemGameStore{
+		games:    games,
+		database: json.NewEncoder(&Tape{file})}, nil
+}
+
+func (fs *FileSystemGameStore) StartGame(startedAt time.Time, numberOfPlayers int) (int, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	id := 1
+	if len(fs.games) > 0 {
+		id = fs.games[len(fs.games)-1].ID + 1
+	}
+	fs.games = append(fs.games, GameRecord{ID: id, StartedAt: startedAt, Players: numberOfPlayers})
+	return id, fs.database.Encode(fs.games)
+}
+
+func (fs *FileSystemGameStore) FinishGame(id int, finishedAt time.Time, blindLevels int, winner string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	game := fs.find(id)
+	if game == nil {
+		return fmt.Errorf("Unknown game %d", id)
+	}
+	finishGame(game, finishedAt, blindLevels, winner)
+	return fs.database.Encode(fs.games)
+}
+
+func (fs *FileSystemGameStore) AbortGame(id int, abortedAt time.Time, blindLevels int) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	game := fs.find(id)
+	if game == nil {
+		return fmt.Errorf("Unknown game %d", id)
+	}
+	abortGame(game, abortedAt, blindLevels)
+	return fs.database.Encode(fs.games)
+}
+
+func (fs *FileSystemGameStore) GetGame(id int) (GameRecord, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	game := fs.find(id)
+	if game == nil {
+		return GameRecord{}, fmt.Errorf("Unknown game %d", id)
+	}
+	return *game, nil
+}
+
+func (fs *FileSystemGameStore) GetGames() ([]GameRecord, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	return append([]GameRecord{}, fs.games...), nil
+}
+
+func (fs *FileSystemGameStore) find(id int) *GameRecord {
+	for i, g := range fs.games {
+		if g.ID == id {
+			return &fs.games[i]
+		}
+	}
+	return nil
+}
+
+func newGameHistory(data io.ReadSeeker) ([]GameRecord, error) {
+	data.Seek(0, 0)
+	var games []GameRecord
+	err := json.NewDecoder(data).Decode(&games)
+	if err != nil {
+		return nil, fmt.Errorf("problem parsing game history, %v", err)
+	}
+	return games, nil
+}
@@ -0,0 +1,88 @@
+package poker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ydsxiong/go-playground/poker-app/poker"
+)
+
+func TestFileSystemGameStore(t *testing.T) {
+
+	database, err, clearDatabase := createTempFile("")
+	if err != nil {
+		t.Fatalf("could not create temp file %v", err)
+	}
+	defer clearDatabase()
+
+	store, err := poker.NewFileSystemGameStore(database)
+	if err != nil {
+		t.Fatalf("Problem with opening game history file, %v", err)
+	}
+
+	started := time.Date(2026, time.October, 1, 19, 0, 0, 0, time.UTC)
+	finished := started.Add(90 * time.Minute)
+
+	t.Run("it records a game from start to finish", func(t *testing.T) {
+		id, err := store.StartGame(started, 5)
+		assertNoError(t, err)
+
+		game, _ := store.GetGame(id)
+		if game.FinishedAt != nil {
+			t.Errorf("did not expect a running game to be finished, got %v", game.FinishedAt)
+		}
+
+		assertNoError(t, store.FinishGame(id, finished, 4, "Chris"))
+
+		game, _ = store.GetGame(id)
+		assertGameRecord(t, game, poker.GameRecord{ID: id, StartedAt: started, FinishedAt: &finished, Players: 5, BlindLevels: 4, Winner: "Chris"})
+		if game.Duration() != 90*time.Minute {
+			t.Errorf("got duration %v, want %v", game.Duration(), 90*time.Minute)
+		}
+	})
+
+	t.Run("it reloads the history from the file", func(t *testing.T) {
+		reloaded, err := poker.NewFileSystemGameStore(database)
+		assertNoError(t, err)
+
+		games, _ := reloaded.GetGames()
+		if len(games) != 1 {
+			t.Fatalf("expected 1 game, got %d", len(games))
+		}
+		assertGameRecord(t, games[0], poker.GameRecord{ID: 1, StartedAt: started, FinishedAt: &finished, Players: 5, BlindLevels: 4, Winner: "Chris"})
+	})
+
+	t.Run("it records an aborted game without a winner", func(t *testing.T) {
+		id, err := store.StartGame(started, 3)
+		assertNoError(t, err)
+		assertNoError(t, store.AbortGame(id, finished, 2))
+
+		game, _ := store.GetGame(id)
+		assertGameRecord(t, game, poker.GameRecord{ID: id, StartedAt: started, FinishedAt: &finished, Players: 3, BlindLevels: 2})
+		if !game.Aborted {
+			t.Errorf("expected the game to be aborted, got %+v", game)
+		}
+	})
+
+	t.Run("it refuses to finish an unknown game", func(t *testing.T) {
+		if err := store.FinishGame(42, finished, 1, "Chris"); err == nil {
+			t.Error("expected an error finishing an unknown game")
+		}
+		if err := store.AbortGame(42, finished, 1); err == nil {
+			t.Error("expected an error aborting an unknown game")
+		}
+		if _, err := store.GetGame(42); err == nil {
+			t.Error("expected an error getting an unknown game")
+		}
+	})
+}
+
+func assertGameRecord(t *testing.T, got, want poker.GameRecord) {
+	t.Helper()
+	sameFinish := (got.FinishedAt == nil && want.FinishedAt == nil) ||
+		(got.FinishedAt != nil && want.FinishedAt != nil && got.FinishedAt.Equal(*want.FinishedAt))
+	if got.ID != want.ID || !got.StartedAt.Equal(want.StartedAt) || !sameFinish ||
+		got.Players != want.Players || got.BlindLevels != want.BlindLevels || got.Winner != want.Winner {
+		t.Errorf("got game %+v, want %+v", got, want)
+	}
+}
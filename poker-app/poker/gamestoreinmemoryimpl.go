@@ -0,0 +1,76 @@
+package poker
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type defaultGameStore struct {
+	mu    sync.Mutex
+	games []GameRecord
+}
+
+func GetInMemoryGameStore() GameStore {
+	return &defaultGameStore{}
+}
+
+func (s *defaultGameStore) StartGame(startedAt time.Time, numberOfPlayers int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := len(s.games) + 1
+	s.games = append(s.games, GameRecord{ID: id, StartedAt: startedAt, Players: numberOfPlayers})
+	return id, nil
+}
+
+func (s *defaultGameStore) FinishGame(id int, finishedAt time.Time, blindLevels int, winner string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id < 1 || id > len(s.games) {
+		return fmt.Errorf("Unknown game found %d", id)
+	}
+	finishGame(&s.games[id-1], finishedAt, blindLevels, winner)
+	return nil
+}
+
+func (s *defaultGameStore) AbortGame(id int, abortedAt time.Time, blindLevels int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id < 1 || id > len(s.games) {
+		return fmt.Errorf("Unknown game found %d", id)
+	}
+	abortGame(&s.games[id-1], abortedAt, blindLevels)
+	return nil
+}
+
+func (s *defaultGameStore) GetGame(id int) (GameRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id < 1 || id > len(s.games) {
+		return GameRecord{}, fmt.Errorf("Unknown game found %d", id)
+	}
+	return s.games[id-1], nil
+}
+
+func (s *defaultGameStore) GetGames() ([]GameRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]GameRecord{}, s.games...), nil
+}
+
+func finishGame(g *GameRecord, finishedAt time.Time, blindLevels int, winner string) {
+	g.FinishedAt = &finishedAt
+	g.BlindLevels = blindLevels
+	g.Winner = winner
+}
+
+// abortGame finishes the game without a winner, nobody won a game that was never played to the end.
+func abortGame(g *GameRecord, abortedAt time.Time, blindLevels int) {
+	finishGame(g, abortedAt, blindLevels, "")
+	g.Aborted = true
+}
@@ -0,0 +1,216 @@
+package poker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ydsxiong/go-playground/poker-app/poker/pokerpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+/**
+NewGRPCServer serves the league and the game over gRPC, for the tools that would otherwise have to
+scrape the HTTP API. It works on the same store, game and hub as the PlayerServer it runs alongside,
+so a game started over gRPC can be watched on /spectate and the game hosted over the websocket
+can be watched over gRPC.
+*/
+func NewGRPCServer(store PlayerStore, game Game, hub *GameHub) *grpc.Server {
+	server := grpc.NewServer()
+	pokerpb.RegisterPokerServer(server, &pokerService{store: store, game: game, hub: hub})
+	return server
+}
+
+type pokerService struct {
+	pokerpb.UnimplementedPokerServer
+	store PlayerStore
+	game  Game
+	hub   *GameHub
+}
+
+func (s *pokerService) GetLeague(ctx context.Context, req *pokerpb.GetLeagueRequest) (*pokerpb.League, error) {
+	league, err := SeasonLeague(s.store, req.Season, time.Now())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	standings, err := league.Standings(req.Rank)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	reply := &pokerpb.League{}
+	for _, standing := range standings {
+		reply.Standings = append(reply.Standings, &pokerpb.Standing{
+			Name:          standing.Name,
+			DisplayName:   standing.DisplayName,
+			Wins:          int32(standing.Wins),
+			Played:        int32(standing.Played),
+			WinPercentage: standing.WinPercentage,
+			Rating:        standing.Rating,
+			Cashes:        int32(standing.Cashes),
+			Profit:        int32(standing.Profit),
+		})
+	}
+	return reply, nil
+}
+
+func (s *pokerService) GetScore(ctx context.Context, req *pokerpb.GetScoreRequest) (*pokerpb.Score, error) {
+	player := s.store.GetLeagueTable().Lookup(req.Name)
+	if player == nil {
+		return nil, status.Errorf(codes.NotFound, "unknown player %s", req.Name)
+	}
+	wins, err := s.store.GetScore(player.Name)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &pokerpb.Score{Name: player.Name, Wins: int32(wins)}, nil
+}
+
+func (s *pokerService) RecordWin(ctx context.Context, req *pokerpb.RecordWinRequest) (*pokerpb.Score, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, status.Error(codes.InvalidArgument, "a win needs the name of the player who won it")
+	}
+	s.store.RecordWin(req.Name)
+	return s.GetScore(ctx, &pokerpb.GetScoreRequest{Name: req.Name})
+}
+
+/**
+WatchGame streams the events of a game as its spectators get them, starting with the state of the game,
+until the game is won or aborted. Without players it watches the game being played, or the next one
+if there is none yet. With players it hosts a game of its own, bots of the strategy asked for playing
+everybody's hands. Nobody played that game but the bots, so its result is not recorded in the league,
+not even for the players the bots sat down as, it only goes into the game history.
+*/
+func (s *pokerService) WatchGame(req *pokerpb.WatchGameRequest, stream pokerpb.Poker_WatchGameServer) error {
+	events := newEventStream(stream)
+
+	if len(req.Players) == 0 {
+		unsubscribe := s.hub.Subscribe(events)
+		defer unsubscribe()
+		return events.wait(stream.Context())
+	}
+
+	players, bots, err := s.seat(req.Players, req.Strategy)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := s.hub.Host(); err != nil {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	defer s.hub.Release()
+
+	alerts, err := s.game.Start(len(players), req.Structure, BuyIn{}, s.hub)
+	if err != nil {
+		s.game.Abort()
+		return status.Errorf(codes.InvalidArgument, "problem starting the game, %v", err)
+	}
+	defer alerts.Cancel()
+
+	s.hub.Start(players, req.Structure)
+	unsubscribe := s.hub.Subscribe(events)
+	defer unsubscribe()
+
+	result, err := s.game.PlayHand(players, nil, bots, io.Discard)
+	if err != nil {
+		s.game.Abort()
+		return status.Errorf(codes.Internal, "problem playing the hand, %v", err)
+	}
+	s.game.FinishUnranked(result)
+	s.hub.Finish(result.Winners)
+	return events.wait(stream.Context())
+}
+
+// seat puts the players under the names the league knows them by and gives each of them a bot to play for them.
+func (s *pokerService) seat(names []string, strategy string) ([]string, Bots, error) {
+	if strategy == "" {
+		strategy = TightStrategy
+	}
+	league := s.store.GetLeagueTable()
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	players := make([]string, 0, len(names))
+	bots := Bots{}
+	for _, name := range names {
+		if player := league.Lookup(name); player != nil {
+			name = player.Name
+		}
+		bot, err := NewBot(strategy, rnd)
+		if err != nil {
+			return nil, nil, err
+		}
+		players = append(players, name)
+		bots[name] = bot
+	}
+	return players, bots, nil
+}
+
+/**
+eventStream is a spectator of a hub that sends the events on to a gRPC stream,
+it is over once the game it is watching is won or aborted, the stream cannot be sent to,
+or the hub drops it for falling behind.
+*/
+type eventStream struct {
+	stream pokerpb.Poker_WatchGameServer
+	over   chan struct{}
+	once   sync.Once
+	err    error
+}
+
+func newEventStream(stream pokerpb.Poker_WatchGameServer) *eventStream {
+	return &eventStream{stream: stream, over: make(chan struct{})}
+}
+
+/**
+Write is called from the hub's goroutine for this spectator, one event at a time, so the stream is
+never sent to by two events at once and a watcher held back by flow control only holds up itself.
+*/
+func (e *eventStream) Write(p []byte) (int, error) {
+	var event GameEvent
+	if err := json.Unmarshal(p, &event); err != nil {
+		return 0, err
+	}
+	err := e.stream.Send(&pokerpb.GameEvent{
+		Type:      event.Type,
+		Players:   event.Players,
+		Structure: event.Structure,
+		Blind:     event.Blind,
+		Winners:   event.Winners,
+		Playing:   event.Playing,
+	})
+	if err != nil {
+		e.end(err)
+		return 0, err
+	}
+	if event.Type == WinnerEvent || event.Type == GameAbortedEvent {
+		e.end(nil)
+	}
+	return len(p), nil
+}
+
+// Close is called by the hub when it drops the stream for falling too far behind the game.
+func (e *eventStream) Close() error {
+	e.end(status.Error(codes.ResourceExhausted, "fell too far behind the game and was dropped"))
+	return nil
+}
+
+func (e *eventStream) end(err error) {
+	e.once.Do(func() {
+		e.err = err
+		close(e.over)
+	})
+}
+
+func (e *eventStream) wait(ctx context.Context) error {
+	select {
+	case <-e.over:
+		return e.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
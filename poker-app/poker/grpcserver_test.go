@@ -0,0 +1,218 @@
+package poker_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ydsxiong/go-playground/poker-app/poker"
+	"github.com/ydsxiong/go-playground/poker-app/poker/pokerpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGRPCServer(t *testing.T) {
+	store := poker.GetInMemoryStore(map[string]int{"Chris": 3, "Cleo": 1})
+	assertNoError(t, store.RegisterPlayer("Chris", "Chris James", "CJ"))
+	game := &GameSpy{BlindAlert: []byte("Blind is now 100\n"), HandWinner: "Chris"}
+	hub := poker.NewGameHub()
+	client, stop := startGRPCServer(t, poker.NewGRPCServer(store, game, hub))
+	defer stop()
+	ctx := context.Background()
+
+	t.Run("it ranks the league", func(t *testing.T) {
+		league, err := client.GetLeague(ctx, &pokerpb.GetLeagueRequest{})
+		assertNoError(t, err)
+		if len(league.Standings) != 2 || league.Standings[0].Name != "Chris" || league.Standings[0].DisplayName != "Chris James" || league.Standings[0].Wins != 3 {
+			t.Errorf("unexpected standings %v", league.Standings)
+		}
+
+		_, err = client.GetLeague(ctx, &pokerpb.GetLeagueRequest{Season: "summer"})
+		assertStatusCode(t, err, codes.InvalidArgument)
+	})
+
+	t.Run("it gets and records scores", func(t *testing.T) {
+		score, err := client.RecordWin(ctx, &pokerpb.RecordWinRequest{Name: "cj"})
+		assertNoError(t, err)
+		if score.Name != "Chris" || score.Wins != 4 {
+			t.Errorf("got %v, want Chris on 4 wins", score)
+		}
+
+		score, err = client.GetScore(ctx, &pokerpb.GetScoreRequest{Name: "Cleo"})
+		assertNoError(t, err)
+		if score.Wins != 1 {
+			t.Errorf("got %d wins for Cleo, want 1", score.Wins)
+		}
+
+		_, err = client.GetScore(ctx, &pokerpb.GetScoreRequest{Name: "Floyd"})
+		assertStatusCode(t, err, codes.NotFound)
+		_, err = client.RecordWin(ctx, &pokerpb.RecordWinRequest{Name: " "})
+		assertStatusCode(t, err, codes.InvalidArgument)
+	})
+
+	t.Run("it starts a game and streams its blinds and winner", func(t *testing.T) {
+		stream, err := client.WatchGame(ctx, &pokerpb.WatchGameRequest{Players: []string{"cj", "Cleo"}, Structure: "turbo"})
+		assertNoError(t, err)
+
+		assertGameEvents(t, stream,
+			&pokerpb.GameEvent{Type: poker.GameStateEvent, Players: []string{"Chris", "Cleo"}, Structure: "turbo", Blind: "Blind is now 100", Playing: true},
+			&pokerpb.GameEvent{Type: poker.WinnerEvent, Winners: []string{"Chris"}},
+		)
+		assertPlayedWith(t, game, "Chris", "Cleo")
+		if game.FinishedWith() != "" {
+			t.Errorf("expected the game played by bots not to be recorded, got it finished with %q", game.FinishedWith())
+		}
+	})
+
+	t.Run("it streams the game being played by somebody else", func(t *testing.T) {
+		stream, err := client.WatchGame(ctx, &pokerpb.WatchGameRequest{})
+		assertNoError(t, err)
+		if _, err := stream.Recv(); err != nil {
+			t.Fatalf("expected the state of the game first, got %v", err)
+		}
+
+		assertNoError(t, hub.Host())
+		hub.Start([]string{"Cleo", "Pepper"}, "")
+		hub.Write([]byte("Blind is now 200\n"))
+		hub.Finish([]string{"Pepper"})
+		hub.Release()
+
+		assertGameEvents(t, stream,
+			&pokerpb.GameEvent{Type: poker.GameStartedEvent, Players: []string{"Cleo", "Pepper"}, Structure: poker.StandardBlindStructure},
+			&pokerpb.GameEvent{Type: poker.BlindEvent, Blind: "Blind is now 200"},
+			&pokerpb.GameEvent{Type: poker.WinnerEvent, Winners: []string{"Pepper"}},
+		)
+	})
+
+	t.Run("a watcher that stops reading is dropped rather than holding up the game", func(t *testing.T) {
+		stream, err := client.WatchGame(ctx, &pokerpb.WatchGameRequest{})
+		assertNoError(t, err)
+		if _, err := stream.Recv(); err != nil {
+			t.Fatalf("expected the state of the game first, got %v", err)
+		}
+
+		// far more blinds than fit in the watcher's queue and gRPC's flow control window together
+		within(t, 5*time.Second, func() {
+			for i := 0; i < 5000; i++ {
+				hub.Write([]byte("Blind is now 100\n"))
+			}
+		})
+
+		for {
+			if _, err = stream.Recv(); err != nil {
+				break
+			}
+		}
+		assertStatusCode(t, err, codes.ResourceExhausted)
+	})
+
+	t.Run("only one game is played at a time", func(t *testing.T) {
+		assertNoError(t, hub.Host())
+		defer hub.Release()
+
+		stream, err := client.WatchGame(ctx, &pokerpb.WatchGameRequest{Players: []string{"Chris", "Cleo"}})
+		assertNoError(t, err)
+		_, err = stream.Recv()
+		assertStatusCode(t, err, codes.FailedPrecondition)
+	})
+}
+
+func TestGRPCBotGames(t *testing.T) {
+	store := poker.GetInMemoryStore(map[string]int{"Chris": 3, "Cleo": 1})
+	assertNoError(t, store.RegisterPlayer("Chris", "Chris James", "CJ"))
+	games := poker.GetInMemoryGameStore()
+	game := poker.NewGame(store, dummySpyAlerter, games, dummyStructures, poker.RealClock{})
+	hub := poker.NewGameHub()
+	client, stop := startGRPCServer(t, poker.NewGRPCServer(store, game, hub))
+	defer stop()
+
+	t.Run("the game is recorded in the history but not in the league", func(t *testing.T) {
+		before := store.GetLeagueTable()
+
+		stream, err := client.WatchGame(context.Background(), &pokerpb.WatchGameRequest{Players: []string{"cj", "Cleo", "Pepper"}})
+		assertNoError(t, err)
+		for {
+			if _, err = stream.Recv(); err != nil {
+				break
+			}
+		}
+		if err != io.EOF {
+			t.Fatalf("expected the game to be played to the end, got %v", err)
+		}
+
+		if after := store.GetLeagueTable(); !reflect.DeepEqual(after, before) {
+			t.Errorf("expected a game played by bots to leave the league alone, got %v, was %v", after, before)
+		}
+		record, err := games.GetGame(1)
+		assertNoError(t, err)
+		if record.FinishedAt == nil || record.Aborted || record.Winner == "" || record.Players != 3 {
+			t.Errorf("expected the game to be finished with a winner, got %+v", record)
+		}
+	})
+
+	t.Run("a game that fails to start is never announced", func(t *testing.T) {
+		spectator := &SpySpectator{}
+		hub.Subscribe(spectator)
+		spectator.received(t, 1)
+
+		stream, err := client.WatchGame(context.Background(), &pokerpb.WatchGameRequest{Players: []string{"Chris", "Cleo"}, Structure: "glacial"})
+		assertNoError(t, err)
+		_, err = stream.Recv()
+		assertStatusCode(t, err, codes.InvalidArgument)
+
+		hub.Write([]byte("Blind is now 100\n"))
+		if got := spectator.received(t, 2); got[1].Type != poker.BlindEvent {
+			t.Errorf("expected spectators to hear nothing of the game, got %+v", got)
+		}
+	})
+}
+
+// startGRPCServer serves the server on an in-process listener and returns a client connected to it.
+func startGRPCServer(t *testing.T, server *grpc.Server) (pokerpb.PokerClient, func()) {
+	t.Helper()
+	listener := bufconn.Listen(1024 * 1024)
+	go server.Serve(listener)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("problem connecting to the gRPC server, %v", err)
+	}
+	return pokerpb.NewPokerClient(conn), func() {
+		conn.Close()
+		server.Stop()
+	}
+}
+
+func assertGameEvents(t *testing.T, stream pokerpb.Poker_WatchGameClient, want ...*pokerpb.GameEvent) {
+	t.Helper()
+	for _, w := range want {
+		got, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("expected %v, got %v", w, err)
+		}
+		if got.Type != w.Type || !reflect.DeepEqual(got.Players, w.Players) || got.Structure != w.Structure ||
+			got.Blind != w.Blind || !reflect.DeepEqual(got.Winners, w.Winners) || got.Playing != w.Playing {
+			t.Errorf("got event %v, want %v", got, w)
+		}
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("expected the stream to end once the game was over, got %v", err)
+	}
+}
+
+func assertStatusCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != want {
+		t.Errorf("got status %v (%v), want %v", got, err, want)
+	}
+}
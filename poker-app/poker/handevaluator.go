@@ -0,0 +1,162 @@
+package poker
+
+import (
+	"fmt"
+	"sort"
+)
+
+type HandCategory int
+
+const (
+	HighCard HandCategory = iota
+	OnePair
+	TwoPair
+	ThreeOfAKind
+	Straight
+	Flush
+	FullHouse
+	FourOfAKind
+	StraightFlush
+)
+
+var handCategoryNames = []string{
+	"High Card", "One Pair", "Two Pair", "Three of a Kind", "Straight",
+	"Flush", "Full House", "Four of a Kind", "Straight Flush",
+}
+
+func (c HandCategory) String() string {
+	return handCategoryNames[c]
+}
+
+/**
+HandValue is the strength of a five card hand: its category, and the ranks that break
+ties within that category in order of importance (e.g. for two pair: high pair, low pair, kicker).
+Two values of the same category are compared rank by rank.
+*/
+type HandValue struct {
+	Category HandCategory
+	Ranks    []Rank
+	Cards    []Card
+}
+
+func (h HandValue) String() string {
+	return fmt.Sprintf("%s (%s)", h.Category, formatCards(h.Cards))
+}
+
+// Compare returns 1 if h beats o, -1 if o beats h and 0 for a split.
+func (h HandValue) Compare(o HandValue) int {
+	if h.Category != o.Category {
+		if h.Category > o.Category {
+			return 1
+		}
+		return -1
+	}
+	for i := 0; i < len(h.Ranks) && i < len(o.Ranks); i++ {
+		if h.Ranks[i] != o.Ranks[i] {
+			if h.Ranks[i] > o.Ranks[i] {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
+
+/**
+EvaluateHand picks the best five card hand out of the given cards, which in Hold'em
+are the two hole cards plus the five community cards, so 21 combinations at most.
+*/
+func EvaluateHand(cards []Card) (HandValue, error) {
+	if len(cards) < 5 {
+		return HandValue{}, fmt.Errorf("need at least 5 cards to evaluate a hand, got %d", len(cards))
+	}
+
+	var best HandValue
+	found := false
+	combination := make([]Card, 5)
+	var choose func(start, picked int)
+	choose = func(start, picked int) {
+		if picked == 5 {
+			v := evaluateFive(combination)
+			if !found || v.Compare(best) > 0 {
+				best = v
+				found = true
+			}
+			return
+		}
+		for i := start; i <= len(cards)-(5-picked); i++ {
+			combination[picked] = cards[i]
+			choose(i+1, picked+1)
+		}
+	}
+	choose(0, 0)
+
+	return best, nil
+}
+
+func evaluateFive(hand []Card) HandValue {
+	cards := append([]Card(nil), hand...)
+	sort.Slice(cards, func(i, j int) bool {
+		return cards[i].Rank > cards[j].Rank
+	})
+
+	flush := true
+	for _, c := range cards[1:] {
+		if c.Suit != cards[0].Suit {
+			flush = false
+		}
+	}
+
+	straightHigh, straight := straightHighCard(cards)
+
+	// group ranks by how often they appear, biggest groups first, then highest rank first
+	counts := map[Rank]int{}
+	for _, c := range cards {
+		counts[c.Rank]++
+	}
+	var groups []Rank
+	for r := range counts {
+		groups = append(groups, r)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if counts[groups[i]] == counts[groups[j]] {
+			return groups[i] > groups[j]
+		}
+		return counts[groups[i]] > counts[groups[j]]
+	})
+
+	value := HandValue{Ranks: groups, Cards: cards}
+	switch {
+	case straight && flush:
+		value.Category, value.Ranks = StraightFlush, []Rank{straightHigh}
+	case counts[groups[0]] == 4:
+		value.Category = FourOfAKind
+	case counts[groups[0]] == 3 && counts[groups[1]] == 2:
+		value.Category = FullHouse
+	case flush:
+		value.Category = Flush
+	case straight:
+		value.Category, value.Ranks = Straight, []Rank{straightHigh}
+	case counts[groups[0]] == 3:
+		value.Category = ThreeOfAKind
+	case counts[groups[0]] == 2 && counts[groups[1]] == 2:
+		value.Category = TwoPair
+	case counts[groups[0]] == 2:
+		value.Category = OnePair
+	default:
+		value.Category = HighCard
+	}
+	return value
+}
+
+// straightHighCard expects cards sorted high to low, and treats A-2-3-4-5 as a five high straight.
+func straightHighCard(cards []Card) (Rank, bool) {
+	for i := 1; i < len(cards); i++ {
+		if cards[i-1].Rank-cards[i].Rank != 1 {
+			wheel := cards[0].Rank == Ace && cards[1].Rank == Five &&
+				cards[2].Rank == Four && cards[3].Rank == Three && cards[4].Rank == Two
+			return Five, wheel
+		}
+	}
+	return cards[0].Rank, true
+}
@@ -0,0 +1,75 @@
+package poker_test
+
+import (
+	"testing"
+
+	"github.com/ydsxiong/go-playground/poker-app/poker"
+)
+
+func TestEvaluateHand(t *testing.T) {
+
+	cases := []struct {
+		cards string
+		want  poker.HandCategory
+	}{
+		{"As Kd 9c 7h 4s 3d 2c", poker.HighCard},
+		{"As Ad 9c 7h 4s 3d 2c", poker.OnePair},
+		{"As Ad 9c 9h 4s 3d 2c", poker.TwoPair},
+		{"As Ad Ac 9h 4s 3d 2c", poker.ThreeOfAKind},
+		{"As 2d 3c 4h 5s Kd Qc", poker.Straight},
+		{"Ts Jd Qc Kh As 2d 2c", poker.Straight},
+		{"2s 7s 9s Js Ks Kd Qc", poker.Flush},
+		{"As Ad Ac 9h 9s 3d 2c", poker.FullHouse},
+		{"As Ad Ac Ah 9s 3d 2c", poker.FourOfAKind},
+		{"5h 6h 7h 8h 9h 9s 9c", poker.StraightFlush},
+	}
+
+	for _, c := range cases {
+		t.Run(c.cards, func(t *testing.T) {
+			got, err := poker.EvaluateHand(poker.MustParseCards(c.cards))
+			if err != nil {
+				t.Fatalf("did not expect an error, %v", err)
+			}
+			if got.Category != c.want {
+				t.Errorf("got %s, want %s", got.Category, c.want)
+			}
+		})
+	}
+
+	t.Run("it needs at least five cards", func(t *testing.T) {
+		_, err := poker.EvaluateHand(poker.MustParseCards("As Ad"))
+		if err == nil {
+			t.Error("expected an error for two cards")
+		}
+	})
+}
+
+func TestCompareHands(t *testing.T) {
+
+	cases := []struct {
+		name   string
+		a, b   string
+		wanted int
+	}{
+		{"higher category wins", "As Ad 9c 9h 4s", "Ks Qd Jc Th 9s", -1},
+		{"higher pair wins", "Ks Kd 9c 7h 4s", "Qs Qd Ac 7h 4s", 1},
+		{"kicker decides equal pairs", "Ks Kd Ac 7h 4s", "Kh Kc Qc 7d 4d", 1},
+		{"wheel is the lowest straight", "As 2d 3c 4h 5s", "2s 3d 4c 5h 6s", -1},
+		{"full house compares the trips first", "2s 2d 2c Ah As", "3s 3d 3c 4h 4s", -1},
+		{"same ranks split the pot", "As Kd 9c 7h 4s", "Ad Kc 9h 7s 4d", 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			a, _ := poker.EvaluateHand(poker.MustParseCards(c.a))
+			b, _ := poker.EvaluateHand(poker.MustParseCards(c.b))
+
+			if got := a.Compare(b); got != c.wanted {
+				t.Errorf("comparing %s with %s got %d, want %d", a, b, got, c.wanted)
+			}
+			if got := b.Compare(a); got != -c.wanted {
+				t.Errorf("comparing %s with %s got %d, want %d", b, a, got, -c.wanted)
+			}
+		})
+	}
+}
@@ -0,0 +1,559 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	MinPlayersPerHand = 2
+	MaxPlayersPerHand = 10
+
+	// how many times a player is asked again after an invalid action before they are folded
+	maxActionAttempts = 3
+)
+
+// ErrHandAborted is returned by Hand.Play when an actor aborts the hand, nobody wins anything.
+var ErrHandAborted = errors.New("the hand was aborted")
+
+type Street int
+
+const (
+	PreFlop Street = iota
+	Flop
+	Turn
+	River
+)
+
+var streetNames = []string{"Pre-flop", "Flop", "Turn", "River"}
+
+func (s Street) String() string {
+	return streetNames[s]
+}
+
+type ActionKind int
+
+const (
+	Fold ActionKind = iota
+	Check
+	Call
+	Raise
+	Abort
+	AllIn
+)
+
+/**
+Action is what a player does when it is their turn to act.
+For a Raise, Amount is how much is put on top of the current bet,
+so "raise 200" facing a bet of 100 makes the bet 300.
+AllIn bets every chip the player has left, which is a call if that is no more than the bet.
+*/
+type Action struct {
+	Kind   ActionKind
+	Amount int
+}
+
+func ParseAction(input string) (Action, error) {
+	fields := strings.Fields(strings.ToLower(input))
+	if len(fields) == 0 {
+		return Action{}, fmt.Errorf("no action given, expecting fold, check, call, bet <n>, raise <n> or all-in")
+	}
+	switch fields[0] {
+	case "fold":
+		return Action{Kind: Fold}, nil
+	case "check":
+		return Action{Kind: Check}, nil
+	case "call":
+		return Action{Kind: Call}, nil
+	case AbortCommand:
+		return Action{Kind: Abort}, nil
+	case "all-in", "allin":
+		return Action{Kind: AllIn}, nil
+	case "bet", "raise":
+		if len(fields) != 2 {
+			return Action{}, fmt.Errorf("%s needs an amount, e.g. %s 200", fields[0], fields[0])
+		}
+		amount, err := strconv.Atoi(fields[1])
+		if err != nil || amount <= 0 {
+			return Action{}, fmt.Errorf("invalid amount %q", fields[1])
+		}
+		return Action{Kind: Raise, Amount: amount}, nil
+	}
+	return Action{}, fmt.Errorf("unknown action %q, expecting fold, check, call, bet <n>, raise <n> or all-in", fields[0])
+}
+
+/**
+TableView is everything a player is allowed to see when asked to act. Stack is the chips
+the player has left to bet, it is 0 in a hand played without stacks where every bet is covered.
+*/
+type TableView struct {
+	Player    string
+	Street    Street
+	Hole      []Card
+	Community []Card
+	Pot       int
+	ToCall    int
+	MinRaise  int
+	Stack     int
+}
+
+func (v TableView) String() string {
+	s := fmt.Sprintf("%s, %s, hole cards %s", v.Player, v.Street, formatCards(v.Hole))
+	if len(v.Community) > 0 {
+		s += fmt.Sprintf(", board %s", formatCards(v.Community))
+	}
+	s += fmt.Sprintf(", pot %d, to call %d", v.Pot, v.ToCall)
+	if v.Stack > 0 {
+		s += fmt.Sprintf(", stack %d", v.Stack)
+	}
+	return s
+}
+
+// Actor decides the actions of the players at a table, whether they are typing into a CLI, a browser or are bots.
+type Actor interface {
+	Act(view TableView) Action
+}
+
+type ActorFunc func(view TableView) Action
+
+func (a ActorFunc) Act(view TableView) Action {
+	return a(view)
+}
+
+/**
+HandResult has the winners of the hand, and everybody else in the order they dropped out:
+first the players who folded, in the order they folded, then the losers at the showdown
+from the weakest hand up. Bets is what every player put into the pot, antes and blinds included,
+and Won what every player took out of it.
+
+The winners and their hand are those of the main pot. Once a player is all-in for less than the
+others have bet, what the others bet on top goes into a side pot that only they can win,
+Pots has the main pot first and then the side pots in the order they were made.
+*/
+type HandResult struct {
+	Winners    []string
+	Eliminated []string
+	Pot        int
+	Bets       map[string]int
+	Won        map[string]int
+	Pots       []SidePot
+	Showdown   bool
+	Value      HandValue
+}
+
+// Settle takes what every player bet out of their stack and gives them what they won from the pots.
+func (r HandResult) Settle(stacks map[string]int) {
+	for p, bet := range r.Bets {
+		stacks[p] -= bet
+	}
+	for p, won := range r.Won {
+		stacks[p] += won
+	}
+}
+
+// SidePot is one of the pots shared out at the showdown, along with who won it with which hand.
+type SidePot struct {
+	Amount  int
+	Winners []string
+	Value   HandValue
+}
+
+/**
+Hand runs a single hand of Texas Hold'em: the blinds, hole cards, the four betting rounds
+and the showdown. Burn cards are not dealt so that a stacked deck reads in dealing order:
+two hole cards per player in seat order, then flop, turn and river.
+
+A hand played with stacks never lets a player bet more chips than they have, a player who
+puts in their last chip is all-in and plays on for the pots they have a share in.
+Without stacks every bet is covered, as it is in a single game.
+*/
+type Hand struct {
+	players  []string
+	deck     *Deck
+	bigBlind int
+	ante     int
+	stacks   map[string]int
+	allIn    map[string]bool
+
+	hole      map[string][]Card
+	community []Card
+	folded    map[string]bool
+	foldOrder []string
+	pot       int
+	bets      map[string]int
+	aborted   bool
+
+	streetBets map[string]int
+	currentBet int
+	minRaise   int
+}
+
+// NewHand deals in the players with the stacks given, nil stacks for a hand where every bet is covered.
+func NewHand(players []string, stacks map[string]int, deck *Deck, bigBlind, ante int) (*Hand, error) {
+	if len(players) < MinPlayersPerHand || len(players) > MaxPlayersPerHand {
+		return nil, fmt.Errorf("a hand needs between %d and %d players, got %d", MinPlayersPerHand, MaxPlayersPerHand, len(players))
+	}
+	seen := map[string]bool{}
+	for _, p := range players {
+		if p == "" || seen[p] {
+			return nil, fmt.Errorf("player names must be unique and not empty, got %q", players)
+		}
+		seen[p] = true
+	}
+	if deck.Remaining() < 2*len(players)+5 {
+		return nil, fmt.Errorf("not enough cards in the deck for %d players", len(players))
+	}
+	var chips map[string]int
+	if stacks != nil {
+		chips = map[string]int{}
+		for _, p := range players {
+			if stacks[p] <= 0 {
+				return nil, fmt.Errorf("%s has no chips to play with", p)
+			}
+			chips[p] = stacks[p]
+		}
+	}
+	return &Hand{
+		players:  players,
+		deck:     deck,
+		bigBlind: bigBlind,
+		ante:     ante,
+		stacks:   chips,
+		allIn:    map[string]bool{},
+		hole:     map[string][]Card{},
+		folded:   map[string]bool{},
+		bets:     map[string]int{},
+	}, nil
+}
+
+func (h *Hand) Play(actor Actor, to io.Writer) (HandResult, error) {
+	for _, p := range h.players {
+		h.hole[p], _ = h.deck.Draw(2)
+	}
+	fmt.Fprintf(to, "Dealing to %s\n", strings.Join(h.players, ", "))
+
+	// antes are dead money, they go into the pot but do not count towards calling the blind
+	for _, p := range h.players {
+		h.pay(p, h.ante)
+	}
+
+	h.startStreet()
+	h.post(h.players[0], h.bigBlind/2)
+	h.post(h.players[1], h.bigBlind)
+	// a blind short of chips is all-in for what it could post
+	h.currentBet = h.streetBets[h.players[0]]
+	if h.streetBets[h.players[1]] > h.currentBet {
+		h.currentBet = h.streetBets[h.players[1]]
+	}
+	h.bettingRound(PreFlop, 2%len(h.players), actor, to)
+
+	// after the flop the first seat acts first, but heads-up the first seat is the button, so the big blind acts first
+	postFlopFirst := 0
+	if len(h.players) == 2 {
+		postFlopFirst = 1
+	}
+	for _, street := range []Street{Flop, Turn, River} {
+		if len(h.active()) == 1 || h.aborted {
+			break
+		}
+		n := 1
+		if street == Flop {
+			n = 3
+		}
+		cards, _ := h.deck.Draw(n)
+		h.community = append(h.community, cards...)
+		fmt.Fprintf(to, "%s: %s\n", street, formatCards(h.community))
+
+		h.startStreet()
+		h.bettingRound(street, postFlopFirst, actor, to)
+	}
+
+	if h.aborted {
+		return HandResult{}, ErrHandAborted
+	}
+
+	result, err := h.showdown()
+	if err != nil {
+		return result, err
+	}
+	switch {
+	case !result.Showdown:
+		fmt.Fprintf(to, "%s wins %d\n", result.Winners[0], result.Pot)
+	case len(result.Pots) == 1:
+		fmt.Fprintf(to, "%s wins %d with %s\n", strings.Join(result.Winners, " and "), result.Pot, result.Value)
+	default:
+		for i, pot := range result.Pots {
+			name := "the main pot"
+			if i > 0 {
+				name = "a side pot"
+			}
+			fmt.Fprintf(to, "%s wins %s of %d with %s\n", strings.Join(pot.Winners, " and "), name, pot.Amount, pot.Value)
+		}
+	}
+	return result, nil
+}
+
+func (h *Hand) startStreet() {
+	h.streetBets = map[string]int{}
+	h.currentBet = 0
+	h.minRaise = h.bigBlind
+}
+
+// pay puts up to amount of the player's chips into the pot, a player who runs out of chips is all-in.
+func (h *Hand) pay(player string, amount int) int {
+	if h.stacks != nil {
+		if amount >= h.stacks[player] {
+			amount = h.stacks[player]
+			h.allIn[player] = true
+		}
+		h.stacks[player] -= amount
+	}
+	h.bets[player] += amount
+	h.pot += amount
+	return amount
+}
+
+// post pays a bet in the current betting round.
+func (h *Hand) post(player string, amount int) {
+	h.streetBets[player] += h.pay(player, amount)
+}
+
+func (h *Hand) active() []string {
+	var active []string
+	for _, p := range h.players {
+		if !h.folded[p] {
+			active = append(active, p)
+		}
+	}
+	return active
+}
+
+// acting are the players still in the hand who are not all-in, so still have a say in the betting.
+func (h *Hand) acting() []string {
+	var acting []string
+	for _, p := range h.active() {
+		if !h.allIn[p] {
+			acting = append(acting, p)
+		}
+	}
+	return acting
+}
+
+func (h *Hand) view(player string, street Street) TableView {
+	return TableView{
+		Player:    player,
+		Street:    street,
+		Hole:      h.hole[player],
+		Community: append([]Card(nil), h.community...),
+		Pot:       h.pot,
+		ToCall:    h.currentBet - h.streetBets[player],
+		MinRaise:  h.minRaise,
+		Stack:     h.stacks[player],
+	}
+}
+
+/**
+bettingRound goes around the table from the first seat until every player still in the hand
+has either matched the current bet, folded or is all-in. A raise re-opens the action for everybody else.
+A player with nothing to call is not asked once everybody else is all-in, there is nobody left to bet against.
+*/
+func (h *Hand) bettingRound(street Street, first int, actor Actor, to io.Writer) {
+	pending := map[string]bool{}
+	for _, p := range h.acting() {
+		pending[p] = true
+	}
+
+	for seat := first; len(pending) > 0 && len(h.active()) > 1 && !h.aborted; seat = (seat + 1) % len(h.players) {
+		player := h.players[seat]
+		if !pending[player] {
+			continue
+		}
+		delete(pending, player)
+		if len(h.acting()) == 1 && h.streetBets[player] == h.currentBet {
+			continue
+		}
+
+		action := h.ask(player, street, actor, to)
+		switch action.Kind {
+		case Abort:
+			h.aborted = true
+		case Fold:
+			h.folded[player] = true
+			h.foldOrder = append(h.foldOrder, player)
+			fmt.Fprintf(to, "%s folds\n", player)
+		case Check:
+			fmt.Fprintf(to, "%s checks\n", player)
+		case Call:
+			h.post(player, h.currentBet-h.streetBets[player])
+			fmt.Fprintf(to, "%s calls %d%s\n", player, h.streetBets[player], h.allInMsg(player))
+		case Raise, AllIn:
+			bet := h.currentBet + action.Amount
+			if action.Kind == AllIn {
+				bet = h.streetBets[player] + h.stacks[player]
+			}
+			h.post(player, bet-h.streetBets[player])
+			if h.streetBets[player] <= h.currentBet {
+				fmt.Fprintf(to, "%s calls %d%s\n", player, h.streetBets[player], h.allInMsg(player))
+				break
+			}
+			// an all-in short of a full raise does not change what the next raise has to be
+			if raise := h.streetBets[player] - h.currentBet; raise >= h.minRaise {
+				h.minRaise = raise
+			}
+			h.currentBet = h.streetBets[player]
+			fmt.Fprintf(to, "%s raises to %d%s\n", player, h.currentBet, h.allInMsg(player))
+			for _, p := range h.acting() {
+				if p != player {
+					pending[p] = true
+				}
+			}
+		}
+	}
+}
+
+func (h *Hand) allInMsg(player string) string {
+	if h.allIn[player] {
+		return " and is all-in"
+	}
+	return ""
+}
+
+func (h *Hand) ask(player string, street Street, actor Actor, to io.Writer) Action {
+	for attempt := 0; attempt < maxActionAttempts; attempt++ {
+		view := h.view(player, street)
+		action := actor.Act(view)
+		err := validateAction(action, view)
+		if err == nil {
+			return action
+		}
+		fmt.Fprintf(to, "%s: %v\n", player, err)
+	}
+	return Action{Kind: Fold}
+}
+
+func validateAction(action Action, view TableView) error {
+	switch action.Kind {
+	case Fold, Abort:
+	case Check:
+		if view.ToCall > 0 {
+			return fmt.Errorf("cannot check, %d to call", view.ToCall)
+		}
+	case Call:
+		if view.ToCall == 0 {
+			return fmt.Errorf("nothing to call, check instead")
+		}
+	case Raise:
+		allIn := view.Stack > 0 && view.ToCall+action.Amount >= view.Stack
+		if action.Amount < view.MinRaise && !allIn {
+			return fmt.Errorf("raise must be at least %d", view.MinRaise)
+		}
+	case AllIn:
+		if view.Stack == 0 {
+			return fmt.Errorf("every bet is covered in this game, bet or raise instead")
+		}
+	default:
+		return fmt.Errorf("unknown action %d", action.Kind)
+	}
+	return nil
+}
+
+func (h *Hand) showdown() (HandResult, error) {
+	eliminated := append([]string(nil), h.foldOrder...)
+	active := h.active()
+	if len(active) == 1 {
+		return HandResult{Winners: active, Eliminated: eliminated, Pot: h.pot, Bets: h.bets,
+			Won: map[string]int{active[0]: h.pot}, Pots: []SidePot{{Amount: h.pot, Winners: active}}}, nil
+	}
+
+	result := HandResult{Pot: h.pot, Bets: h.bets, Won: map[string]int{}, Showdown: true}
+	values := map[string]HandValue{}
+	for _, p := range active {
+		value, err := EvaluateHand(append(append([]Card(nil), h.hole[p]...), h.community...))
+		if err != nil {
+			return HandResult{}, fmt.Errorf("problem evaluating hand of %s, %v", p, err)
+		}
+		values[p] = value
+	}
+
+	for _, pot := range h.pots(active) {
+		contenders := pot.Winners
+		pot.Winners = nil
+		for _, p := range contenders {
+			switch {
+			case len(pot.Winners) == 0 || values[p].Compare(pot.Value) > 0:
+				pot.Winners = []string{p}
+				pot.Value = values[p]
+			case values[p].Compare(pot.Value) == 0:
+				pot.Winners = append(pot.Winners, p)
+			}
+		}
+		// the odd chips of a split pot go to the first winner
+		for i, w := range pot.Winners {
+			result.Won[w] += pot.Amount / len(pot.Winners)
+			if i == 0 {
+				result.Won[w] += pot.Amount % len(pot.Winners)
+			}
+		}
+		result.Pots = append(result.Pots, pot)
+	}
+	result.Winners = result.Pots[0].Winners
+	result.Value = result.Pots[0].Value
+
+	var losers []string
+	for _, p := range active {
+		if values[p].Compare(result.Value) < 0 {
+			losers = append(losers, p)
+		}
+	}
+	sort.SliceStable(losers, func(i, j int) bool {
+		return values[losers[i]].Compare(values[losers[j]]) < 0
+	})
+	result.Eliminated = append(eliminated, losers...)
+	return result, nil
+}
+
+/**
+pots splits the chips bet into the main pot, which every player still in the hand can win,
+and a side pot for every all-in player who bet less than the others, which only those who
+bet more can win. The Winners of each pot are everybody in the running for it until the
+showdown decides between them, the chips of players who folded go into the pots they bet towards.
+*/
+func (h *Hand) pots(active []string) []SidePot {
+	var levels []int
+	for _, p := range active {
+		levels = append(levels, h.bets[p])
+	}
+	sort.Ints(levels)
+
+	var pots []SidePot
+	previous := 0
+	for i, level := range levels {
+		if level == previous {
+			continue
+		}
+		last := i == len(levels)-1
+		pot := SidePot{}
+		for _, p := range h.players {
+			bet := h.bets[p]
+			// the last pot takes whatever was bet above the others too, that of a player who folded included
+			if !last && bet > level {
+				bet = level
+			}
+			if bet > previous {
+				pot.Amount += bet - previous
+			}
+		}
+		for _, p := range active {
+			if h.bets[p] >= level {
+				pot.Winners = append(pot.Winners, p)
+			}
+		}
+		pots = append(pots, pot)
+		previous = level
+	}
+	return pots
+}
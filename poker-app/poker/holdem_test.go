@@ -0,0 +1,269 @@
+package poker_test
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ydsxiong/go-playground/poker-app/poker"
+)
+
+// passiveActor checks when it can and calls otherwise, so every hand goes to a showdown
+var passiveActor = poker.ActorFunc(func(view poker.TableView) poker.Action {
+	if view.ToCall > 0 {
+		return poker.Action{Kind: poker.Call}
+	}
+	return poker.Action{Kind: poker.Check}
+})
+
+func TestDeck(t *testing.T) {
+	deck := poker.NewShuffledDeck(rand.New(rand.NewSource(1)))
+
+	cards, err := deck.Draw(52)
+	if err != nil {
+		t.Fatalf("could not draw the whole deck, %v", err)
+	}
+
+	seen := map[poker.Card]bool{}
+	for _, c := range cards {
+		if seen[c] {
+			t.Fatalf("card %s dealt twice", c)
+		}
+		seen[c] = true
+	}
+
+	if _, err := deck.Draw(1); err == nil {
+		t.Error("expected an error drawing from an empty deck")
+	}
+}
+
+func TestHand(t *testing.T) {
+
+	t.Run("the best hand at showdown wins the pot", func(t *testing.T) {
+		// Chris: As Ad, Cleo: Ks Kd, board: 2c 7h 9s Jd 3c
+		deck := poker.NewStackedDeck(poker.MustParseCards("As Ad Ks Kd 2c 7h 9s Jd 3c")...)
+		hand := mustMakeHand(t, []string{"Chris", "Cleo"}, deck)
+
+		out := &bytes.Buffer{}
+		result, err := hand.Play(passiveActor, out)
+		if err != nil {
+			t.Fatalf("did not expect an error, %v", err)
+		}
+
+		assertWinners(t, result, "Chris")
+		if !result.Showdown || result.Value.Category != poker.OnePair {
+			t.Errorf("expected a showdown won with one pair, got %+v", result)
+		}
+		if result.Pot != 200 {
+			t.Errorf("got pot %d, want %d", result.Pot, 200)
+		}
+		if !strings.Contains(out.String(), "River: 2c 7h 9s Jd 3c") {
+			t.Errorf("expected the board to be dealt, got %q", out.String())
+		}
+	})
+
+	t.Run("equal hands split the pot", func(t *testing.T) {
+		deck := poker.NewStackedDeck(poker.MustParseCards("2s 3d 2h 3c Ac Kh Qs Jd Tc")...)
+		hand := mustMakeHand(t, []string{"Chris", "Cleo"}, deck)
+
+		result, _ := hand.Play(passiveActor, &bytes.Buffer{})
+
+		assertWinners(t, result, "Chris", "Cleo")
+	})
+
+	t.Run("the last player left wins without a showdown", func(t *testing.T) {
+		deck := poker.NewShuffledDeck(rand.New(rand.NewSource(1)))
+		hand := mustMakeHand(t, []string{"Chris", "Cleo", "Pepper"}, deck)
+
+		// pre-flop Pepper acts first and raises, everybody else folds
+		actor := poker.ActorFunc(func(view poker.TableView) poker.Action {
+			if view.Player == "Pepper" {
+				return poker.Action{Kind: poker.Raise, Amount: 200}
+			}
+			return poker.Action{Kind: poker.Fold}
+		})
+
+		result, _ := hand.Play(actor, &bytes.Buffer{})
+
+		assertWinners(t, result, "Pepper")
+		if result.Showdown {
+			t.Error("did not expect a showdown")
+		}
+		if result.Pot != 450 {
+			t.Errorf("got pot %d, want %d", result.Pot, 450)
+		}
+	})
+
+	t.Run("an invalid action is asked again and then folded", func(t *testing.T) {
+		deck := poker.NewShuffledDeck(rand.New(rand.NewSource(1)))
+		hand := mustMakeHand(t, []string{"Chris", "Cleo"}, deck)
+
+		asked := 0
+		actor := poker.ActorFunc(func(view poker.TableView) poker.Action {
+			asked++
+			return poker.Action{Kind: poker.Check}
+		})
+
+		result, _ := hand.Play(actor, &bytes.Buffer{})
+
+		assertWinners(t, result, "Cleo")
+		if asked != 3 {
+			t.Errorf("expected Chris to be asked 3 times, got %d", asked)
+		}
+	})
+
+	t.Run("players are eliminated in the order they fold, then from the weakest hand at showdown", func(t *testing.T) {
+		deck := poker.NewStackedDeck(poker.MustParseCards("As Ad Ks Kd 2h 5s 8d 8c 2c 7h 9s Jd 4c")...)
+		hand := mustMakeHand(t, []string{"Chris", "Cleo", "Pepper", "Ruth"}, deck)
+
+		actor := poker.ActorFunc(func(view poker.TableView) poker.Action {
+			if view.Player == "Ruth" {
+				return poker.Action{Kind: poker.Fold}
+			}
+			return passiveActor(view)
+		})
+		result, _ := hand.Play(actor, &bytes.Buffer{})
+
+		assertWinners(t, result, "Chris")
+		if want := []string{"Ruth", "Pepper", "Cleo"}; !reflect.DeepEqual(result.Eliminated, want) {
+			t.Errorf("got eliminated %v, want %v", result.Eliminated, want)
+		}
+	})
+
+	t.Run("heads-up the button acts first before the flop and the big blind after it", func(t *testing.T) {
+		deck := poker.NewShuffledDeck(rand.New(rand.NewSource(1)))
+		hand := mustMakeHand(t, []string{"Chris", "Cleo"}, deck)
+
+		acted := map[poker.Street][]string{}
+		hand.Play(poker.ActorFunc(func(view poker.TableView) poker.Action {
+			acted[view.Street] = append(acted[view.Street], view.Player)
+			return passiveActor.Act(view)
+		}), &bytes.Buffer{})
+
+		want := map[poker.Street][]string{
+			poker.PreFlop: {"Chris", "Cleo"},
+			poker.Flop:    {"Cleo", "Chris"},
+			poker.Turn:    {"Cleo", "Chris"},
+			poker.River:   {"Cleo", "Chris"},
+		}
+		if !reflect.DeepEqual(acted, want) {
+			t.Errorf("got players acting %v, want %v", acted, want)
+		}
+	})
+
+	t.Run("nobody bets more than their stack and an all-in player only wins the pot they could cover", func(t *testing.T) {
+		// Chris: As Ad, Cleo: Ks Kd, Pepper: Qs Qd, board: 2c 7h 9s Jd 3c
+		deck := poker.NewStackedDeck(poker.MustParseCards("As Ad Ks Kd Qs Qd 2c 7h 9s Jd 3c")...)
+		hand, err := poker.NewHand([]string{"Chris", "Cleo", "Pepper"}, map[string]int{"Chris": 80, "Cleo": 500, "Pepper": 500}, deck, 100, 0)
+		assertNoError(t, err)
+
+		// Pepper raises far more than anybody has and everybody calls
+		actor := poker.ActorFunc(func(view poker.TableView) poker.Action {
+			if view.Player == "Pepper" && view.Street == poker.PreFlop {
+				return poker.Action{Kind: poker.Raise, Amount: 5000}
+			}
+			return passiveActor(view)
+		})
+		out := &bytes.Buffer{}
+		result, err := hand.Play(actor, out)
+		assertNoError(t, err)
+
+		if want := map[string]int{"Chris": 80, "Cleo": 500, "Pepper": 500}; !reflect.DeepEqual(result.Bets, want) {
+			t.Errorf("got bets %v, want %v", result.Bets, want)
+		}
+		if want := map[string]int{"Chris": 240, "Cleo": 840}; !reflect.DeepEqual(result.Won, want) {
+			t.Errorf("got winnings %v, want %v", result.Won, want)
+		}
+		assertWinners(t, result, "Chris")
+		if len(result.Pots) != 2 || result.Pots[0].Amount != 240 || result.Pots[1].Amount != 840 {
+			t.Errorf("expected a main pot of 240 and a side pot of 840, got %+v", result.Pots)
+		}
+		if !strings.Contains(out.String(), "Pepper raises to 500 and is all-in\nChris calls 80 and is all-in\n") ||
+			!strings.Contains(out.String(), "Cleo wins a side pot of 840 with") {
+			t.Errorf("expected the all-ins and the side pot to be reported, got %q", out.String())
+		}
+	})
+
+	t.Run("a raise short of the minimum is only allowed all-in", func(t *testing.T) {
+		deck := poker.NewShuffledDeck(rand.New(rand.NewSource(1)))
+		hand, _ := poker.NewHand([]string{"Chris", "Cleo"}, map[string]int{"Chris": 1000, "Cleo": 1000}, deck, 100, 0)
+
+		var views []poker.TableView
+		result, _ := hand.Play(poker.ActorFunc(func(view poker.TableView) poker.Action {
+			views = append(views, view)
+			if len(views) == 1 {
+				return poker.Action{Kind: poker.Raise, Amount: 60}
+			}
+			return poker.Action{Kind: poker.AllIn}
+		}), &bytes.Buffer{})
+
+		if len(views) < 2 || views[0].Stack != 950 || views[1].Player != "Chris" {
+			t.Errorf("expected Chris to be asked again after a short raise, got %+v", views)
+		}
+		if result.Bets["Chris"] != 1000 || result.Bets["Cleo"] != 1000 {
+			t.Errorf("expected both players all-in, got bets %v", result.Bets)
+		}
+	})
+
+	t.Run("an aborted hand has no winner", func(t *testing.T) {
+		deck := poker.NewShuffledDeck(rand.New(rand.NewSource(1)))
+		hand := mustMakeHand(t, []string{"Chris", "Cleo"}, deck)
+
+		_, err := hand.Play(poker.ActorFunc(func(view poker.TableView) poker.Action {
+			return poker.Action{Kind: poker.Abort}
+		}), &bytes.Buffer{})
+
+		if err != poker.ErrHandAborted {
+			t.Errorf("got error %v, want %v", err, poker.ErrHandAborted)
+		}
+	})
+
+	t.Run("it refuses a hand with duplicate players", func(t *testing.T) {
+		_, err := poker.NewHand([]string{"Chris", "Chris"}, nil, poker.NewDeck(), 100, 0)
+		if err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestParseAction(t *testing.T) {
+	cases := map[string]poker.Action{
+		"fold":      {Kind: poker.Fold},
+		"Check":     {Kind: poker.Check},
+		"call":      {Kind: poker.Call},
+		"bet 200":   {Kind: poker.Raise, Amount: 200},
+		"raise 400": {Kind: poker.Raise, Amount: 400},
+		"abort":     {Kind: poker.Abort},
+		"all-in":    {Kind: poker.AllIn},
+	}
+	for input, want := range cases {
+		got, err := poker.ParseAction(input)
+		if err != nil || got != want {
+			t.Errorf("parsing %q got %+v (%v), want %+v", input, got, err, want)
+		}
+	}
+
+	for _, input := range []string{"", "bet", "raise lots", "Chris wins"} {
+		if _, err := poker.ParseAction(input); err == nil {
+			t.Errorf("expected an error parsing %q", input)
+		}
+	}
+}
+
+func mustMakeHand(t *testing.T, players []string, deck *poker.Deck) *poker.Hand {
+	t.Helper()
+	hand, err := poker.NewHand(players, nil, deck, 100, 0)
+	if err != nil {
+		t.Fatalf("problem creating hand, %v", err)
+	}
+	return hand
+}
+
+func assertWinners(t *testing.T, result poker.HandResult, winners ...string) {
+	t.Helper()
+	if !reflect.DeepEqual(result.Winners, winners) {
+		t.Errorf("got winners %v, want %v", result.Winners, winners)
+	}
+}
@@ -0,0 +1,251 @@
+package poker
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// The formats a league can be exported in and imported from.
+const (
+	CSVFormat  = "csv"
+	JSONFormat = "json"
+)
+
+// The ways an imported league can be merged into the store, see MergeLeagues.
+const (
+	MergeSum     = "sum"
+	MergeReplace = "replace"
+	MergeMax     = "max"
+)
+
+var leagueCSVHeader = []string{"Name", "Wins", "Played", "Rating", "Cashes", "BuyIns", "Winnings"}
+
+// WriteLeagueCSV writes one row per player, after a header naming the columns.
+func WriteLeagueCSV(out io.Writer, league League) error {
+	w := csv.NewWriter(out)
+	w.Write(leagueCSVHeader)
+	for _, p := range league {
+		w.Write([]string{
+			p.Name,
+			strconv.Itoa(p.Wins),
+			strconv.Itoa(p.Played),
+			strconv.FormatFloat(p.Rating, 'f', -1, 64),
+			strconv.Itoa(p.Cashes),
+			strconv.Itoa(p.BuyIns),
+			strconv.Itoa(p.Winnings),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+/**
+ReadLeague reads a league exported in the given format. A CSV league needs a header row,
+the columns are found by name so they can come in any order, and only Name and Wins are required.
+A JSON league is a list of players as the league file stores them, or as /league returns them.
+*/
+func ReadLeague(in io.Reader, format string) (League, error) {
+	var league League
+	switch strings.ToLower(format) {
+	case CSVFormat:
+		var err error
+		if league, err = readLeagueCSV(in); err != nil {
+			return nil, err
+		}
+	case JSONFormat:
+		if err := json.NewDecoder(in).Decode(&league); err != nil {
+			return nil, fmt.Errorf("problem parsing league, %v", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown league format %q, expecting %s or %s", format, CSVFormat, JSONFormat)
+	}
+	return league, validateLeague(league)
+}
+
+func readLeagueCSV(in io.Reader) (League, error) {
+	rows, err := csv.NewReader(in).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("problem parsing league, %v", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("problem parsing league, the header row is missing")
+	}
+
+	columns := map[string]int{}
+	for i, name := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"name", "wins"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("problem parsing league, there is no %s column", required)
+		}
+	}
+
+	var league League
+	for i, row := range rows[1:] {
+		var p Player
+		var err error
+		field := func(column string) string {
+			if c, ok := columns[column]; ok && c < len(row) {
+				return strings.TrimSpace(row[c])
+			}
+			return ""
+		}
+		number := func(column string) int {
+			n := 0
+			if value := field(column); value != "" && err == nil {
+				if n, err = strconv.Atoi(value); err != nil {
+					err = fmt.Errorf("%s %q is not a number", column, value)
+				}
+			}
+			return n
+		}
+
+		p.Name = field("name")
+		p.Wins, p.Played, p.Cashes, p.BuyIns, p.Winnings = number("wins"), number("played"), number("cashes"), number("buyins"), number("winnings")
+		if rating := field("rating"); rating != "" && err == nil {
+			if p.Rating, err = strconv.ParseFloat(rating, 64); err != nil {
+				err = fmt.Errorf("rating %q is not a number", rating)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("problem parsing league, row %d: %v", i+2, err)
+		}
+		league = append(league, p)
+	}
+	return league, nil
+}
+
+func validateLeague(league League) error {
+	seen := map[string]bool{}
+	for _, p := range league {
+		switch {
+		case p.Name == "":
+			return fmt.Errorf("every player in the league needs a name")
+		case seen[p.Name]:
+			return fmt.Errorf("%s is in the league more than once", p.Name)
+		case p.Wins < 0 || p.Played < 0 || p.Rating < 0 || p.Cashes < 0 || p.BuyIns < 0 || p.Winnings < 0:
+			return fmt.Errorf("%s has a negative record %+v", p.Name, p)
+		}
+		seen[p.Name] = true
+	}
+	return nil
+}
+
+// LeagueConflict is a player who is in both leagues, with the record they would end up with.
+type LeagueConflict struct {
+	Name   string
+	Ours   Player
+	Theirs Player
+	Merged Player
+}
+
+/**
+MergeReport says what merging a league does: the players it adds and the players already in
+the league whose records it changes. Players whose record it leaves as it is are only counted.
+*/
+type MergeReport struct {
+	Strategy  string
+	DryRun    bool
+	Added     []string
+	Conflicts []LeagueConflict
+	Unchanged int
+}
+
+/**
+MergeLeagues merges their league into ours, players only they have are added and the players
+in both are merged with the strategy given:
+
+	sum      adds their results to ours, as when both leagues played separate games,
+	         the ratings are averaged over the games played
+	replace  takes their record over ours
+	max      takes the higher of the two for every number
+
+Ours is left as it was, the merged league is returned along with a report of what changed.
+*/
+func MergeLeagues(ours, theirs League, strategy string) (League, MergeReport, error) {
+	report := MergeReport{Strategy: strategy}
+	var merge func(ours, theirs Player) Player
+	switch strategy {
+	case MergeSum:
+		merge = sumPlayers
+	case MergeReplace:
+		merge = func(ours, theirs Player) Player { return theirs }
+	case MergeMax:
+		merge = maxPlayers
+	default:
+		return nil, report, fmt.Errorf("unknown merge strategy %q, expecting %s, %s or %s", strategy, MergeSum, MergeReplace, MergeMax)
+	}
+
+	merged := append(League{}, ours...)
+	for _, p := range theirs {
+		existing := merged.Lookup(p.Name)
+		if existing == nil {
+			merged = append(merged, p)
+			report.Added = append(report.Added, p.Name)
+			continue
+		}
+		result := merge(*existing, p)
+		result.Name, result.DisplayName, result.Aliases = existing.Name, existing.DisplayName, existing.Aliases
+		if result.DisplayName == "" {
+			result.DisplayName = p.DisplayName
+		}
+		for _, alias := range p.Aliases {
+			if other := merged.Lookup(alias); other == nil {
+				result.Aliases = withAlias(result.Aliases, alias)
+			}
+		}
+		if reflect.DeepEqual(result, *existing) {
+			report.Unchanged++
+			continue
+		}
+		report.Conflicts = append(report.Conflicts, LeagueConflict{existing.Name, *existing, p, result})
+		*existing = result
+	}
+	sortLeague(merged)
+	return merged, report, nil
+}
+
+func sumPlayers(ours, theirs Player) Player {
+	merged := ours
+	merged.Wins += theirs.Wins
+	merged.Played += theirs.Played
+	merged.Cashes += theirs.Cashes
+	merged.BuyIns += theirs.BuyIns
+	merged.Winnings += theirs.Winnings
+
+	// a rating is only worked out once a player has played a rated game, neither having one keeps it that way
+	if ours.Rating == 0 && theirs.Rating == 0 {
+		return merged
+	}
+	ourGames, theirGames := float64(ours.GamesPlayed()), float64(theirs.GamesPlayed())
+	if ourGames+theirGames == 0 {
+		merged.Rating = math.Max(ours.Rating, theirs.Rating)
+		return merged
+	}
+	merged.Rating = math.Round((ours.CurrentRating()*ourGames+theirs.CurrentRating()*theirGames)/(ourGames+theirGames)*10) / 10
+	return merged
+}
+
+func maxPlayers(ours, theirs Player) Player {
+	max := func(a, b int) int {
+		if a > b {
+			return a
+		}
+		return b
+	}
+	merged := ours
+	merged.Wins = max(ours.Wins, theirs.Wins)
+	merged.Played = max(ours.Played, theirs.Played)
+	merged.Rating = math.Max(ours.Rating, theirs.Rating)
+	merged.Cashes = max(ours.Cashes, theirs.Cashes)
+	merged.BuyIns = max(ours.BuyIns, theirs.BuyIns)
+	merged.Winnings = max(ours.Winnings, theirs.Winnings)
+	return merged
+}
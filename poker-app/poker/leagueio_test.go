@@ -0,0 +1,175 @@
+package poker_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ydsxiong/go-playground/poker-app/poker"
+)
+
+func TestMergeLeagues(t *testing.T) {
+	ours := poker.League{
+		{Name: "Chris", Wins: 3, Played: 5, Rating: 1520, BuyIns: 50, Winnings: 80},
+		{Name: "Cleo", Wins: 1, Played: 5, Rating: 1480},
+	}
+	theirs := poker.League{
+		{Name: "Chris", Wins: 2, Played: 5, Rating: 1480, BuyIns: 50},
+		{Name: "Cleo", Wins: 1, Played: 5, Rating: 1480},
+		{Name: "Pepper", Wins: 4, Played: 6, Rating: 1550},
+	}
+
+	cases := []struct {
+		strategy string
+		chris    poker.Player
+		cleo     poker.Player
+	}{
+		{poker.MergeSum,
+			poker.Player{Name: "Chris", Wins: 5, Played: 10, Rating: 1500, BuyIns: 100, Winnings: 80},
+			poker.Player{Name: "Cleo", Wins: 2, Played: 10, Rating: 1480}},
+		{poker.MergeReplace,
+			poker.Player{Name: "Chris", Wins: 2, Played: 5, Rating: 1480, BuyIns: 50},
+			poker.Player{Name: "Cleo", Wins: 1, Played: 5, Rating: 1480}},
+		{poker.MergeMax,
+			poker.Player{Name: "Chris", Wins: 3, Played: 5, Rating: 1520, BuyIns: 50, Winnings: 80},
+			poker.Player{Name: "Cleo", Wins: 1, Played: 5, Rating: 1480}},
+	}
+	for _, c := range cases {
+		t.Run(c.strategy, func(t *testing.T) {
+			merged, report, err := poker.MergeLeagues(ours, theirs, c.strategy)
+			assertNoError(t, err)
+
+			if got := *merged.Find("Chris"); !reflect.DeepEqual(got, c.chris) {
+				t.Errorf("got Chris %+v, want %+v", got, c.chris)
+			}
+			if got := *merged.Find("Cleo"); !reflect.DeepEqual(got, c.cleo) {
+				t.Errorf("got Cleo %+v, want %+v", got, c.cleo)
+			}
+			if !reflect.DeepEqual(report.Added, []string{"Pepper"}) {
+				t.Errorf("got %v added, want Pepper", report.Added)
+			}
+			if ours.Find("Pepper") != nil || ours.Find("Chris").Wins != 3 {
+				t.Errorf("our league was changed by the merge, %v", ours)
+			}
+		})
+	}
+
+	t.Run("a player whose record the merge changes is reported as a conflict", func(t *testing.T) {
+		_, report, _ := poker.MergeLeagues(ours, theirs, poker.MergeMax)
+
+		if len(report.Conflicts) != 0 || report.Unchanged != 2 {
+			t.Errorf("expected nothing to change taking the max, got %+v", report)
+		}
+
+		_, report, _ = poker.MergeLeagues(ours, theirs, poker.MergeReplace)
+		if len(report.Conflicts) != 1 || report.Conflicts[0].Name != "Chris" || report.Unchanged != 1 {
+			t.Errorf("expected Chris to be the only conflict, got %+v", report)
+		}
+	})
+
+	t.Run("it rejects unknown strategies and leagues naming a player twice", func(t *testing.T) {
+		if _, _, err := poker.MergeLeagues(ours, theirs, "average"); err == nil {
+			t.Error("expected an error for an unknown strategy")
+		}
+		if _, err := poker.ReadLeague(strings.NewReader(`[{"Name":"Chris"},{"Name":"Chris"}]`), poker.JSONFormat); err == nil {
+			t.Error("expected an error for a player in the league twice")
+		}
+	})
+}
+
+func TestLeagueCSV(t *testing.T) {
+	league := poker.League{
+		{Name: "Chris", Wins: 3, Played: 5, Rating: 1516.5, Cashes: 2, BuyIns: 50, Winnings: 80},
+		{Name: "Cleo", Wins: 1},
+	}
+
+	t.Run("a league written as CSV reads back the same", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		assertNoError(t, poker.WriteLeagueCSV(out, league))
+
+		want := "Name,Wins,Played,Rating,Cashes,BuyIns,Winnings\nChris,3,5,1516.5,2,50,80\nCleo,1,0,0,0,0,0\n"
+		if out.String() != want {
+			t.Errorf("got %q, want %q", out.String(), want)
+		}
+
+		got, err := poker.ReadLeague(out, poker.CSVFormat)
+		assertNoError(t, err)
+		assertleague(t, got, league)
+	})
+
+	t.Run("the columns can come in any order and only the name and wins are needed", func(t *testing.T) {
+		got, err := poker.ReadLeague(strings.NewReader("wins,name\n3,Chris\n1,Cleo\n"), poker.CSVFormat)
+		assertNoError(t, err)
+		assertleague(t, got, poker.League{{Name: "Chris", Wins: 3}, {Name: "Cleo", Wins: 1}})
+	})
+
+	t.Run("it reports the row that is wrong", func(t *testing.T) {
+		_, err := poker.ReadLeague(strings.NewReader("Name,Wins\nChris,3\nCleo,lots\n"), poker.CSVFormat)
+		if err == nil || !strings.Contains(err.Error(), "row 3") {
+			t.Errorf("expected an error about row 3, got %v", err)
+		}
+	})
+}
+
+func TestLeagueExportImportAPI(t *testing.T) {
+	newServer := func(store poker.PlayerStore) http.Handler {
+		server, err := poker.NewPlayerServer(store, dummyGame, poker.NewTableRegistry(func() poker.Game { return dummyGame }), dummyGameStore, dummyStructures)
+		assertNoError(t, err)
+		return server
+	}
+
+	t.Run("it exports the league as CSV when asked for it", func(t *testing.T) {
+		store := poker.GetInMemoryStore(map[string]int{"Cleo": 1, "Chris": 3})
+		server := newServer(store)
+
+		want := "Name,Wins,Played,Rating,Cashes,BuyIns,Winnings\nChris,3,0,0,0,0,0\nCleo,1,0,0,0,0,0\n"
+
+		res := serveRequest(server, http.MethodGet, "/league?format=csv", "")
+		assertStatus(t, res, http.StatusOK)
+		if res.Header().Get("content-type") != "text/csv" || res.Body.String() != want {
+			t.Errorf("got %s %q, want CSV %q", res.Header().Get("content-type"), res.Body.String(), want)
+		}
+
+		req, _ := http.NewRequest(http.MethodGet, "/league", nil)
+		req.Header.Set("Accept", "text/csv")
+		if res := serveRequestWith(server, req); res.Body.String() != want {
+			t.Errorf("got %q asking for text/csv, want %q", res.Body.String(), want)
+		}
+	})
+
+	t.Run("an import is a dry run unless asked to apply it", func(t *testing.T) {
+		store := poker.GetInMemoryStore(map[string]int{"Chris": 3})
+		server := newServer(store)
+		body := "Name,Wins\nChris,2\nPepper,4\n"
+
+		req, _ := http.NewRequest(http.MethodPost, "/league/import?strategy=sum", strings.NewReader(body))
+		req.Header.Set("content-type", "text/csv")
+		res := serveRequestWith(server, req)
+		assertStatus(t, res, http.StatusOK)
+
+		var report poker.MergeReport
+		json.NewDecoder(res.Body).Decode(&report)
+		if !report.DryRun || len(report.Conflicts) != 1 || report.Conflicts[0].Merged.Wins != 5 || !reflect.DeepEqual(report.Added, []string{"Pepper"}) {
+			t.Errorf("unexpected report %+v", report)
+		}
+		assertScore(t, store, "Chris", 3)
+
+		res = serveRequest(server, http.MethodPost, "/league/import?strategy=sum&format=csv&apply=true", body)
+		assertStatus(t, res, http.StatusOK)
+		assertScore(t, store, "Chris", 5)
+		assertScore(t, store, "Pepper", 4)
+
+		assertNoError(t, store.UndoLastWin())
+		assertScore(t, store, "Chris", 3)
+	})
+
+	t.Run("a bad league or strategy is a bad request", func(t *testing.T) {
+		server := newServer(poker.GetInMemoryStore())
+
+		assertStatus(t, serveRequest(server, http.MethodPost, "/league/import", `{"Name":`), http.StatusBadRequest)
+		assertStatus(t, serveRequest(server, http.MethodPost, "/league/import?strategy=average", `[{"Name":"Chris","Wins":1}]`), http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,165 @@
+package poker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/**
+PayoutTable splits the prize pool between the places paid, as percentages of the pool,
+"50/30/20" pays half to first place, 30% to second and 20% to third.
+*/
+type PayoutTable []int
+
+var WinnerTakesAll = PayoutTable{100}
+
+func ParsePayoutTable(input string) (PayoutTable, error) {
+	var payouts PayoutTable
+	for _, field := range strings.Split(input, "/") {
+		percentage, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("invalid payout %q, expecting percentages such as 50/30/20", field)
+		}
+		payouts = append(payouts, percentage)
+	}
+	return payouts, payouts.Validate()
+}
+
+func (p PayoutTable) Validate() error {
+	total := 0
+	for _, percentage := range p {
+		if percentage <= 0 {
+			return fmt.Errorf("every place paid needs a share of the pool, got %s", p)
+		}
+		total += percentage
+	}
+	if total != 100 {
+		return fmt.Errorf("the payouts %s add up to %d%%, not 100%%", p, total)
+	}
+	return nil
+}
+
+func (p PayoutTable) String() string {
+	var percentages []string
+	for _, percentage := range p {
+		percentages = append(percentages, strconv.Itoa(percentage))
+	}
+	return strings.Join(percentages, "/")
+}
+
+/**
+BuyIn is what every player pays to take part in a game and how the pool is paid out.
+The zero value is a game played for fun, nobody pays and nobody is paid.
+*/
+type BuyIn struct {
+	Amount  int
+	Payouts PayoutTable
+}
+
+// ParseBuyIn reads a buy-in such as "100" or "100 50/30/20", winner takes all unless payouts are given.
+func ParseBuyIn(input string) (BuyIn, error) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		return BuyIn{}, nil
+	}
+	if len(fields) > 2 {
+		return BuyIn{}, fmt.Errorf("expecting a buy-in optionally followed by payouts, e.g. 100 50/30/20, got %q", input)
+	}
+
+	amount, err := strconv.Atoi(fields[0])
+	if err != nil || amount < 0 {
+		return BuyIn{}, fmt.Errorf("invalid buy-in %q", fields[0])
+	}
+	buyIn := BuyIn{Amount: amount, Payouts: WinnerTakesAll}
+	if len(fields) == 2 {
+		if buyIn.Payouts, err = ParsePayoutTable(fields[1]); err != nil {
+			return BuyIn{}, err
+		}
+	}
+	return buyIn, nil
+}
+
+func (b BuyIn) Validate(numberOfPlayers int) error {
+	if b.Amount < 0 {
+		return fmt.Errorf("the buy-in cannot be negative, got %d", b.Amount)
+	}
+	if b.Amount == 0 {
+		return nil
+	}
+	if err := b.Payouts.Validate(); err != nil {
+		return err
+	}
+	if len(b.Payouts) > numberOfPlayers {
+		return fmt.Errorf("cannot pay %d places with %d players", len(b.Payouts), numberOfPlayers)
+	}
+	return nil
+}
+
+func (b BuyIn) String() string {
+	if b.Amount == 0 {
+		return "for fun"
+	}
+	return fmt.Sprintf("%d a head, paying %s", b.Amount, b.Payouts)
+}
+
+// Placing is where a player finished in a game, what they paid to play and what they won.
+type Placing struct {
+	Name     string
+	Position int
+	BuyIn    int
+	Prize    int
+}
+
+func (p Placing) Profit() int {
+	return p.Prize - p.BuyIn
+}
+
+/**
+Placings ranks the players of a finished game: the winners share first place, everybody else
+finishes in the reverse of the order they were knocked out in. Players who tie share the prizes
+of the places they take up, the chips that do not split evenly go to the first player placed.
+*/
+func (b BuyIn) Placings(players []string, result HandResult) []Placing {
+	var groups [][]string
+	placed := map[string]bool{}
+	addGroup := func(names []string) {
+		var group []string
+		for _, name := range names {
+			if !placed[name] {
+				placed[name] = true
+				group = append(group, name)
+			}
+		}
+		if len(group) > 0 {
+			groups = append(groups, group)
+		}
+	}
+
+	addGroup(result.Winners)
+	for i := len(result.Eliminated) - 1; i >= 0; i-- {
+		addGroup(result.Eliminated[i : i+1])
+	}
+	// anybody the result does not account for shares last place
+	addGroup(players)
+
+	pool := b.Amount * len(players)
+	var placings []Placing
+	paid := 0
+	for _, group := range groups {
+		position := len(placings) + 1
+		share := 0
+		for place := position; place < position+len(group) && place <= len(b.Payouts); place++ {
+			share += b.Payouts[place-1]
+		}
+		prize := pool * share / 100 / len(group)
+		for _, name := range group {
+			placings = append(placings, Placing{Name: name, Position: position, BuyIn: b.Amount, Prize: prize})
+			paid += prize
+		}
+	}
+	if len(placings) > 0 && paid > 0 {
+		placings[0].Prize += pool - paid
+	}
+	return placings
+}
@@ -0,0 +1,149 @@
+package poker_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ydsxiong/go-playground/poker-app/poker"
+)
+
+func TestParseBuyIn(t *testing.T) {
+	cases := map[string]poker.BuyIn{
+		"":             {},
+		"100":          {Amount: 100, Payouts: poker.WinnerTakesAll},
+		"100 50/30/20": {Amount: 100, Payouts: poker.PayoutTable{50, 30, 20}},
+	}
+	for input, want := range cases {
+		got, err := poker.ParseBuyIn(input)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("ParseBuyIn(%q) got %+v (%v), want %+v", input, got, err, want)
+		}
+	}
+
+	for _, input := range []string{"lots", "-5", "100 50/30", "100 60/50/-10", "100 50/30/20 extra"} {
+		if _, err := poker.ParseBuyIn(input); err == nil {
+			t.Errorf("expected an error parsing %q", input)
+		}
+	}
+}
+
+func TestPlacings(t *testing.T) {
+	players := []string{"Chris", "Cleo", "Pepper", "Ruth"}
+
+	t.Run("it pays the places in the order players were knocked out", func(t *testing.T) {
+		buyIn := poker.BuyIn{Amount: 100, Payouts: poker.PayoutTable{50, 30, 20}}
+		result := poker.HandResult{Winners: []string{"Chris"}, Eliminated: []string{"Ruth", "Pepper", "Cleo"}}
+
+		want := []poker.Placing{
+			{Name: "Chris", Position: 1, BuyIn: 100, Prize: 200},
+			{Name: "Cleo", Position: 2, BuyIn: 100, Prize: 120},
+			{Name: "Pepper", Position: 3, BuyIn: 100, Prize: 80},
+			{Name: "Ruth", Position: 4, BuyIn: 100, Prize: 0},
+		}
+		if got := buyIn.Placings(players, result); !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("tied players share the places they take up and the odd chips go to the first", func(t *testing.T) {
+		buyIn := poker.BuyIn{Amount: 25, Payouts: poker.PayoutTable{50, 30, 20}}
+		result := poker.HandResult{Winners: []string{"Chris", "Cleo", "Pepper"}, Eliminated: []string{"Ruth"}}
+
+		got := buyIn.Placings(players, result)
+
+		prizes := []int{got[0].Prize, got[1].Prize, got[2].Prize, got[3].Prize}
+		if !reflect.DeepEqual(prizes, []int{34, 33, 33, 0}) || got[2].Position != 1 || got[3].Position != 4 {
+			t.Errorf("got %+v", got)
+		}
+	})
+
+	t.Run("a buy-in cannot pay more places than there are players", func(t *testing.T) {
+		buyIn := poker.BuyIn{Amount: 100, Payouts: poker.PayoutTable{50, 30, 20}}
+		if buyIn.Validate(2) == nil {
+			t.Error("expected an error paying 3 places with 2 players")
+		}
+	})
+}
+
+func TestBankroll(t *testing.T) {
+
+	t.Run("a game with a buy-in pays out and shows the placings", func(t *testing.T) {
+		store := poker.GetInMemoryStore()
+		out := &bytes.Buffer{}
+		game := &GameSpy{HandWinner: "Cleo"}
+
+		poker.NewPokerCLI(userSends("2", "Chris, Cleo", "", "50"), out, game, dummyGameStore, store, dummyStructures).PlayPoker()
+
+		if !strings.HasSuffix(out.String(), "Place  Player  Prize  Profit\n1      Cleo    100    +50\n2      Chris   0      -50\n") {
+			t.Errorf("expected the placings, got %q", out.String())
+		}
+	})
+
+	t.Run("the stores keep every player's lifetime profit", func(t *testing.T) {
+		store := poker.GetInMemoryStore()
+		game := poker.NewGame(store, dummySpyAlerter, poker.GetInMemoryGameStore(), dummyStructures, poker.RealClock{})
+		players := []string{"Chris", "Cleo", "Pepper"}
+
+		game.Start(3, "", poker.BuyIn{Amount: 100, Payouts: poker.PayoutTable{70, 30}}, dummyStdOut)
+		game.Finish(players, poker.HandResult{Winners: []string{"Pepper"}, Eliminated: []string{"Chris", "Cleo"}})
+
+		for name, want := range map[string]int{"Pepper": 110, "Cleo": -10, "Chris": -100} {
+			if got, err := store.GetProfit(name); err != nil || got != want {
+				t.Errorf("got profit %d (%v) for %s, want %d", got, err, name, want)
+			}
+		}
+		assertScore(t, store, "Pepper", 1)
+
+		standings, _ := store.GetLeagueTable().Standings(poker.RankByCashes)
+		if standings[1].Name != "Pepper" || standings[2].Name != "Chris" || standings[2].Cashes != 0 {
+			t.Errorf("expected the players who cashed first, got %+v", standings)
+		}
+
+		assertNoError(t, store.UndoLastWin())
+		if _, err := store.GetProfit("Pepper"); err == nil {
+			t.Error("expected undo to take back the whole game")
+		}
+	})
+
+	t.Run("the journal replays the bankroll", func(t *testing.T) {
+		path, clean := createTempLeague(t, "")
+		defer clean()
+
+		store := mustOpenJournal(t, path, 100)
+		store.RecordPlacings([]poker.Placing{{Name: "Chris", Position: 1, BuyIn: 20, Prize: 40}, {Name: "Cleo", Position: 2, BuyIn: 20}})
+		store.Close()
+
+		reopened := mustOpenJournal(t, path, 100)
+		defer reopened.Close()
+		if got, _ := reopened.GetProfit("Chris"); got != 20 {
+			t.Errorf("got profit %d, want 20", got)
+		}
+		assertScore(t, reopened, "Chris", 1)
+	})
+
+	t.Run("a player's profile is served as JSON", func(t *testing.T) {
+		store := poker.GetInMemoryStore()
+		store.RecordPlacings([]poker.Placing{{Name: "Chris", Position: 1, BuyIn: 20, Prize: 40}, {Name: "Cleo", Position: 2, BuyIn: 20}})
+		server := mustMakePlayerServer(t, store, dummyGame)
+
+		req, _ := http.NewRequest(http.MethodGet, "/players/Cleo", nil)
+		req.Header.Set("Accept", "application/json")
+		res := serveRequestWith(server, req)
+		assertStatus(t, res, http.StatusOK)
+
+		var got poker.PlayerProfile
+		json.NewDecoder(res.Body).Decode(&got)
+		want := poker.PlayerProfile{Name: "Cleo", Played: 1, Rating: 1484, BuyIns: 20, Profit: -20}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+
+		req, _ = http.NewRequest(http.MethodGet, "/players/Nobody", nil)
+		req.Header.Set("Accept", "application/json")
+		assertStatus(t, serveRequestWith(server, req), http.StatusNotFound)
+	})
+}
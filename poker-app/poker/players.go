@@ -0,0 +1,245 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrUnknownPlayer = errors.New("unknown player")
+	ErrNameTaken     = errors.New("the name is already taken")
+)
+
+/**
+Lookup finds a player by their name or any of their aliases, whatever the case it is typed in,
+so that "chris" and "CJ" both find Chris once CJ is one of his aliases.
+*/
+func (l League) Lookup(name string) *Player {
+	name = strings.TrimSpace(name)
+	for i, p := range l {
+		if strings.EqualFold(p.Name, name) {
+			return &l[i]
+		}
+		for _, alias := range p.Aliases {
+			if strings.EqualFold(alias, name) {
+				return &l[i]
+			}
+		}
+	}
+	return nil
+}
+
+// names maps every name and alias in the league, in lower case, to the name of the player it belongs to.
+func (l League) names() map[string]string {
+	names := map[string]string{}
+	for _, p := range l {
+		names[strings.ToLower(p.Name)] = p.Name
+		for _, alias := range p.Aliases {
+			names[strings.ToLower(alias)] = p.Name
+		}
+	}
+	return names
+}
+
+// resolve puts the names in a result as the league knows them, names it does not know are new players.
+func (l League) resolve(result Result) Result {
+	names := l.names()
+	resolved, _ := result.rename(func(name string) (string, bool) {
+		if known, ok := names[strings.ToLower(name)]; ok {
+			return known, true
+		}
+		return name, true
+	})
+	return resolved
+}
+
+/**
+resolveResults puts the names in the results as the league knows them now, so that the results of
+a player who was renamed or merged into another count for the player they are now.
+The results of players who have been deleted are dropped, a result only they were in goes altogether.
+*/
+func (l League) resolveResults(results []Result) []Result {
+	names := l.names()
+	resolved := make([]Result, 0, len(results))
+	for _, result := range results {
+		r, ok := result.rename(func(name string) (string, bool) {
+			known, ok := names[strings.ToLower(name)]
+			return known, ok
+		})
+		if ok {
+			resolved = append(resolved, r)
+		}
+	}
+	return resolved
+}
+
+// rename returns the result with every name renamed, leaving out the names rename does not keep.
+func (r Result) rename(rename func(name string) (string, bool)) (Result, bool) {
+	names := func(names []string) []string {
+		var renamed []string
+		for _, name := range names {
+			if name, ok := rename(name); ok {
+				renamed = append(renamed, name)
+			}
+		}
+		return renamed
+	}
+
+	renamed := Result{At: r.At, Players: names(r.Players), Winners: names(r.Winners)}
+	if r.Win != "" {
+		var ok bool
+		if renamed.Win, ok = rename(r.Win); !ok {
+			return renamed, false
+		}
+	}
+	for _, placing := range r.Placings {
+		if name, ok := rename(placing.Name); ok {
+			placing.Name = name
+			renamed.Placings = append(renamed.Placings, placing)
+		}
+	}
+	return renamed, renamed.Win != "" || len(renamed.Players) > 0 || len(renamed.Placings) > 0
+}
+
+// The changes to who is in the league, as opposed to the results they play.
+const (
+	registerPlayer = "register"
+	renamePlayer   = "rename"
+	mergePlayers   = "merge"
+	deletePlayer   = "delete"
+)
+
+/**
+playerChange is a change to who is in the league, every store makes it the same way and the journal
+keeps it as it is. To is the new name of a rename, or the player another is merged into.
+*/
+type playerChange struct {
+	Action      string
+	Name        string
+	To          string   `json:",omitempty"`
+	DisplayName string   `json:",omitempty"`
+	Aliases     []string `json:",omitempty"`
+}
+
+// apply returns the league with the change made, the league given is left as it was.
+func (c playerChange) apply(league League) (League, error) {
+	league = append(League{}, league...)
+	player := league.Lookup(c.Name)
+
+	switch c.Action {
+	case registerPlayer:
+		return league.register(c.Name, c.DisplayName, c.Aliases)
+	case renamePlayer:
+		if player == nil {
+			return nil, fmt.Errorf("%w %s", ErrUnknownPlayer, c.Name)
+		}
+		if err := league.available(c.To, player); err != nil {
+			return nil, err
+		}
+		// the old name is kept as an alias, so the results recorded under it still count for the player
+		player.Aliases = withAlias(withoutAlias(player.Aliases, c.To), player.Name)
+		player.Name = c.To
+	case mergePlayers:
+		into := league.Lookup(c.To)
+		if player == nil || into == nil {
+			return nil, fmt.Errorf("%w %s", ErrUnknownPlayer, unknownOf(player, c.Name, c.To))
+		}
+		if player.Name == into.Name {
+			return nil, fmt.Errorf("cannot merge %s into themselves", into.Name)
+		}
+		merged := sumPlayers(*into, *player)
+		if merged.DisplayName == "" {
+			merged.DisplayName = player.DisplayName
+		}
+		merged.Aliases = append(withAlias(into.Aliases, player.Name), player.Aliases...)
+		*into = merged
+		league = league.remove(player.Name)
+	case deletePlayer:
+		if player == nil {
+			return nil, fmt.Errorf("%w %s", ErrUnknownPlayer, c.Name)
+		}
+		league = league.remove(player.Name)
+	default:
+		return nil, fmt.Errorf("unknown change to the players %q", c.Action)
+	}
+	sortLeague(league)
+	return league, nil
+}
+
+/**
+register adds a player with no results yet. Registering a player who is already in the league,
+by their name, gives them the display name and adds the aliases, so that players who came in
+before registration did can be given them.
+*/
+func (l League) register(name, displayName string, aliases []string) (League, error) {
+	player := l.Find(name)
+	if player == nil {
+		if err := l.available(name, nil); err != nil {
+			return nil, err
+		}
+		l = append(l, Player{Name: name})
+		player = &l[len(l)-1]
+	}
+	for _, alias := range aliases {
+		if err := l.available(alias, player); err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(alias, player.Name) {
+			player.Aliases = withAlias(player.Aliases, alias)
+		}
+	}
+	if displayName != "" {
+		player.DisplayName = displayName
+	}
+	sortLeague(l)
+	return l, nil
+}
+
+// available checks a name can be used by the player given, a new one if nil: nobody else has it as a name or an alias.
+func (l League) available(name string, player *Player) error {
+	if strings.TrimSpace(name) == "" || strings.Contains(name, ",") {
+		return fmt.Errorf("%q cannot be a player's name, it needs to be something other than spaces and commas", name)
+	}
+	if other := l.Lookup(name); other != nil && other != player {
+		return fmt.Errorf("%w, %s is %s", ErrNameTaken, name, other.Name)
+	}
+	return nil
+}
+
+func (l League) remove(name string) League {
+	var kept League
+	for _, p := range l {
+		if p.Name != name {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+// withAlias returns a new list of aliases, the ones given along with alias unless it is already one of them.
+func withAlias(aliases []string, alias string) []string {
+	for _, a := range aliases {
+		if strings.EqualFold(a, alias) {
+			return append([]string{}, aliases...)
+		}
+	}
+	return append(append([]string{}, aliases...), alias)
+}
+
+func withoutAlias(aliases []string, name string) []string {
+	var kept []string
+	for _, a := range aliases {
+		if !strings.EqualFold(a, name) {
+			kept = append(kept, a)
+		}
+	}
+	return kept
+}
+
+func unknownOf(player *Player, name, other string) string {
+	if player == nil {
+		return name
+	}
+	return other
+}
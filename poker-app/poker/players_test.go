@@ -0,0 +1,152 @@
+package poker_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ydsxiong/go-playground/poker-app/poker"
+)
+
+func TestPlayerManagement(t *testing.T) {
+	q1 := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	for name, open := range playerStores {
+		t.Run(name, func(t *testing.T) {
+			store, reopen, clean := open(t, poker.NewVirtualClock(q1))
+			defer clean()
+
+			assertNoError(t, store.RegisterPlayer("Chris", "Chris James", "CJ"))
+			if err := store.RegisterPlayer("Christopher", "", "cj"); !errors.Is(err, poker.ErrNameTaken) {
+				t.Errorf("got %v registering an alias that is taken, want %v", err, poker.ErrNameTaken)
+			}
+
+			store.RecordGame([]string{"cj", "Cleo"}, []string{"CJ"})
+			store.RecordWin("Chirs")
+			store.RecordWin("Ruth")
+			assertNoError(t, store.MergePlayers("Chirs", "Chris"))
+			assertNoError(t, store.RenamePlayer("Cleo", "Cleopatra"))
+			assertNoError(t, store.DeletePlayer("Ruth"))
+			assertNoError(t, store.UndoLastWin())
+			assertScore(t, store, "Ruth", 1)
+			assertNoError(t, store.DeletePlayer("Ruth"))
+
+			store = reopen()
+			chris := store.GetLeagueTable().Find("Chris")
+			want := poker.Player{Name: "Chris", DisplayName: "Chris James", Aliases: []string{"CJ", "Chirs"}, Wins: 2, Played: 1, Rating: chris.Rating}
+			if !reflect.DeepEqual(*chris, want) {
+				t.Errorf("got %+v, want %+v", *chris, want)
+			}
+			assertScore(t, store, "Cleo", 10)
+			assertScore(t, store, "Cleopatra", 10)
+			if store.GetLeagueTable().Lookup("Ruth") != nil {
+				t.Errorf("expected Ruth to be gone, got %v", store.GetLeagueTable())
+			}
+			assertSeasonWins(t, store, "2025-Q1", map[string]int{"Chris": 2, "Cleopatra": 0})
+		})
+	}
+
+	t.Run("it reports the players it does not know", func(t *testing.T) {
+		store := poker.GetInMemoryStore(map[string]int{"Chris": 1})
+
+		for _, err := range []error{
+			store.RenamePlayer("Floyd", "Pink"),
+			store.MergePlayers("Floyd", "Chris"),
+			store.MergePlayers("Chris", "Floyd"),
+			store.DeletePlayer("Floyd"),
+		} {
+			if !errors.Is(err, poker.ErrUnknownPlayer) {
+				t.Errorf("got %v, want %v", err, poker.ErrUnknownPlayer)
+			}
+		}
+		if err := store.MergePlayers("Chris", "chris"); err == nil {
+			t.Error("expected an error merging a player into themselves")
+		}
+		if err := store.RegisterPlayer("Chris, Cleo", ""); err == nil {
+			t.Error("expected an error registering a name with a comma in it")
+		}
+	})
+}
+
+func TestPlayersAPI(t *testing.T) {
+	store := poker.GetInMemoryStore(map[string]int{"Chris": 3, "Chirs": 1})
+	server := mustMakePlayerServer(t, store, dummyGame)
+
+	t.Run("it registers players and lists them", func(t *testing.T) {
+		res := serveRequest(server, http.MethodPost, "/players", `{"Name": "Pepper", "DisplayName": "Pepper Potts", "Aliases": ["Pep"]}`)
+		assertStatus(t, res, http.StatusCreated)
+
+		assertStatus(t, serveRequest(server, http.MethodPost, "/players", `{"Name": "Chris", "Aliases": ["CJ"]}`), http.StatusOK)
+		assertStatus(t, serveRequest(server, http.MethodPost, "/players", `{"Name": "Peppa", "Aliases": ["pep"]}`), http.StatusConflict)
+
+		var players []poker.Player
+		json.NewDecoder(serveRequest(server, http.MethodGet, "/players", "").Body).Decode(&players)
+		var names []string
+		for _, p := range players {
+			names = append(names, p.Name)
+		}
+		if !reflect.DeepEqual(names, []string{"Chirs", "Chris", "Pepper"}) {
+			t.Errorf("got players %v", names)
+		}
+
+		res = serveRequest(server, http.MethodPost, "/players/Pep", "")
+		assertStatus(t, res, http.StatusAccepted)
+		assertScore(t, store, "Pepper", 1)
+	})
+
+	t.Run("it merges, renames and deletes players", func(t *testing.T) {
+		res := serveRequest(server, http.MethodPost, "/players/Chirs/merge", `{"Into": "Chris"}`)
+		assertStatus(t, res, http.StatusOK)
+		assertScore(t, store, "Chris", 4)
+
+		assertStatus(t, serveRequest(server, http.MethodPost, "/players/Pepper/rename", `{"Name": "Chris"}`), http.StatusConflict)
+		assertStatus(t, serveRequest(server, http.MethodPost, "/players/Pepper/rename", `{"Name": "Virginia"}`), http.StatusOK)
+		assertScore(t, store, "Virginia", 1)
+
+		assertStatus(t, serveRequest(server, http.MethodDelete, "/players/Virginia", ""), http.StatusNoContent)
+		assertStatus(t, serveRequest(server, http.MethodDelete, "/players/Virginia", ""), http.StatusNotFound)
+		assertStatus(t, serveRequest(server, http.MethodPost, "/players/Floyd/merge", `{"Into": "Chris"}`), http.StatusNotFound)
+	})
+}
+
+func TestCLIUnknownPlayers(t *testing.T) {
+	play := func(store poker.PlayerStore, lines ...string) (*GameSpy, string) {
+		game := &GameSpy{HandWinner: "Chris"}
+		out := &strings.Builder{}
+		poker.NewPokerCLI(userSends(lines...), out, game, dummyGameStore, store, dummyStructures).PlayPoker()
+		return game, out.String()
+	}
+
+	t.Run("a player the league does not know is refused unless confirmed", func(t *testing.T) {
+		store := poker.GetInMemoryStore(map[string]int{"Chris": 3})
+
+		game, out := play(store, "2", "Chris, Clio", "n")
+		if game.StartedWith() != 0 || !strings.HasSuffix(out, fmtNewPlayer("Clio")+"Not playing without Clio, check the spelling or register them first\n") {
+			t.Errorf("expected the game not to be played, got %q", out)
+		}
+
+		game, _ = play(store, "2", "Chris, Clio", "y", "", "")
+		assertPlayedWith(t, game, "Chris", "Clio")
+	})
+
+	t.Run("players are played under the name their alias belongs to", func(t *testing.T) {
+		store := poker.GetInMemoryStore(map[string]int{"Chris": 3, "Cleo": 1})
+		assertNoError(t, store.RegisterPlayer("Chris", "", "CJ"))
+
+		game, _ := play(store, "2", "cj, cleo", "", "")
+		assertPlayedWith(t, game, "Chris", "Cleo")
+
+		game, out := play(store, "2", "CJ, Chris")
+		if game.StartedWith() != 0 || !strings.HasSuffix(out, poker.BadPlayerNamesErrMsg) {
+			t.Errorf("expected Chris entered twice to be refused, got %q", out)
+		}
+	})
+}
+
+func fmtNewPlayer(name string) string {
+	return strings.Replace(poker.NewPlayerPrompt, "%s", name, 1)
+}
@@ -2,12 +2,17 @@ package poker
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"text/template"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -34,15 +39,26 @@ It is a very common mistake to misuse embedding and end up polluting your APIs a
 type PlayerServer struct {
 	Store PlayerStore
 	http.Handler
-	template *template.Template
-	game     Game
+	template    *template.Template
+	game        Game
+	tables      *TableRegistry
+	tournaments *TournamentRegistry
+	games       GameStore
+	structures  *BlindStructures
+	hub         *GameHub
 }
 
+// wsWriteWait is how long a websocket has to take a message before it is given up on.
+const wsWriteWait = 10 * time.Second
+
 type playerServerWS struct {
 	*websocket.Conn
+	writeLock sync.Mutex
 }
 
 const htmlTemplatePath = "game.html"
+const spectateTemplatePath = "spectate.html"
+const tournamentTemplatePath = "tournament.html"
 
 /**
 
@@ -60,15 +76,21 @@ func (ps *PlayerServer) Servehttp(w http.ResponseWriter, r *http.Request) {
 }
 */
 
-func NewPlayerServer(store PlayerStore, game Game) (*PlayerServer, error) {
+func NewPlayerServer(store PlayerStore, game Game, tables *TableRegistry, games GameStore, structures *BlindStructures) (*PlayerServer, error) {
 	ps := new(PlayerServer)
 	ps.game = game
+	ps.tables = tables
+	// a tournament gets its game from the same place as the tables its players sit at
+	ps.tournaments = NewTournamentRegistry(tables.newGame)
+	ps.games = games
+	ps.structures = structures
+	ps.hub = NewGameHub()
 
-	tmpl, err := template.ParseFiles(htmlTemplatePath)
+	tmpl, err := template.ParseFiles(htmlTemplatePath, spectateTemplatePath, tournamentTemplatePath)
 
 	if err != nil {
 		//http.Error(w, fmt.Sprintf("problem loading template %s", err.Error()), http.StatusInternalServerError)
-		return nil, fmt.Errorf("problem opening %s %v", htmlTemplatePath, err)
+		return nil, fmt.Errorf("problem opening templates %v", err)
 	}
 
 	ps.template = tmpl
@@ -76,15 +98,35 @@ func NewPlayerServer(store PlayerStore, game Game) (*PlayerServer, error) {
 
 	router := http.NewServeMux()
 	router.HandleFunc("/league", http.HandlerFunc(ps.handleLeague))
+	router.HandleFunc("/league/import", ps.handleLeagueImport)
+	router.HandleFunc("/league/champions", ps.handleChampions)
+	router.HandleFunc("/players", ps.handleRoster)
 	router.HandleFunc("/players/", ps.handlePlayers)
 	router.HandleFunc("/game", ps.handleGame)
 	router.HandleFunc("/ws", ps.webSocket)
+	router.HandleFunc("/watch", ps.handleWatch)
+	router.HandleFunc("/spectate", func(w http.ResponseWriter, r *http.Request) {
+		spectatorWebSocket(w, r, ps.hub)
+	})
+	router.HandleFunc("/tables", ps.handleTables)
+	router.HandleFunc("/tables/", ps.handleTable)
+	router.HandleFunc("/tournaments", ps.handleTournaments)
+	router.HandleFunc("/tournaments/", ps.handleTournament)
+	router.HandleFunc("/games", ps.handleGames)
+	router.HandleFunc("/games/", ps.handleGames)
+	router.HandleFunc("/structures", ps.handleStructures)
+	router.HandleFunc("/structures/", ps.handleStructures)
 
 	ps.Handler = router
 
 	return ps, nil
 }
 
+// Hub is where the spectators of the game hosted over /ws subscribe to it.
+func (ps *PlayerServer) Hub() *GameHub {
+	return ps.hub
+}
+
 /**
 Remember that every time we get a winner we close the connection, you will need to refresh the page to open the connection again.
 */
@@ -95,8 +137,52 @@ func (ps *PlayerServer) handleGame(w http.ResponseWriter, r *http.Request) {
 	//if err != nil {
 	//http.Error(w, fmt.Sprintf("problem loading template %s", err.Error()), http.StatusInternalServerError)
 	//}
-	ps.template.Execute(w, nil)
+	structures, _ := json.Marshal(ps.structures.List())
+	ps.template.Execute(w, gamePage{string(structures)})
+
+}
+
+// handleWatch serves the spectator page for a wall display, /watch?table={id} watches a table instead of the main game.
+func (ps *PlayerServer) handleWatch(w http.ResponseWriter, r *http.Request) {
+	ps.template.ExecuteTemplate(w, spectateTemplatePath, nil)
+}
+
+// gamePage hands the blind structures to the page so it can show the levels coming up.
+type gamePage struct {
+	StructuresJSON string
+}
 
+/**
+handleStructures lists the blind structures on GET /structures, returns one on GET /structures/{name}
+(the standard structure is shown for a full table) and adds or replaces one on POST /structures.
+*/
+func (ps *PlayerServer) handleStructures(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/structures"), "/")
+
+	switch {
+	case r.Method == http.MethodGet && name == "":
+		writeJSON(w, http.StatusOK, ps.structures.List())
+	case r.Method == http.MethodGet:
+		structure, err := ps.structures.Get(name, MaxPlayersPerHand)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, structure)
+	case r.Method == http.MethodPost && name == "":
+		var structure BlindStructure
+		if err := json.NewDecoder(r.Body).Decode(&structure); err != nil {
+			http.Error(w, fmt.Sprintf("problem parsing blind structure %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := ps.structures.Add(structure); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, http.StatusCreated, structure)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
 }
 
 /**
@@ -116,6 +202,17 @@ we will use WebSockets to send that data to our server for it to record.
 */
 func (ps *PlayerServer) webSocket(w http.ResponseWriter, r *http.Request) {
 	ws := NewWwebSocket(w, r)
+	if ws.Conn == nil {
+		return
+	}
+	defer ws.Close()
+
+	// whoever connects first hosts the game, everybody else can only watch it
+	if err := ps.hub.Host(); err != nil {
+		ws.Write([]byte(err.Error()))
+		return
+	}
+	defer ps.hub.Release()
 
 	playersMsg := ws.WaitForMsg()
 	number, err := strconv.Atoi(playersMsg)
@@ -123,30 +220,307 @@ func (ps *PlayerServer) webSocket(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("invalid vaue for number (%s) of players %s", playersMsg, err.Error()), http.StatusInternalServerError)
 		return
 	}
-	ps.game.Start(number, ws) //ioutil.Discard)
 
-	winnerMsg := ws.WaitForMsg()
-	ps.game.Finish(winnerMsg)
+	players := extractPlayers(ws.WaitForMsg())
+	if len(players) != number {
+		ws.Write([]byte(BadPlayerNamesErrMsg))
+		return
+	}
+	structure := ws.WaitForMsg()
+	buyIn, err := ParseBuyIn(ws.WaitForMsg())
+	if err != nil {
+		ws.Write([]byte(fmt.Sprintf("problem with the buy-in, %v", err)))
+		return
+	}
+	ws.playGame(ps.game, players, structure, buyIn, ps.hub)
+}
+
+/**
+spectatorWebSocket sends the spectator the state of the game followed by its events as JSON,
+anything the spectator sends is ignored as only the host controls the game.
+*/
+func spectatorWebSocket(w http.ResponseWriter, r *http.Request, hub *GameHub) {
+	ws := NewWwebSocket(w, r)
+	if ws.Conn == nil {
+		return
+	}
+	defer ws.Close()
+
+	unsubscribe := hub.Subscribe(ws)
+	defer unsubscribe()
+
+	for {
+		if _, err := ws.readMsg(); err != nil {
+			return
+		}
+	}
 }
 
+/**
+handleLeague ranks by wins unless asked for ?rank=rating, every entry carries
+the games played, the win percentage and the rating either way.
+?season=2026-Q3 ranks the results of that season alone, ?season=current the season going on now,
+and the all-time league is the default.
+Asked for ?format=csv, or with an Accept header of text/csv, it exports the players' records
+as CSV in the order of the standings, ready to be imported into another league.
+*/
 func (ps *PlayerServer) handleLeague(w http.ResponseWriter, r *http.Request) {
+	league, err := SeasonLeague(ps.Store, r.URL.Query().Get("season"), time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	standings, err := league.Standings(r.URL.Query().Get("rank"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if wantsCSV(r) {
+		export := make(League, 0, len(standings))
+		for _, standing := range standings {
+			export = append(export, *league.Find(standing.Name))
+		}
+		w.Header().Set("content-type", "text/csv")
+		w.Header().Set("content-disposition", `attachment; filename="league.csv"`)
+		WriteLeagueCSV(w, export)
+		return
+	}
+
 	w.Header().Set("content-type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
-	json.NewEncoder(w).Encode(ps.Store.GetLeagueTable())
+	json.NewEncoder(w).Encode(standings)
+}
+
+// handleChampions is the archive of past champions, one per season that is over, the latest first.
+func (ps *PlayerServer) handleChampions(w http.ResponseWriter, r *http.Request) {
+	champions := Champions(ps.Store.GetResults(), time.Now())
+	if champions == nil {
+		champions = []Champion{}
+	}
+	writeJSON(w, http.StatusOK, champions)
+}
+
+func wantsCSV(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return strings.EqualFold(format, CSVFormat)
+	}
+	return strings.Contains(r.Header.Get("Accept"), "text/csv")
 }
 
+/**
+handleLeagueImport merges a league posted to /league/import into this one, as CSV when asked for
+?format=csv or sent as text/csv and as JSON otherwise. ?strategy= is how the players in both leagues
+are merged, sum by default. Nothing is changed unless asked for ?apply=true, without it the
+MergeReport returned says what the import would do, the players it would add and the conflicts.
+*/
+func (ps *PlayerServer) handleLeagueImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = JSONFormat
+		if strings.HasPrefix(r.Header.Get("content-type"), "text/csv") {
+			format = CSVFormat
+		}
+	}
+	strategy := query.Get("strategy")
+	if strategy == "" {
+		strategy = MergeSum
+	}
+
+	imported, err := ReadLeague(r.Body, format)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if apply, _ := strconv.ParseBool(query.Get("apply")); !apply {
+		_, report, err := MergeLeagues(ps.Store.GetLeagueTable(), imported, strategy)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		report.DryRun = true
+		writeJSON(w, http.StatusOK, report)
+		return
+	}
+
+	report, err := ps.Store.ImportLeague(imported, strategy)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+func (ps *PlayerServer) handleGames(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/games"), "/")
+	if id == "" {
+		games, err := ps.games.GetGames()
+		if err != nil {
+			http.Error(w, fmt.Sprintf("problem loading games %v", err), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, games)
+		return
+	}
+
+	gameID, err := strconv.Atoi(id)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid game id %s", id), http.StatusBadRequest)
+		return
+	}
+	game, err := ps.games.GetGame(gameID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, game)
+}
+
+/**
+handlePlayers serves /players/{name}: GET for the score, POST to record a win and DELETE to take the player
+out of the league. POST /players/{name}/rename with a PlayerRename renames them, their old name staying on as
+an alias, and POST /players/{name}/merge with a PlayerMerge adds their record to another player's.
+*/
 func (ps *PlayerServer) handlePlayers(w http.ResponseWriter, r *http.Request) {
 	player := strings.TrimPrefix(r.URL.Path, "/players/")
+	if i := strings.LastIndex(player, "/"); i >= 0 && r.Method == http.MethodPost {
+		ps.changePlayer(w, r, player[:i], player[i+1:])
+		return
+	}
 	switch r.Method {
 	case http.MethodGet:
 		ps.showScore(w, r, player)
 	case http.MethodPost:
 		ps.processWin(w, r, player)
+	case http.MethodDelete:
+		if err := ps.Store.DeletePlayer(player); err != nil {
+			playerError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// PlayerRegistration is what POST /players takes to register a player.
+type PlayerRegistration struct {
+	Name        string
+	DisplayName string   `json:",omitempty"`
+	Aliases     []string `json:",omitempty"`
+}
+
+// PlayerRename is what POST /players/{name}/rename takes, the player's new name.
+type PlayerRename struct {
+	Name string
+}
+
+// PlayerMerge is what POST /players/{name}/merge takes, the player to merge them into.
+type PlayerMerge struct {
+	Into string
+}
+
+/**
+handleRoster lists everybody in the league on GET /players, by name, and registers a player on POST /players.
+Registering a player already in the league updates their display name and adds to their aliases.
+*/
+func (ps *PlayerServer) handleRoster(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		league := ps.Store.GetLeagueTable()
+		sort.Slice(league, func(i, j int) bool { return league[i].Name < league[j].Name })
+		writeJSON(w, http.StatusOK, league)
+	case http.MethodPost:
+		var registration PlayerRegistration
+		if err := json.NewDecoder(r.Body).Decode(&registration); err != nil {
+			http.Error(w, fmt.Sprintf("problem parsing player registration %v", err), http.StatusBadRequest)
+			return
+		}
+		status := http.StatusOK
+		if ps.Store.GetLeagueTable().Find(registration.Name) == nil {
+			status = http.StatusCreated
+		}
+		if err := ps.Store.RegisterPlayer(registration.Name, registration.DisplayName, registration.Aliases...); err != nil {
+			playerError(w, err)
+			return
+		}
+		writeJSON(w, status, ps.Store.GetLeagueTable().Find(registration.Name))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (ps *PlayerServer) changePlayer(w http.ResponseWriter, r *http.Request, player, change string) {
+	var err error
+	var name string
+	switch change {
+	case "rename":
+		var rename PlayerRename
+		if err := json.NewDecoder(r.Body).Decode(&rename); err != nil {
+			http.Error(w, fmt.Sprintf("problem parsing rename %v", err), http.StatusBadRequest)
+			return
+		}
+		name, err = rename.Name, ps.Store.RenamePlayer(player, rename.Name)
+	case "merge":
+		var merge PlayerMerge
+		if err := json.NewDecoder(r.Body).Decode(&merge); err != nil {
+			http.Error(w, fmt.Sprintf("problem parsing merge %v", err), http.StatusBadRequest)
+			return
+		}
+		name, err = merge.Into, ps.Store.MergePlayers(player, merge.Into)
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		playerError(w, err)
+		return
 	}
+	writeJSON(w, http.StatusOK, ps.Store.GetLeagueTable().Lookup(name))
+}
+
+// playerError answers a change to the players that could not be made with why.
+func playerError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, ErrUnknownPlayer):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrNameTaken):
+		status = http.StatusConflict
+	}
+	http.Error(w, err.Error(), status)
+}
+
+/**
+PlayerProfile is what GET /players/{name} returns when asked for JSON rather than just the score,
+the player's record along with their lifetime bankroll.
+*/
+type PlayerProfile struct {
+	Name        string
+	DisplayName string   `json:",omitempty"`
+	Aliases     []string `json:",omitempty"`
+	Wins        int
+	Played      int
+	Rating      float64
+	Cashes      int
+	BuyIns      int
+	Winnings    int
+	Profit      int
 }
 
 func (ps *PlayerServer) showScore(w http.ResponseWriter, r *http.Request, player string) {
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		ps.showProfile(w, player)
+		return
+	}
 	//fmt.Fprintf(w, "20") // ok
 	// fmt.Println(w, "20") // no
 	// w.Write([]byte("20")) // ok
@@ -159,12 +533,34 @@ func (ps *PlayerServer) showScore(w http.ResponseWriter, r *http.Request, player
 	fmt.Fprint(w, score)
 }
 
+func (ps *PlayerServer) showProfile(w http.ResponseWriter, name string) {
+	profit, err := ps.Store.GetProfit(name)
+	player := ps.Store.GetLeagueTable().Lookup(name)
+	if err != nil || player == nil {
+		http.Error(w, fmt.Sprintf("unknown player %s", name), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, PlayerProfile{
+		Name:        player.Name,
+		DisplayName: player.DisplayName,
+		Aliases:     player.Aliases,
+		Wins:        player.Wins,
+		Played:      player.GamesPlayed(),
+		Rating:      player.CurrentRating(),
+		Cashes:      player.Cashes,
+		BuyIns:      player.BuyIns,
+		Winnings:    player.Winnings,
+		Profit:      profit,
+	})
+}
+
 func (ps *PlayerServer) processWin(w http.ResponseWriter, r *http.Request, player string) {
 	ps.Store.RecordWin(player)
 	w.WriteHeader(http.StatusAccepted)
 	ps.showScore(w, r, player)
 }
 
+// NewWwebSocket upgrades the connection to a websocket, the Conn is nil if that failed and the request has been answered already.
 func NewWwebSocket(w http.ResponseWriter, r *http.Request) *playerServerWS {
 	conn, err := wsUpgrader.Upgrade(w, r, nil)
 	if err != nil {
@@ -172,18 +568,89 @@ func NewWwebSocket(w http.ResponseWriter, r *http.Request) *playerServerWS {
 		log.Printf("problem upgrading connection to WebSockets %v\n", err)
 	}
 
-	return &playerServerWS{conn}
+	return &playerServerWS{Conn: conn}
 }
 
 func (ws *playerServerWS) WaitForMsg() string {
+	msg, _ := ws.readMsg()
+	return msg
+}
+
+func (ws *playerServerWS) readMsg() (string, error) {
 	_, msg, err := ws.Conn.ReadMessage()
 	if err != nil {
 		log.Printf("error reading from websocket %v\n", err)
 	}
-	return string(msg)
+	return string(msg), err
 }
 
+/**
+playGame plays a hand over the websocket, the game's blind alerts are cancelled once it is over,
+whether it finished, was aborted or the connection went away. The start, the blinds and the winner
+are broadcast to the spectators of the hub as well, a game that fails to start is never announced to them.
+*/
+func (ws *playerServerWS) playGame(game Game, players []string, structure string, buyIn BuyIn, hub *GameHub) {
+	alerts, err := game.Start(len(players), structure, buyIn, io.MultiWriter(hub, ws))
+	if err != nil {
+		game.Abort()
+		ws.Write([]byte(fmt.Sprintf("problem starting the game, %v", err)))
+		return
+	}
+	defer alerts.Cancel()
+	hub.Start(players, structure)
+
+	control := &gameControl{alerts: alerts}
+	result, err := game.PlayHand(players, nil, ActorFunc(func(view TableView) Action {
+		return ws.askForAction(view, control)
+	}), ws)
+	if err == ErrHandAborted {
+		game.Abort()
+		ws.Write([]byte(GameAbortedMsg))
+		return
+	}
+	if err != nil {
+		game.Abort()
+		ws.Write([]byte(fmt.Sprintf("problem playing the hand, %v", err)))
+		return
+	}
+	placings := game.Finish(players, result)
+	if buyIn.Amount > 0 {
+		printPlacings(ws, placings)
+	}
+	hub.Finish(result.Winners)
+}
+
+/**
+askForAction sends the acting player their view of the table and waits for the browser to reply with an action.
+A closed connection aborts the hand, nobody is left to play it.
+*/
+func (ws *playerServerWS) askForAction(view TableView, control *gameControl) Action {
+	for {
+		ws.Write([]byte(view.String()))
+		msg, err := ws.readMsg()
+		if err != nil {
+			return Action{Kind: Abort}
+		}
+		if reply, ok := control.command(msg); ok {
+			ws.Write([]byte(reply))
+			continue
+		}
+		action, err := ParseAction(msg)
+		if err == nil {
+			return action
+		}
+		ws.Write([]byte(err.Error()))
+	}
+}
+
+/**
+Blind alerts are written from their own timers while the hand is being played,
+and a websocket connection supports only one concurrent writer.
+*/
 func (ws *playerServerWS) Write(p []byte) (n int, err error) {
+	ws.writeLock.Lock()
+	defer ws.writeLock.Unlock()
+	ws.SetWriteDeadline(time.Now().Add(wsWriteWait))
 	err = ws.WriteMessage(1, p)
 
 	if err != nil {
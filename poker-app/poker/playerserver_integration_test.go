@@ -15,7 +15,7 @@ func TestRecordingWinsAndRetrievingThem(t *testing.T) {
 		t.Fatalf("could not create temp file %v", err)
 	}
 	defer cleanDatabase()
-	store, e := poker.NewFileSystemPlayerStore(database)
+	store, e := poker.NewFileSystemPlayerStore(database, poker.RealClock{})
 	if e != nil {
 		t.Fatalf("Problem with opening file database, %v", e)
 	}
@@ -41,15 +41,15 @@ func TestRecordingWinsAndRetrievingThem(t *testing.T) {
 		res, _ := createandservereqres(server)
 		got := assertresponsecode(t, res)
 
-		wanted := poker.League{
-			{"pepper", 3},
+		wanted := []poker.Standing{
+			{Name: "pepper", Wins: 3, Played: 3, WinPercentage: 100, Rating: poker.DefaultRating},
 		}
-		assertleague(t, got, wanted)
+		assertstandings(t, got, wanted)
 	})
 }
 
 func mustMakePlayerServer(t *testing.T, store poker.PlayerStore, game poker.Game) *poker.PlayerServer {
-	server, err := poker.NewPlayerServer(store, game)
+	server, err := poker.NewPlayerServer(store, game, poker.NewTableRegistry(func() poker.Game { return game }), dummyGameStore, dummyStructures)
 	if err != nil {
 		t.Fatal("problem creating player server", err)
 	}
@@ -28,7 +28,7 @@ func TestGETPlayers(t *testing.T) {
 		t.Fatalf("could not create temp file %v", err)
 	}
 	defer cleanDatabase()
-	store, e := poker.NewFileSystemPlayerStore(database)
+	store, e := poker.NewFileSystemPlayerStore(database, poker.RealClock{})
 	if e != nil {
 		t.Fatalf("Problem with opening file database, %v", e)
 	}
@@ -119,11 +119,11 @@ func TestGETPlayers(t *testing.T) {
 	// 	}
 	// })
 
-	t.Run("start a game with 3 players and declare Ruth the winner", func(t *testing.T) {
+	t.Run("start a game with 3 players and Ruth wins the hand", func(t *testing.T) {
 		wantedBlindAlert := "Blind is 100"
-		game := &GameSpy{BlindAlert: []byte(wantedBlindAlert)}
-
 		winner := "Ruth"
+		game := &GameSpy{BlindAlert: []byte(wantedBlindAlert), HandWinner: winner}
+
 		server := httptest.NewServer(mustMakePlayerServer(t, store, game))
 		ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
 
@@ -131,7 +131,9 @@ func TestGETPlayers(t *testing.T) {
 		defer ws.Close()
 
 		writeWSMessage(t, ws, "3")
-		writeWSMessage(t, ws, winner)
+		writeWSMessage(t, ws, "Ruth, Chris, Cleo")
+		writeWSMessage(t, ws, "")
+		writeWSMessage(t, ws, "")
 
 		assertGameStartedWith(t, game, 3)
 		assertFinishCalledWith(t, game, winner)
@@ -140,6 +142,135 @@ func TestGETPlayers(t *testing.T) {
 
 	})
 
+	t.Run("spectators watch the game the host plays and cannot start another", func(t *testing.T) {
+		game := &GameSpy{BlindAlert: []byte("Blind is now 100\n"), HandWinner: "Ruth"}
+		server := httptest.NewServer(mustMakePlayerServer(t, store, game))
+		defer server.Close()
+		baseURL := "ws" + strings.TrimPrefix(server.URL, "http")
+
+		spectator := mustDialWS(t, baseURL+"/spectate")
+		defer spectator.Close()
+		assertEventReceived(t, spectator, poker.GameEvent{Type: poker.GameStateEvent})
+
+		host := mustDialWS(t, baseURL+"/ws")
+		defer host.Close()
+		writeWSMessage(t, host, "2")
+
+		intruder := mustDialWS(t, baseURL+"/ws")
+		defer intruder.Close()
+		within(t, tenMS*10, func() { assertMessageReceived(t, intruder, "a game is already being played, watch it instead") })
+
+		writeWSMessage(t, host, "Ruth, Chris")
+		writeWSMessage(t, host, "")
+		writeWSMessage(t, host, "")
+
+		assertEventReceived(t, spectator, poker.GameEvent{Type: poker.GameStartedEvent, Players: []string{"Ruth", "Chris"}, Structure: poker.StandardBlindStructure})
+		assertEventReceived(t, spectator, poker.GameEvent{Type: poker.BlindEvent, Blind: "Blind is now 100"})
+		assertEventReceived(t, spectator, poker.GameEvent{Type: poker.WinnerEvent, Winners: []string{"Ruth"}})
+	})
+
+	t.Run("spectators never hear of a game that fails to start", func(t *testing.T) {
+		game := poker.NewGame(poker.GetInMemoryStore(), dummySpyAlerter, dummyGameStore, dummyStructures, poker.RealClock{})
+		server := httptest.NewServer(mustMakePlayerServer(t, store, game))
+		defer server.Close()
+		baseURL := "ws" + strings.TrimPrefix(server.URL, "http")
+
+		spectator := mustDialWS(t, baseURL+"/spectate")
+		defer spectator.Close()
+		assertEventReceived(t, spectator, poker.GameEvent{Type: poker.GameStateEvent})
+
+		host := mustDialWS(t, baseURL+"/ws")
+		defer host.Close()
+		writeWSMessage(t, host, "2")
+		writeWSMessage(t, host, "Ruth, Chris")
+		writeWSMessage(t, host, "glacial")
+		writeWSMessage(t, host, "")
+		within(t, tenMS*10, func() { assertMessageReceived(t, host, "problem starting the game, unknown blind structure glacial") })
+
+		spectator.SetReadDeadline(time.Now().Add(tenMS * 5))
+		if _, msg, err := spectator.ReadMessage(); err == nil {
+			t.Errorf("expected the spectator to hear nothing, got %s", msg)
+		}
+	})
+
+	t.Run("a websocket path asked for without upgrading the connection is turned away", func(t *testing.T) {
+		game := poker.NewGame(poker.GetInMemoryStore(), dummySpyAlerter, dummyGameStore, dummyStructures, poker.RealClock{})
+		server := mustMakePlayerServer(t, store, game)
+		assertStatus(t, serveRequest(server, http.MethodPost, "/tables", `{"ID": "red", "Seats": 2}`), http.StatusCreated)
+		assertStatus(t, serveRequest(server, http.MethodPost, "/tournaments", `{"ID": "sunday", "Players": ["Chris", "Cleo"]}`), http.StatusCreated)
+
+		for _, path := range []string{"/ws", "/spectate", "/tables/red/ws", "/tables/red/spectate", "/tournaments/sunday/spectate", "/tournaments/sunday/tables/final/ws"} {
+			assertStatus(t, serveRequest(server, http.MethodGet, path, ""), http.StatusBadRequest)
+		}
+	})
+
+	t.Run("closing the websocket cancels the blind alerts of the game", func(t *testing.T) {
+		blindAlerter := &SpyBlindAlerter{}
+		games := poker.GetInMemoryGameStore()
+		game := poker.NewGame(poker.GetInMemoryStore(), blindAlerter, games, dummyStructures, poker.RealClock{})
+
+		server := httptest.NewServer(mustMakePlayerServer(t, store, game))
+		defer server.Close()
+		ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+
+		writeWSMessage(t, ws, "2")
+		writeWSMessage(t, ws, "Ruth, Chris")
+		writeWSMessage(t, ws, "")
+		writeWSMessage(t, ws, "")
+		ws.Close()
+
+		cancelled := retryUntil(500*time.Millisecond, func() bool {
+			return blindAlerter.firstCancelled()
+		})
+		if !cancelled {
+			t.Error("expected the blind alerts to be cancelled once the websocket closed")
+		}
+		if record, _ := games.GetGame(1); !record.Aborted {
+			t.Errorf("expected the game to be recorded as aborted, got %+v", record)
+		}
+	})
+
+}
+
+func TestGETGames(t *testing.T) {
+	games := poker.GetInMemoryGameStore()
+	started := time.Date(2026, time.October, 1, 19, 0, 0, 0, time.UTC)
+	id, _ := games.StartGame(started, 4)
+	games.FinishGame(id, started.Add(time.Hour), 3, "Pepper")
+	aborted, _ := games.StartGame(started.Add(2*time.Hour), 2)
+	games.AbortGame(aborted, started.Add(150*time.Minute), 1)
+
+	store := poker.GetInMemoryStore()
+	server, _ := poker.NewPlayerServer(store, dummyGame, poker.NewTableRegistry(func() poker.Game { return dummyGame }), games, dummyStructures)
+
+	t.Run("it returns all the games as JSON", func(t *testing.T) {
+		res := serveRequest(server, http.MethodGet, "/games", "")
+		assertStatus(t, res, http.StatusOK)
+
+		var got []poker.GameRecord
+		if err := json.NewDecoder(res.Body).Decode(&got); err != nil {
+			t.Fatalf("Unable to parse response from server %q, '%v'", res.Body, err)
+		}
+		if len(got) != 2 || got[0].Winner != "Pepper" || got[0].Aborted || got[1].Winner != "" || !got[1].Aborted {
+			t.Errorf("expected one game won by Pepper and one aborted, got %+v", got)
+		}
+	})
+
+	t.Run("it returns a single game", func(t *testing.T) {
+		res := serveRequest(server, http.MethodGet, fmt.Sprintf("/games/%d", id), "")
+		assertStatus(t, res, http.StatusOK)
+
+		var got poker.GameRecord
+		json.NewDecoder(res.Body).Decode(&got)
+		if got.ID != id || got.Players != 4 || got.BlindLevels != 3 {
+			t.Errorf("got %+v", got)
+		}
+	})
+
+	t.Run("it returns 404 for an unknown game and 400 for a bad id", func(t *testing.T) {
+		assertStatus(t, serveRequest(server, http.MethodGet, "/games/42", ""), http.StatusNotFound)
+		assertStatus(t, serveRequest(server, http.MethodGet, "/games/latest", ""), http.StatusBadRequest)
+	})
 }
 
 func within(t *testing.T, waitFor time.Duration, assert func()) {
@@ -168,6 +299,18 @@ func assertMessageReceived(t *testing.T, ws *websocket.Conn, wantedBlindAlert st
 	}
 }
 
+func assertEventReceived(t *testing.T, ws *websocket.Conn, want poker.GameEvent) {
+	t.Helper()
+	ws.SetReadDeadline(time.Now().Add(time.Second))
+	var got poker.GameEvent
+	if err := ws.ReadJSON(&got); err != nil {
+		t.Fatalf("could not read event %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got event %+v, want %+v", got, want)
+	}
+}
+
 func writeWSMessage(t *testing.T, conn *websocket.Conn, message string) {
 	t.Helper()
 	if err := conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
@@ -196,9 +339,9 @@ func createandservegamereqres(server *poker.PlayerServer) (*httptest.ResponseRec
 	return res, req
 }
 
-func assertresponsecode(t *testing.T, res *httptest.ResponseRecorder) poker.League {
+func assertresponsecode(t *testing.T, res *httptest.ResponseRecorder) []poker.Standing {
 	t.Helper()
-	var got poker.League
+	var got []poker.Standing
 
 	err := json.NewDecoder(res.Body).Decode(&got)
 	if err != nil {
@@ -216,6 +359,13 @@ func assertresponsecode(t *testing.T, res *httptest.ResponseRecorder) poker.Leag
 	return got
 }
 
+func assertstandings(t *testing.T, got, wanted []poker.Standing) {
+	t.Helper()
+	if !reflect.DeepEqual(got, wanted) {
+		t.Errorf("got %v want %v", got, wanted)
+	}
+}
+
 func assertleague(t *testing.T, got, wanted poker.League) {
 	t.Helper()
 	if !reflect.DeepEqual(got, wanted) { //if !playerstore.AreCollectionsEqual(got, wanted) {
@@ -230,14 +380,15 @@ func areSameCollections(a, b poker.League) bool {
 }
 
 func intersection(a, b poker.League) (c poker.League) {
-	m := make(map[poker.Player]bool)
+	// a player has a list of aliases, so is keyed as it prints
+	m := make(map[string]bool)
 
 	for _, item := range a {
-		m[item] = true
+		m[fmt.Sprint(item)] = true
 	}
 
 	for _, item := range b {
-		if _, ok := m[item]; ok {
+		if _, ok := m[fmt.Sprint(item)]; ok {
 			c = append(c, item)
 		}
 	}
@@ -0,0 +1,163 @@
+package poker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const StartTableGameMsg = "start"
+
+type openTableRequest struct {
+	ID    string
+	Seats int
+}
+
+/**
+handleTables lists the open tables on GET and opens a new one on POST, e.g.
+
+	POST /tables {"ID": "red", "Seats": 6}
+*/
+func (ps *PlayerServer) handleTables(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, ps.tables.List())
+	case http.MethodPost:
+		var req openTableRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("problem parsing table %v", err), http.StatusBadRequest)
+			return
+		}
+		table, err := ps.tables.Open(req.ID, req.Seats)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		writeJSON(w, http.StatusCreated, table.Info())
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+/**
+handleTable serves everything under a single table:
+
+	GET    /tables/{id}                 the table and who is seated at it
+	DELETE /tables/{id}                 closes the table
+	POST   /tables/{id}/players/{name}  takes a seat
+	DELETE /tables/{id}/players/{name}  leaves the table
+	       /tables/{id}/ws              plays a game with the seated players
+	       /tables/{id}/spectate        watches the game played at the table
+*/
+func (ps *PlayerServer) handleTable(w http.ResponseWriter, r *http.Request) {
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/tables/"), "/", 3)
+
+	table, ok := ps.tables.Get(parts[0])
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown table %s", parts[0]), http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case len(parts) == 1:
+		ps.handleTableInfo(w, r, table)
+	case len(parts) == 2 && parts[1] == "ws":
+		ps.tableWebSocket(w, r, table)
+	case len(parts) == 2 && parts[1] == "spectate":
+		spectatorWebSocket(w, r, table.Hub())
+	case len(parts) == 3 && parts[1] == "players":
+		ps.handleSeat(w, r, table, parts[2])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (ps *PlayerServer) handleTableInfo(w http.ResponseWriter, r *http.Request, table *Table) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, table.Info())
+	case http.MethodDelete:
+		if err := ps.tables.Close(table.ID()); err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (ps *PlayerServer) handleSeat(w http.ResponseWriter, r *http.Request, table *Table, player string) {
+	var err error
+	switch r.Method {
+	case http.MethodPost:
+		err = table.Join(player)
+	case http.MethodDelete:
+		err = table.Leave(player)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusOK, table.Info())
+}
+
+/**
+tableWebSocket waits for the start message, e.g. "start", "start turbo" or "start turbo 100 50/30/20"
+to play for a buy-in, and then plays a game with whoever is seated, using the table's own game
+so its blinds run independently of every other table.
+*/
+func (ps *PlayerServer) tableWebSocket(w http.ResponseWriter, r *http.Request, table *Table) {
+	ws := NewWwebSocket(w, r)
+	if ws.Conn == nil {
+		return
+	}
+	defer ws.Close()
+
+	var structure string
+	var buyIn BuyIn
+	for {
+		msg, err := ws.readMsg()
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(msg)
+		if len(fields) > 0 && len(fields) <= 4 && fields[0] == StartTableGameMsg {
+			var terms []string
+			if len(fields) > 1 {
+				structure, terms = fields[1], fields[2:]
+			}
+			if buyIn, err = ParseBuyIn(strings.Join(terms, " ")); err == nil {
+				break
+			}
+			ws.Write([]byte(fmt.Sprintf("problem with the buy-in, %v", err)))
+			continue
+		}
+		ws.Write([]byte(fmt.Sprintf("send %q, optionally followed by a blind structure and a buy-in, once everybody is seated", StartTableGameMsg)))
+	}
+
+	players, err := table.Sit()
+	if err != nil {
+		ws.Write([]byte(err.Error()))
+		return
+	}
+	defer table.Stand()
+
+	if err := table.Hub().Host(); err != nil {
+		ws.Write([]byte(err.Error()))
+		return
+	}
+	defer table.Hub().Release()
+
+	ws.playGame(table.Game(), players, structure, buyIn, table.Hub())
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,119 @@
+package poker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type openTournamentRequest struct {
+	ID        string
+	Players   []string
+	Seats     int
+	Stack     int
+	Structure string
+	BuyIn     string
+}
+
+/**
+handleTournaments lists the tournaments on GET and starts a new one on POST, e.g.
+
+	POST /tournaments {"ID": "sunday", "Players": ["Chris", "Cleo", ...], "Seats": 6, "Stack": 5000, "Structure": "turbo", "BuyIn": "100 50/30/20"}
+*/
+func (ps *PlayerServer) handleTournaments(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, ps.tournaments.List())
+	case http.MethodPost:
+		var req openTournamentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("problem parsing tournament %v", err), http.StatusBadRequest)
+			return
+		}
+		buyIn, err := ParseBuyIn(req.BuyIn)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("problem with the buy-in, %v", err), http.StatusBadRequest)
+			return
+		}
+		tournament, err := ps.tournaments.Open(req.ID, req.Players, req.Seats, req.Stack, req.Structure, buyIn)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		writeJSON(w, http.StatusCreated, tournament.Info())
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+/**
+handleTournament serves everything under a single tournament:
+
+	GET    /tournaments/{id}                  the tables, who is seated where and the moves so far
+	DELETE /tournaments/{id}                  calls the tournament off
+	       /tournaments/{id}/view             shows the seat assignments and table moves as they happen
+	       /tournaments/{id}/spectate         follows the blinds and the winner
+	       /tournaments/{id}/tables/{table}/ws  deals a hand at the table
+*/
+func (ps *PlayerServer) handleTournament(w http.ResponseWriter, r *http.Request) {
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/tournaments/"), "/", 4)
+
+	tournament, ok := ps.tournaments.Get(parts[0])
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown tournament %s", parts[0]), http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case len(parts) == 1:
+		ps.handleTournamentInfo(w, r, tournament)
+	case len(parts) == 2 && parts[1] == "view":
+		ps.template.ExecuteTemplate(w, tournamentTemplatePath, nil)
+	case len(parts) == 2 && parts[1] == "spectate":
+		spectatorWebSocket(w, r, tournament.Hub())
+	case len(parts) == 4 && parts[1] == "tables" && parts[3] == "ws":
+		tournamentTableWebSocket(w, r, tournament, parts[2])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (ps *PlayerServer) handleTournamentInfo(w http.ResponseWriter, r *http.Request, tournament *Tournament) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, tournament.Info())
+	case http.MethodDelete:
+		if err := ps.tournaments.Close(tournament.ID()); err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+/**
+tournamentTableWebSocket deals one hand at a table of the tournament, the players act over the websocket
+just like in a single game. Pausing stops the blinds of the whole tournament, as every table plays to the same clock.
+*/
+func tournamentTableWebSocket(w http.ResponseWriter, r *http.Request, tournament *Tournament, table string) {
+	ws := NewWwebSocket(w, r)
+	if ws.Conn == nil {
+		return
+	}
+	defer ws.Close()
+
+	control := &gameControl{alerts: tournament.alerts}
+	_, err := tournament.PlayTable(table, ActorFunc(func(view TableView) Action {
+		return ws.askForAction(view, control)
+	}), ws)
+	if err == ErrHandAborted {
+		ws.Write([]byte(GameAbortedMsg))
+		return
+	}
+	if err != nil {
+		ws.Write([]byte(err.Error()))
+	}
+}
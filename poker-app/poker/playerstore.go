@@ -1,14 +1,40 @@
 package poker
 
+import (
+	"errors"
+	"fmt"
+)
+
+// how many results back a store can undo
+const maxUndo = 20
+
+var ErrNothingToUndo = errors.New("there is no recorded win to undo")
+
+/**
+Player is a player's record in the league. DisplayName is how they like to be shown, Name being
+what they are known by in the league, and they can be found by any of their Aliases as well.
+*/
 type Player struct {
-	Name string
-	Wins int
+	Name        string
+	DisplayName string   `json:",omitempty"`
+	Aliases     []string `json:",omitempty"`
+	Wins        int
+	Played      int     `json:",omitempty"`
+	Rating      float64 `json:",omitempty"`
+	Cashes      int     `json:",omitempty"`
+	BuyIns      int     `json:",omitempty"`
+	Winnings    int     `json:",omitempty"`
+}
+
+// Profit is everything the player has won minus everything they have paid to play.
+func (p Player) Profit() int {
+	return p.Winnings - p.BuyIns
 }
 
 type League []Player
 
 func (l League) Find(name string) *Player {
-	
+
 	for i, p := range l {
 		if p.Name == name {
 			return &l[i]
@@ -20,5 +46,76 @@ func (l League) Find(name string) *Player {
 type PlayerStore interface {
 	GetScore(name string) (int, error)
 	RecordWin(name string)
+	RecordGame(players []string, winners []string)
+	RecordPlacings(placings []Placing)
+	GetProfit(name string) (int, error)
 	GetLeagueTable() League
+	UndoLastWin() error
+	ImportLeague(league League, strategy string) (MergeReport, error)
+	GetResults() []Result
+	RegisterPlayer(name, displayName string, aliases ...string) error
+	RenamePlayer(name, newName string) error
+	MergePlayers(from, into string) error
+	DeletePlayer(name string) error
+}
+
+/**
+recordPlacings records a finished game from where everybody placed, the winners being those in
+first place, and adds the buy-ins and prizes to the players' bankrolls, a prize counts as a cash.
+*/
+func (l League) recordPlacings(placings []Placing) League {
+	var players, winners []string
+	for _, placing := range placings {
+		players = append(players, placing.Name)
+		if placing.Position == 1 {
+			winners = append(winners, placing.Name)
+		}
+	}
+	l = l.recordGame(players, winners)
+
+	for _, placing := range placings {
+		player := l.Find(placing.Name)
+		player.BuyIns += placing.BuyIn
+		player.Winnings += placing.Prize
+		if placing.Prize > 0 {
+			player.Cashes++
+		}
+	}
+	return l
+}
+
+func (l League) profit(name string) (int, error) {
+	player := l.Lookup(name)
+	if player == nil {
+		return 0, fmt.Errorf("Unknown player %v", name)
+	}
+	return player.Profit(), nil
+}
+
+/**
+leagueHistory keeps the league as it was before each of the last results recorded,
+undoing a result puts the league back the way it was, ratings included, and drops
+the results recorded since.
+*/
+type leagueHistory []leagueState
+
+type leagueState struct {
+	league  League
+	results int
+}
+
+func (h *leagueHistory) push(league League, results int) {
+	*h = append(*h, leagueState{append(League{}, league...), results})
+	if len(*h) > maxUndo {
+		*h = (*h)[1:]
+	}
+}
+
+func (h *leagueHistory) pop() (League, int, error) {
+	if len(*h) == 0 {
+		return nil, 0, ErrNothingToUndo
+	}
+	state := (*h)[len(*h)-1]
+	*h = (*h)[:len(*h)-1]
+	return state.league, state.results, nil
 }
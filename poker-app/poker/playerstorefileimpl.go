@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"sort"
 	"time"
@@ -23,23 +24,30 @@ func NewTapeFile(database *os.File) *Tape {
 	return &Tape{database}
 }
 
+/**
+FileSystemPlayerStore rewrites the whole file on every result, the league along with every result
+recorded so far. A file holding just a list of players, as it used to be written, is read as a league
+with no results.
+*/
 type FileSystemPlayerStore struct {
 	league   League
+	results  []Result
 	database *json.Encoder
+	history  leagueHistory
+	clock    Clock
 }
 
-func LoadUpFileStore(filestorepath string) (*FileSystemPlayerStore, func(), error) {
-	db, err := os.OpenFile(filestorepath, os.O_RDWR|os.O_CREATE, 0666)
+/**
+LoadUpFileStore opens the league kept at filestorepath with a journal next to it,
+a league file written by FileSystemPlayerStore is read as the starting snapshot.
+*/
+func LoadUpFileStore(filestorepath string) (*JournalPlayerStore, func(), error) {
+	store, err := NewJournalPlayerStore(filestorepath, DefaultCompactionThreshold, RealClock{})
 	if err != nil {
-		return nil, nil, fmt.Errorf("problem opening %s %v", filestorepath, err)
+		return nil, nil, fmt.Errorf("Problem with opening file database, %v", err)
 	}
 	closeFunc := func() {
-		db.Close()
-	}
-
-	store, err := NewFileSystemPlayerStore(db)
-	if err != nil {
-		return nil, nil, fmt.Errorf("Problem with opening file database, %v", err)
+		store.Close()
 	}
 
 	return store, closeFunc, nil
@@ -61,40 +69,54 @@ func initialisePlayerDBFile(file *os.File) error {
 	return nil
 }
 
-func NewFileSystemPlayerStore(file *os.File) (*FileSystemPlayerStore, error) {
+func NewFileSystemPlayerStore(file *os.File, clock Clock) (*FileSystemPlayerStore, error) {
 
 	e := initialisePlayerDBFile(file)
 	if e != nil {
 		return nil, fmt.Errorf("problem initialising player db file, %v", e)
 	}
 
-	league, err := newLeague(file)
+	snap, err := loadPlayerDBFile(file)
 	if err != nil {
 		return nil, fmt.Errorf("problem loading player store from file %s, %v", file.Name(), err)
 	}
 
 	store := &FileSystemPlayerStore{
-		league:   league,
-		database: json.NewEncoder(&Tape{file})}
+		league:   snap.League,
+		results:  snap.Results,
+		database: json.NewEncoder(&Tape{file}),
+		clock:    clock}
 
 	ticker := time.NewTicker(30 * time.Second)
 	go func() {
 		for _ = range ticker.C {
-			league, err := newLeague(file)
+			snap, err := loadPlayerDBFile(file)
 			if err == nil {
-				store.league = league
+				store.league, store.results = snap.League, snap.Results
 			}
 		}
 	}()
 	return store, nil
 }
 
+func loadPlayerDBFile(file *os.File) (snapshot, error) {
+	file.Seek(0, 0)
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		return snapshot{}, err
+	}
+	snap, err := parseSnapshot(data)
+	sortLeague(snap.League)
+	return snap, err
+}
+
+// GetLeagueTable gives back a copy of the league, so sorting it for a page does not reorder what is saved.
 func (fs *FileSystemPlayerStore) GetLeagueTable() League {
-	return fs.league
+	return append(League{}, fs.league...)
 }
 
 func (fs *FileSystemPlayerStore) GetScore(name string) (int, error) {
-	player := fs.league.Find(name)
+	player := fs.league.Lookup(name)
 
 	if player != nil {
 		return player.Wins, nil
@@ -110,15 +132,83 @@ on the league slice that we iterate on. For that reason, we need to get the refe
  by doing league[i] and then changing that value instead.
 */
 func (fs *FileSystemPlayerStore) RecordWin(name string) {
-	player := fs.league.Find(name)
+	fs.record(Result{Win: name})
+}
 
-	if player != nil {
-		player.Wins++
-	} else {
-		fs.league = append(fs.league, Player{name, 1})
-	}
+func (fs *FileSystemPlayerStore) RecordGame(players []string, winners []string) {
+	fs.record(Result{Players: players, Winners: winners})
+}
+
+func (fs *FileSystemPlayerStore) RecordPlacings(placings []Placing) {
+	fs.record(Result{Placings: placings})
+}
+
+func (fs *FileSystemPlayerStore) record(result Result) {
+	result = fs.league.resolve(result)
+	result.At = fs.clock.Now()
+	fs.history.push(fs.league, len(fs.results))
+	fs.league = result.apply(fs.league)
+	fs.results = append(fs.results, result)
 	sortLeague(fs.league)
-	fs.database.Encode(fs.league)
+	fs.save()
+}
+
+func (fs *FileSystemPlayerStore) save() error {
+	return fs.database.Encode(snapshot{League: fs.league, Results: fs.results})
+}
+
+func (fs *FileSystemPlayerStore) GetResults() []Result {
+	return fs.league.resolveResults(fs.results)
+}
+
+func (fs *FileSystemPlayerStore) RegisterPlayer(name, displayName string, aliases ...string) error {
+	return fs.change(playerChange{Action: registerPlayer, Name: name, DisplayName: displayName, Aliases: aliases})
+}
+
+func (fs *FileSystemPlayerStore) RenamePlayer(name, newName string) error {
+	return fs.change(playerChange{Action: renamePlayer, Name: name, To: newName})
+}
+
+func (fs *FileSystemPlayerStore) MergePlayers(from, into string) error {
+	return fs.change(playerChange{Action: mergePlayers, Name: from, To: into})
+}
+
+func (fs *FileSystemPlayerStore) DeletePlayer(name string) error {
+	return fs.change(playerChange{Action: deletePlayer, Name: name})
+}
+
+func (fs *FileSystemPlayerStore) change(c playerChange) error {
+	league, err := c.apply(fs.league)
+	if err != nil {
+		return err
+	}
+	fs.history.push(fs.league, len(fs.results))
+	fs.league = league
+	return fs.save()
+}
+
+func (fs *FileSystemPlayerStore) GetProfit(name string) (int, error) {
+	return fs.league.profit(name)
+}
+
+func (fs *FileSystemPlayerStore) UndoLastWin() error {
+	league, results, err := fs.history.pop()
+	if err != nil {
+		return err
+	}
+	fs.league = league
+	fs.results = fs.results[:results]
+	return fs.save()
+}
+
+func (fs *FileSystemPlayerStore) ImportLeague(league League, strategy string) (MergeReport, error) {
+	merged, report, err := MergeLeagues(fs.league, league, strategy)
+	if err != nil {
+		return report, err
+	}
+	fs.history.push(fs.league, len(fs.results))
+	fs.league = merged
+	return report, fs.save()
 }
 
 func sortLeague(league League) {
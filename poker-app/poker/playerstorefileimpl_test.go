@@ -20,7 +20,7 @@ func TestFileSystemStore(t *testing.T) {
 	}
 	defer clearDatabase()
 
-	store, err := poker.NewFileSystemPlayerStore(database)
+	store, err := poker.NewFileSystemPlayerStore(database, poker.RealClock{})
 	if err != nil {
 		t.Fatalf("Problem with opening file database, %v", err)
 	}
@@ -28,12 +28,14 @@ func TestFileSystemStore(t *testing.T) {
 	t.Run("/league from a reader", func(t *testing.T) {
 		got := store.GetLeagueTable()
 		want := poker.League{
-			{"Chris", 33},
-			{"Cleo", 10},
+			{Name: "Chris", Wins: 33},
+			{Name: "Cleo", Wins: 10},
 		}
 
 		assertleague(t, got, want)
 
+		// reordering the table handed out, as GET /players does, leaves the store's own league alone
+		got[0], got[1] = got[1], got[0]
 		got = store.GetLeagueTable()
 		assertleague(t, got, want)
 	})
@@ -88,7 +90,7 @@ func TestFileSystemStore(t *testing.T) {
 		database, _, cleanDatabase := createTempFile("")
 		defer cleanDatabase()
 
-		_, err := poker.NewFileSystemPlayerStore(database)
+		_, err := poker.NewFileSystemPlayerStore(database, poker.RealClock{})
 
 		if err != nil {
 			t.Fatalf("Problem with opening file database, %v", err)
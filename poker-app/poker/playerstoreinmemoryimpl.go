@@ -2,35 +2,138 @@ package poker
 
 import (
 	"fmt"
+	"sync"
 )
 
 type defaultStore struct {
-	scoreboard map[string]int
+	mu      sync.Mutex
+	league  League
+	results []Result
+	history leagueHistory
+	clock   Clock
 }
 
 func GetInMemoryStore(iniData ...map[string]int) PlayerStore {
+	return NewInMemoryPlayerStore(RealClock{}, iniData...)
+}
+
+// NewInMemoryPlayerStore keeps the league in memory, every result recorded is timed by the clock given.
+func NewInMemoryPlayerStore(clock Clock, iniData ...map[string]int) PlayerStore {
+	s := &defaultStore{clock: clock}
 	if len(iniData) > 0 {
-		return &defaultStore{iniData[0]}
+		for k, v := range iniData[0] {
+			s.league = append(s.league, Player{Name: k, Wins: v})
+		}
 	}
-	return &defaultStore{map[string]int{}}
+	return s
 }
 
 func (s *defaultStore) GetScore(player string) (int, error) {
-	score, ok := s.scoreboard[player]
-	if ok {
-		return score, nil
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.league.Lookup(player)
+	if p != nil {
+		return p.Wins, nil
 	}
 	return 0, fmt.Errorf("Unknown player found %s", player)
 }
 
 func (s *defaultStore) RecordWin(name string) {
-	s.scoreboard[name]++
+	s.record(Result{Win: name})
+}
+
+func (s *defaultStore) RecordGame(players []string, winners []string) {
+	s.record(Result{Players: players, Winners: winners})
+}
+
+func (s *defaultStore) RecordPlacings(placings []Placing) {
+	s.record(Result{Placings: placings})
+}
+
+func (s *defaultStore) record(result Result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result = s.league.resolve(result)
+	result.At = s.clock.Now()
+	s.history.push(s.league, len(s.results))
+	s.league = result.apply(s.league)
+	s.results = append(s.results, result)
+}
+
+func (s *defaultStore) GetProfit(name string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.league.profit(name)
+}
+
+func (s *defaultStore) UndoLastWin() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	league, results, err := s.history.pop()
+	if err != nil {
+		return err
+	}
+	s.league = league
+	s.results = s.results[:results]
+	return nil
+}
+
+func (s *defaultStore) ImportLeague(league League, strategy string) (MergeReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	merged, report, err := MergeLeagues(s.league, league, strategy)
+	if err != nil {
+		return report, err
+	}
+	s.history.push(s.league, len(s.results))
+	s.league = merged
+	return report, nil
 }
 
 func (s *defaultStore) GetLeagueTable() League {
-	var result League
-	for k, v := range s.scoreboard {
-		result = append(result, Player{k, v})
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append(League{}, s.league...)
+}
+
+func (s *defaultStore) GetResults() []Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.league.resolveResults(s.results)
+}
+
+func (s *defaultStore) RegisterPlayer(name, displayName string, aliases ...string) error {
+	return s.change(playerChange{Action: registerPlayer, Name: name, DisplayName: displayName, Aliases: aliases})
+}
+
+func (s *defaultStore) RenamePlayer(name, newName string) error {
+	return s.change(playerChange{Action: renamePlayer, Name: name, To: newName})
+}
+
+func (s *defaultStore) MergePlayers(from, into string) error {
+	return s.change(playerChange{Action: mergePlayers, Name: from, To: into})
+}
+
+func (s *defaultStore) DeletePlayer(name string) error {
+	return s.change(playerChange{Action: deletePlayer, Name: name})
+}
+
+func (s *defaultStore) change(c playerChange) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	league, err := c.apply(s.league)
+	if err != nil {
+		return err
 	}
-	return result
+	s.history.push(s.league, len(s.results))
+	s.league = league
+	return nil
 }
@@ -0,0 +1,444 @@
+package poker
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	DefaultCompactionThreshold = 1000
+
+	journalSuffix    = ".journal"
+	compactingSuffix = ".journal.compacting"
+)
+
+/**
+journalEntry is one line of the journal, every change to the league is appended as one.
+Seq increases by one with every entry so that a snapshot can tell which entries it already contains.
+Most entries are a result, an undo entry carries the whole league as it was before the result it undoes
+and how many results there were, an import carries the league imported and how it was merged
+and a change to who is in the league, a registration say, carries the change.
+Entries written before results were timed have no At, they count towards the all-time league only.
+*/
+type journalEntry struct {
+	Seq int64
+	Result
+	Undo     bool          `json:",omitempty"`
+	League   League        `json:",omitempty"`
+	Results  int           `json:",omitempty"`
+	Imported League        `json:",omitempty"`
+	Merge    string        `json:",omitempty"`
+	Change   *playerChange `json:",omitempty"`
+}
+
+/**
+snapshot is what the league compacts into, along with the results it was built from.
+Snapshots written before the journal existed are a bare JSON array of players, which is read as
+a snapshot at sequence 0, so an existing league file is migrated just by pointing the journal store at it.
+*/
+type snapshot struct {
+	Seq     int64 `json:",omitempty"`
+	League  League
+	Results []Result `json:",omitempty"`
+}
+
+/**
+JournalPlayerStore appends one fsynced line per recorded result instead of rewriting
+the whole league on every win, so a write costs the same however big the league gets
+and a crash can at worst lose the line being written.
+
+The league is rebuilt on load from the snapshot plus the journal. Once the journal grows past
+the compaction threshold it is swapped for an empty one and the old one is folded into a new
+snapshot in the background, the snapshot is written to a temporary file and renamed over the old
+one so there is always a complete snapshot on disk.
+*/
+type JournalPlayerStore struct {
+	mu           sync.Mutex
+	league       League
+	results      []Result
+	seq          int64
+	path         string
+	journal      *os.File
+	entries      int
+	compactAfter int
+	compacting   bool
+	compacted    sync.WaitGroup
+	history      leagueHistory
+	clock        Clock
+}
+
+func NewJournalPlayerStore(path string, compactAfter int, clock Clock) (*JournalPlayerStore, error) {
+	snap, err := readSnapshot(path)
+	if err != nil {
+		return nil, fmt.Errorf("problem loading league snapshot %s, %v", path, err)
+	}
+
+	store := &JournalPlayerStore{
+		league:       snap.League,
+		results:      snap.Results,
+		seq:          snap.Seq,
+		path:         path,
+		compactAfter: compactAfter,
+		clock:        clock,
+	}
+
+	// a compaction that was interrupted left its journal behind, it is replayed before the current one
+	for _, journalPath := range []string{path + compactingSuffix, path + journalSuffix} {
+		if err := store.replay(journalPath); err != nil {
+			return nil, fmt.Errorf("problem replaying journal %s, %v", journalPath, err)
+		}
+	}
+
+	if _, err := os.Stat(path + compactingSuffix); err == nil {
+		if err := store.writeSnapshot(store.snapshot()); err != nil {
+			return nil, fmt.Errorf("problem finishing an interrupted compaction, %v", err)
+		}
+	}
+
+	store.journal, err = os.OpenFile(path+journalSuffix, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		return nil, fmt.Errorf("problem opening journal %s, %v", path+journalSuffix, err)
+	}
+	sortLeague(store.league)
+	return store, nil
+}
+
+func (js *JournalPlayerStore) GetLeagueTable() League {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	return append(League{}, js.league...)
+}
+
+func (js *JournalPlayerStore) GetScore(name string) (int, error) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	player := js.league.Lookup(name)
+	if player != nil {
+		return player.Wins, nil
+	}
+	return 0, fmt.Errorf("Unknown player %v", name)
+}
+
+func (js *JournalPlayerStore) GetResults() []Result {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	return js.league.resolveResults(js.results)
+}
+
+func (js *JournalPlayerStore) RegisterPlayer(name, displayName string, aliases ...string) error {
+	return js.change(playerChange{Action: registerPlayer, Name: name, DisplayName: displayName, Aliases: aliases})
+}
+
+func (js *JournalPlayerStore) RenamePlayer(name, newName string) error {
+	return js.change(playerChange{Action: renamePlayer, Name: name, To: newName})
+}
+
+func (js *JournalPlayerStore) MergePlayers(from, into string) error {
+	return js.change(playerChange{Action: mergePlayers, Name: from, To: into})
+}
+
+func (js *JournalPlayerStore) DeletePlayer(name string) error {
+	return js.change(playerChange{Action: deletePlayer, Name: name})
+}
+
+// change checks the change can be made before it is journalled, so replaying it never fails.
+func (js *JournalPlayerStore) change(c playerChange) error {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	if _, err := c.apply(js.league); err != nil {
+		return err
+	}
+	entry := journalEntry{Seq: js.seq + 1, Change: &c}
+	entry.At = js.clock.Now()
+	if err := js.append(entry); err != nil {
+		return fmt.Errorf("problem writing to the journal, %v", err)
+	}
+	js.apply(entry)
+	js.entries++
+	return nil
+}
+
+func (js *JournalPlayerStore) RecordWin(name string) {
+	js.record(journalEntry{Result: Result{Win: name}})
+}
+
+func (js *JournalPlayerStore) RecordGame(players []string, winners []string) {
+	js.record(journalEntry{Result: Result{Players: players, Winners: winners}})
+}
+
+func (js *JournalPlayerStore) RecordPlacings(placings []Placing) {
+	js.record(journalEntry{Result: Result{Placings: placings}})
+}
+
+func (js *JournalPlayerStore) GetProfit(name string) (int, error) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	return js.league.profit(name)
+}
+
+// UndoLastWin puts the league back the way it was before the last result, as far back as this store has seen.
+func (js *JournalPlayerStore) UndoLastWin() error {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	if len(js.history) == 0 {
+		return ErrNothingToUndo
+	}
+	last := js.history[len(js.history)-1]
+	entry := journalEntry{Seq: js.seq + 1, Undo: true, League: last.league, Results: last.results}
+	entry.At = js.clock.Now()
+	if err := js.append(entry); err != nil {
+		return fmt.Errorf("problem writing to the journal, %v", err)
+	}
+	js.apply(entry)
+	js.entries++
+	return nil
+}
+
+// ImportLeague merges the league given into this one, the import is journalled as one entry and undone as one.
+func (js *JournalPlayerStore) ImportLeague(league League, strategy string) (MergeReport, error) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	_, report, err := MergeLeagues(js.league, league, strategy)
+	if err != nil {
+		return report, err
+	}
+	entry := journalEntry{Seq: js.seq + 1, Imported: league, Merge: strategy}
+	entry.At = js.clock.Now()
+	if err := js.append(entry); err != nil {
+		return report, fmt.Errorf("problem writing to the journal, %v", err)
+	}
+	js.apply(entry)
+	js.entries++
+	return report, nil
+}
+
+func (js *JournalPlayerStore) record(entry journalEntry) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	entry.Seq = js.seq + 1
+	entry.Result = js.league.resolve(entry.Result)
+	entry.At = js.clock.Now()
+	if err := js.append(entry); err != nil {
+		log.Printf("problem writing to the journal, %v\n", err)
+		return
+	}
+	js.apply(entry)
+
+	js.entries++
+	if js.compactAfter > 0 && js.entries >= js.compactAfter && !js.compacting {
+		js.startCompaction()
+	}
+}
+
+// apply must be called holding the lock, replaying an entry keeps the undo history the same as recording it did.
+func (js *JournalPlayerStore) apply(entry journalEntry) {
+	switch {
+	case entry.Undo:
+		js.history.pop()
+		js.league = append(League{}, entry.League...)
+		if entry.Results < len(js.results) {
+			js.results = js.results[:entry.Results]
+		}
+	case entry.Change != nil:
+		js.history.push(js.league, len(js.results))
+		js.league, _ = entry.Change.apply(js.league)
+	case entry.Merge != "":
+		// the import was checked before it was journalled, it merges the same way on replay
+		js.history.push(js.league, len(js.results))
+		js.league, _, _ = MergeLeagues(js.league, entry.Imported, entry.Merge)
+	default:
+		js.history.push(js.league, len(js.results))
+		js.league = entry.Result.apply(js.league)
+		js.results = append(js.results, entry.Result)
+	}
+	js.seq = entry.Seq
+	sortLeague(js.league)
+}
+
+// snapshot must be called holding the lock.
+func (js *JournalPlayerStore) snapshot() snapshot {
+	return snapshot{js.seq, append(League{}, js.league...), append([]Result{}, js.results...)}
+}
+
+func (js *JournalPlayerStore) append(entry journalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := js.journal.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return js.journal.Sync()
+}
+
+// Compact folds the journal into the snapshot straight away, and waits for a background compaction to finish.
+func (js *JournalPlayerStore) Compact() error {
+	js.compacted.Wait()
+
+	js.mu.Lock()
+	if js.compacting {
+		js.mu.Unlock()
+		return nil
+	}
+	snap, err := js.rotate()
+	js.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return js.writeSnapshot(snap)
+}
+
+func (js *JournalPlayerStore) startCompaction() {
+	snap, err := js.rotate()
+	if err != nil {
+		log.Printf("problem rotating the journal, %v\n", err)
+		return
+	}
+	js.compacting = true
+	js.compacted.Add(1)
+	go func() {
+		defer js.compacted.Done()
+		if err := js.writeSnapshot(snap); err != nil {
+			log.Printf("problem compacting the journal, %v\n", err)
+		}
+		js.mu.Lock()
+		js.compacting = false
+		js.mu.Unlock()
+	}()
+}
+
+/**
+rotate must be called holding the lock, it moves the current journal aside and starts
+an empty one, returning the league as of the last entry in the journal moved aside.
+*/
+func (js *JournalPlayerStore) rotate() (snapshot, error) {
+	if _, err := os.Stat(js.path + compactingSuffix); err == nil {
+		return snapshot{}, fmt.Errorf("the journal from a previous compaction is still waiting to be snapshotted")
+	}
+	if err := js.journal.Close(); err != nil {
+		return snapshot{}, err
+	}
+	if err := os.Rename(js.path+journalSuffix, js.path+compactingSuffix); err != nil {
+		return snapshot{}, err
+	}
+	journal, err := os.OpenFile(js.path+journalSuffix, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		return snapshot{}, err
+	}
+	js.journal = journal
+	js.entries = 0
+	return js.snapshot(), nil
+}
+
+func (js *JournalPlayerStore) writeSnapshot(snap snapshot) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(js.path), filepath.Base(js.path)+".snapshot")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := json.NewEncoder(tmp).Encode(snap); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), js.path); err != nil {
+		return err
+	}
+	return os.Remove(js.path + compactingSuffix)
+}
+
+func (js *JournalPlayerStore) Close() error {
+	js.compacted.Wait()
+
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	return js.journal.Close()
+}
+
+/**
+replay applies the entries that are newer than the snapshot. A crash in the middle of
+an append leaves a torn last line, which is cut off so the next append starts on a clean line.
+*/
+func (js *JournalPlayerStore) replay(journalPath string) error {
+	file, err := os.OpenFile(journalPath, os.O_RDWR, 0666)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var good int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		var entry journalEntry
+		if json.Unmarshal(line, &entry) != nil {
+			break
+		}
+		good += int64(len(line))
+		if entry.Seq <= js.seq {
+			continue
+		}
+		js.apply(entry)
+		js.entries++
+	}
+	return file.Truncate(good)
+}
+
+func readSnapshot(path string) (snapshot, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return snapshot{}, nil
+	}
+	if err != nil {
+		return snapshot{}, err
+	}
+	return parseSnapshot(data)
+}
+
+// parseSnapshot reads a snapshot, or a bare league as FileSystemPlayerStore used to write it.
+func parseSnapshot(data []byte) (snapshot, error) {
+	data = bytes.TrimSpace(data)
+	switch {
+	case len(data) == 0:
+		return snapshot{}, nil
+	case data[0] == '[':
+		league, err := newLeague(bytes.NewReader(data))
+		return snapshot{League: league}, err
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return snapshot{}, fmt.Errorf("problem parsing league snapshot, %v", err)
+	}
+	return snap, nil
+}
@@ -0,0 +1,183 @@
+package poker_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ydsxiong/go-playground/poker-app/poker"
+)
+
+func TestJournalPlayerStore(t *testing.T) {
+
+	t.Run("it migrates a league file written by the file system store", func(t *testing.T) {
+		path, clean := createTempLeague(t, `[
+		{"Name": "Cleo", "Wins": 10},
+		{"Name": "Chris", "Wins": 33}]`)
+		defer clean()
+
+		store := mustOpenJournal(t, path, 100)
+		defer store.Close()
+
+		assertleague(t, store.GetLeagueTable(), poker.League{
+			{Name: "Chris", Wins: 33},
+			{Name: "Cleo", Wins: 10},
+		})
+	})
+
+	t.Run("it appends one line per win and rebuilds the league on load", func(t *testing.T) {
+		path, clean := createTempLeague(t, `[{"Name": "Chris", "Wins": 33}]`)
+		defer clean()
+
+		store := mustOpenJournal(t, path, 100)
+		store.RecordWin("Chris")
+		store.RecordWin("Pepper")
+		store.RecordGame([]string{"Chris", "Pepper"}, []string{"Pepper"})
+		store.Close()
+
+		if lines := journalLines(t, path); lines != 3 {
+			t.Errorf("expected 3 lines in the journal, got %d", lines)
+		}
+
+		reopened := mustOpenJournal(t, path, 100)
+		defer reopened.Close()
+
+		assertScore(t, reopened, "Chris", 34)
+		assertScore(t, reopened, "Pepper", 2)
+		if got := reopened.GetLeagueTable().Find("Pepper").Played; got != 2 {
+			t.Errorf("got %d games played by Pepper, want 2", got)
+		}
+	})
+
+	t.Run("it drops a torn last line left by a crash", func(t *testing.T) {
+		path, clean := createTempLeague(t, "")
+		defer clean()
+
+		ioutil.WriteFile(path+".journal", []byte(`{"Seq":1,"Win":"Chris"}`+"\n"+`{"Seq":2,"Wi`), 0666)
+
+		store := mustOpenJournal(t, path, 100)
+		store.RecordWin("Chris")
+		store.Close()
+
+		reopened := mustOpenJournal(t, path, 100)
+		defer reopened.Close()
+		assertScore(t, reopened, "Chris", 2)
+	})
+
+	t.Run("an undo survives reopening the store and can go back further", func(t *testing.T) {
+		path, clean := createTempLeague(t, "")
+		defer clean()
+
+		store := mustOpenJournal(t, path, 100)
+		store.RecordWin("Chris")
+		store.RecordGame([]string{"Chris", "Cleo"}, []string{"Cleo"})
+		assertNoError(t, store.UndoLastWin())
+		store.Close()
+
+		reopened := mustOpenJournal(t, path, 100)
+		defer reopened.Close()
+		if reopened.GetLeagueTable().Find("Cleo") != nil {
+			t.Errorf("expected Cleo's win to be undone, got %v", reopened.GetLeagueTable())
+		}
+		assertScore(t, reopened, "Chris", 1)
+
+		assertNoError(t, reopened.UndoLastWin())
+		if err := reopened.UndoLastWin(); err != poker.ErrNothingToUndo {
+			t.Errorf("got %v, want %v", err, poker.ErrNothingToUndo)
+		}
+	})
+
+	t.Run("an import is journalled as one entry and replayed the same way", func(t *testing.T) {
+		path, clean := createTempLeague(t, `[{"Name": "Chris", "Wins": 3}]`)
+		defer clean()
+
+		store := mustOpenJournal(t, path, 100)
+		_, err := store.ImportLeague(poker.League{{Name: "Chris", Wins: 2}, {Name: "Cleo", Wins: 1}}, poker.MergeSum)
+		assertNoError(t, err)
+		store.Close()
+
+		if lines := journalLines(t, path); lines != 1 {
+			t.Errorf("expected 1 line in the journal, got %d", lines)
+		}
+
+		reopened := mustOpenJournal(t, path, 100)
+		defer reopened.Close()
+		assertScore(t, reopened, "Chris", 5)
+		assertScore(t, reopened, "Cleo", 1)
+
+		assertNoError(t, reopened.UndoLastWin())
+		assertScore(t, reopened, "Chris", 3)
+	})
+
+	t.Run("it compacts the journal into a snapshot once it grows past the threshold", func(t *testing.T) {
+		path, clean := createTempLeague(t, `[{"Name": "Chris", "Wins": 33}]`)
+		defer clean()
+
+		store := mustOpenJournal(t, path, 3)
+		for i := 0; i < 4; i++ {
+			store.RecordWin("Cleo")
+		}
+
+		compacted := retryUntil(500*time.Millisecond, func() bool {
+			snapshot, _ := ioutil.ReadFile(path)
+			return strings.Contains(string(snapshot), `"Seq":3`)
+		})
+		if !compacted {
+			t.Fatal("expected the journal to have been compacted")
+		}
+		store.Close()
+
+		if lines := journalLines(t, path); lines != 1 {
+			t.Errorf("expected only the win after the compaction left in the journal, got %d lines", lines)
+		}
+
+		reopened := mustOpenJournal(t, path, 3)
+		defer reopened.Close()
+		assertScore(t, reopened, "Chris", 33)
+		assertScore(t, reopened, "Cleo", 4)
+	})
+}
+
+func createTempLeague(t *testing.T, initialData string) (string, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir(".", "journal")
+	if err != nil {
+		t.Fatalf("could not create temp dir %v", err)
+	}
+	path := filepath.Join(dir, "league.db.json")
+	if initialData != "" {
+		ioutil.WriteFile(path, []byte(initialData), 0666)
+	}
+	return path, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func mustOpenJournal(t *testing.T, path string, compactAfter int) *poker.JournalPlayerStore {
+	t.Helper()
+	store, err := poker.NewJournalPlayerStore(path, compactAfter, poker.RealClock{})
+	if err != nil {
+		t.Fatalf("Problem with opening journal store, %v", err)
+	}
+	return store
+}
+
+func journalLines(t *testing.T, path string) int {
+	t.Helper()
+	journal, err := ioutil.ReadFile(path + ".journal")
+	if err != nil {
+		t.Fatalf("could not read journal %v", err)
+	}
+	return strings.Count(string(journal), "\n")
+}
+
+func assertScore(t *testing.T, store poker.PlayerStore, name string, want int) {
+	t.Helper()
+	got, err := store.GetScore(name)
+	if err != nil || got != want {
+		t.Errorf("got score %d (%v) for %s, want %d", got, err, name, want)
+	}
+}
@@ -0,0 +1,4 @@
+// Package pokerpb is the gRPC API of the poker server, generated from poker.proto.
+package pokerpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative poker.proto
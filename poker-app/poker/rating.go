@@ -0,0 +1,142 @@
+package poker
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	DefaultRating = 1500.0
+
+	// how far a single game can move a rating
+	ratingKFactor = 32.0
+
+	RankByWins   = "wins"
+	RankByRating = "rating"
+	RankByCashes = "cashes"
+	RankByProfit = "profit"
+)
+
+// CurrentRating is the player's Elo rating, players who have not finished a game yet start at DefaultRating.
+func (p Player) CurrentRating() float64 {
+	if p.Rating == 0 {
+		return DefaultRating
+	}
+	return p.Rating
+}
+
+/**
+GamesPlayed counts every game the player finished, wins recorded on their own
+through POST /players/{name} count as games played too, otherwise those players
+would be sitting on more than 100% of wins.
+*/
+func (p Player) GamesPlayed() int {
+	if p.Wins > p.Played {
+		return p.Wins
+	}
+	return p.Played
+}
+
+/**
+recordGame adds a finished game to the league: a win for every winner,
+a game played and a new rating for every player who took part.
+
+Ratings follow a multiplayer Elo: every winner is scored as having beaten every loser,
+winners of a split pot draw with each other, and losers are not compared with each other
+as poker does not tell us anything about them. The change is averaged over the number
+of opponents so a ten player game does not swing a rating more than a heads-up one.
+*/
+func (l League) recordGame(players []string, winners []string) League {
+	won := map[string]bool{}
+	for _, w := range winners {
+		won[w] = true
+	}
+
+	before := map[string]float64{}
+	for _, name := range players {
+		if l.Find(name) == nil {
+			l = append(l, Player{Name: name})
+		}
+		before[name] = l.Find(name).CurrentRating()
+	}
+
+	for _, name := range players {
+		player := l.Find(name)
+		player.Played = player.GamesPlayed() + 1
+		if won[name] {
+			player.Wins++
+		}
+
+		delta := 0.0
+		for _, opponent := range players {
+			if opponent == name || (!won[name] && !won[opponent]) {
+				continue
+			}
+			score := 0.5
+			if won[name] && !won[opponent] {
+				score = 1
+			} else if !won[name] && won[opponent] {
+				score = 0
+			}
+			expected := 1 / (1 + math.Pow(10, (before[opponent]-before[name])/400))
+			delta += ratingKFactor * (score - expected)
+		}
+		if len(players) > 1 {
+			delta /= float64(len(players) - 1)
+		}
+		player.Rating = math.Round((before[name]+delta)*10) / 10
+	}
+	return l
+}
+
+type Standing struct {
+	Name          string
+	DisplayName   string `json:",omitempty"`
+	Wins          int
+	Played        int
+	WinPercentage float64
+	Rating        float64
+	Cashes        int
+	Profit        int
+}
+
+/**
+Standings ranks the league by raw win count, by rating, by how often players finished
+in the money or by how much they are up overall.
+*/
+func (l League) Standings(rank string) ([]Standing, error) {
+	standings := make([]Standing, 0, len(l))
+	for _, p := range l {
+		played := p.GamesPlayed()
+		percentage := 0.0
+		if played > 0 {
+			percentage = math.Round(float64(p.Wins)/float64(played)*1000) / 10
+		}
+		standings = append(standings, Standing{p.Name, p.DisplayName, p.Wins, played, percentage, p.CurrentRating(), p.Cashes, p.Profit()})
+	}
+
+	var less func(a, b Standing) bool
+	switch rank {
+	case "", RankByWins:
+		less = func(a, b Standing) bool { return a.Wins > b.Wins }
+	case RankByRating:
+		less = func(a, b Standing) bool { return a.Rating > b.Rating }
+	case RankByCashes:
+		less = func(a, b Standing) bool { return a.Cashes > b.Cashes }
+	case RankByProfit:
+		less = func(a, b Standing) bool { return a.Profit > b.Profit }
+	default:
+		return nil, fmt.Errorf("unknown ranking %q, expecting %s, %s, %s or %s", rank, RankByWins, RankByRating, RankByCashes, RankByProfit)
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		if less(standings[i], standings[j]) {
+			return true
+		}
+		if less(standings[j], standings[i]) {
+			return false
+		}
+		return standings[i].Name < standings[j].Name
+	})
+	return standings, nil
+}
@@ -0,0 +1,90 @@
+package poker_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ydsxiong/go-playground/poker-app/poker"
+)
+
+func TestRatings(t *testing.T) {
+
+	t.Run("winners gain what losers lose", func(t *testing.T) {
+		store := poker.GetInMemoryStore()
+		store.RecordGame([]string{"Chris", "Cleo", "Pepper"}, []string{"Chris"})
+
+		league := store.GetLeagueTable()
+		chris, cleo, pepper := league.Find("Chris"), league.Find("Cleo"), league.Find("Pepper")
+
+		if chris.Rating != 1516 || cleo.Rating != 1492 || pepper.Rating != 1492 {
+			t.Errorf("got ratings %v %v %v, want 1516 1492 1492", chris.Rating, cleo.Rating, pepper.Rating)
+		}
+		if chris.Wins != 1 || cleo.Wins != 0 || chris.Played != 1 || pepper.Played != 1 {
+			t.Errorf("expected one game played by all and won by Chris, got %+v", league)
+		}
+	})
+
+	t.Run("beating a stronger player is worth more", func(t *testing.T) {
+		store := poker.GetInMemoryStore()
+		for i := 0; i < 5; i++ {
+			store.RecordGame([]string{"Chris", "Cleo"}, []string{"Chris"})
+		}
+		before := store.GetLeagueTable().Find("Cleo").Rating
+
+		store.RecordGame([]string{"Chris", "Cleo"}, []string{"Cleo"})
+		upset := store.GetLeagueTable().Find("Cleo").Rating - before
+
+		if upset <= 16 {
+			t.Errorf("expected more than 16 points for beating a stronger player, got %v", upset)
+		}
+	})
+
+	t.Run("a split pot is a draw between the winners", func(t *testing.T) {
+		store := poker.GetInMemoryStore()
+		store.RecordGame([]string{"Chris", "Cleo"}, []string{"Chris", "Cleo"})
+
+		league := store.GetLeagueTable()
+		if league.Find("Chris").Rating != poker.DefaultRating || league.Find("Cleo").Wins != 1 {
+			t.Errorf("expected both to win without changing ratings, got %+v", league)
+		}
+	})
+}
+
+func TestStandings(t *testing.T) {
+	league := poker.League{
+		{Name: "Grinder", Wins: 10, Played: 40, Rating: 1480},
+		{Name: "Shark", Wins: 6, Played: 8, Rating: 1560},
+		{Name: "Newcomer", Wins: 1},
+	}
+
+	t.Run("ranked by wins", func(t *testing.T) {
+		got, _ := league.Standings(poker.RankByWins)
+		assertstandings(t, got, []poker.Standing{
+			{Name: "Grinder", Wins: 10, Played: 40, WinPercentage: 25, Rating: 1480},
+			{Name: "Shark", Wins: 6, Played: 8, WinPercentage: 75, Rating: 1560},
+			{Name: "Newcomer", Wins: 1, Played: 1, WinPercentage: 100, Rating: poker.DefaultRating},
+		})
+	})
+
+	t.Run("ranked by rating", func(t *testing.T) {
+		got, _ := league.Standings(poker.RankByRating)
+		if got[0].Name != "Shark" || got[1].Name != "Newcomer" || got[2].Name != "Grinder" {
+			t.Errorf("got %v", got)
+		}
+	})
+
+	t.Run("/league?rank=rating and a bad ranking", func(t *testing.T) {
+		store := poker.GetInMemoryStore()
+		store.RecordGame([]string{"Chris", "Cleo"}, []string{"Cleo"})
+		server := mustMakePlayerServer(t, store, dummyGame)
+
+		res := serveRequest(server, http.MethodGet, "/league?rank=rating", "")
+		got := assertresponsecode(t, res)
+		if len(got) != 2 || got[0].Name != "Cleo" || got[0].Rating <= got[1].Rating {
+			t.Errorf("expected Cleo on top of the ratings, got %v", got)
+		}
+
+		res = serveRequest(server, http.MethodGet, "/league?rank=luck", "")
+		assertStatus(t, res, http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,163 @@
+package poker
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/**
+Result is one result recorded in the league and when it was recorded: a win on its own,
+a game and who won it, or a game with everybody's placings. The stores keep every result
+so that the league of any season can be worked out again from the results recorded in it.
+*/
+type Result struct {
+	At       time.Time
+	Win      string    `json:",omitempty"`
+	Players  []string  `json:",omitempty"`
+	Winners  []string  `json:",omitempty"`
+	Placings []Placing `json:",omitempty"`
+}
+
+func (r Result) apply(league League) League {
+	if len(r.Placings) > 0 {
+		return league.recordPlacings(r.Placings)
+	}
+	if r.Win != "" {
+		if player := league.Find(r.Win); player != nil {
+			player.Wins++
+			return league
+		}
+		return append(league, Player{Name: r.Win, Wins: 1})
+	}
+	return league.recordGame(r.Players, r.Winners)
+}
+
+// The seasons that are not a date range: every result ever recorded, and the season going on now.
+const (
+	AllTime       = "all-time"
+	CurrentSeason = "current"
+)
+
+/**
+Season is a period the league starts afresh in, so that newcomers have a chance of winning one.
+The seasons are the quarters of the year, named like 2026-Q3, a whole year such as 2026 can be
+asked for as well. A season starts at Start and runs up to but not including End, in UTC.
+*/
+type Season struct {
+	Name  string
+	Start time.Time
+	End   time.Time
+}
+
+// SeasonOf returns the quarter t falls in.
+func SeasonOf(t time.Time) Season {
+	t = t.UTC()
+	quarter := (int(t.Month())-1)/3 + 1
+	start := time.Date(t.Year(), time.Month((quarter-1)*3+1), 1, 0, 0, 0, 0, time.UTC)
+	return Season{fmt.Sprintf("%d-Q%d", t.Year(), quarter), start, start.AddDate(0, 3, 0)}
+}
+
+// ParseSeason reads a season named like 2026-Q3 or a year like 2026.
+func ParseSeason(name string) (Season, error) {
+	year, quarter := name, ""
+	i := strings.Index(strings.ToUpper(name), "-Q")
+	if i >= 0 {
+		year, quarter = name[:i], name[i+2:]
+	}
+
+	y, err := strconv.Atoi(year)
+	if err != nil || y < 1 {
+		return Season{}, fmt.Errorf("unknown season %q, expecting a quarter like 2026-Q3 or a year like 2026", name)
+	}
+	// only a name without -Q is a whole year, 2026-Q is a quarter left off
+	if i < 0 {
+		start := time.Date(y, time.January, 1, 0, 0, 0, 0, time.UTC)
+		return Season{year, start, start.AddDate(1, 0, 0)}, nil
+	}
+	q, err := strconv.Atoi(quarter)
+	if err != nil || q < 1 || q > 4 {
+		return Season{}, fmt.Errorf("unknown season %q, a year has quarters Q1 to Q4", name)
+	}
+	return SeasonOf(time.Date(y, time.Month((q-1)*3+1), 1, 0, 0, 0, 0, time.UTC)), nil
+}
+
+func (s Season) Contains(t time.Time) bool {
+	return !t.Before(s.Start) && t.Before(s.End)
+}
+
+/**
+League replays the results recorded during the season into a league of its own, everybody starting
+from nothing and the default rating. Leagues imported from elsewhere only count towards the all-time league,
+they have no dates to place their results in a season.
+*/
+func (s Season) League(results []Result) League {
+	var league League
+	for _, result := range results {
+		if s.Contains(result.At) {
+			league = result.apply(league)
+		}
+	}
+	sortLeague(league)
+	return league
+}
+
+// Champion is the player who won the most games in a season that is over.
+type Champion struct {
+	Season string
+	Name   string
+	Wins   int
+	Played int
+}
+
+/**
+Champions lists the winner of every season that ended before now, the latest season first.
+Seasons nobody won a game in have no champion, a tie goes to the player first in alphabetical order
+as it does in the standings.
+*/
+func Champions(results []Result, now time.Time) []Champion {
+	seasons := map[string]Season{}
+	for _, result := range results {
+		if result.At.IsZero() {
+			continue
+		}
+		if season := SeasonOf(result.At); !season.End.After(now) {
+			seasons[season.Name] = season
+		}
+	}
+
+	var champions []Champion
+	for _, season := range seasons {
+		standings, _ := season.League(results).Standings(RankByWins)
+		if len(standings) == 0 || standings[0].Wins == 0 {
+			continue
+		}
+		champions = append(champions, Champion{season.Name, standings[0].Name, standings[0].Wins, standings[0].Played})
+	}
+	sort.Slice(champions, func(i, j int) bool {
+		return seasons[champions[i].Season].Start.After(seasons[champions[j].Season].Start)
+	})
+	return champions
+}
+
+/**
+SeasonLeague is the league of the season named, all-time (or no season at all) being every result
+ever recorded and imported, as the store keeps it. Any other season is replayed from the store's results.
+*/
+func SeasonLeague(store PlayerStore, name string, now time.Time) (League, error) {
+	var season Season
+	switch name {
+	case "", AllTime:
+		return store.GetLeagueTable(), nil
+	case CurrentSeason:
+		season = SeasonOf(now)
+	default:
+		var err error
+		if season, err = ParseSeason(name); err != nil {
+			return nil, err
+		}
+	}
+	return season.League(store.GetResults()), nil
+}
@@ -0,0 +1,184 @@
+package poker_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ydsxiong/go-playground/poker-app/poker"
+)
+
+func TestParseSeason(t *testing.T) {
+	cases := []struct {
+		name       string
+		start, end time.Time
+	}{
+		{"2026-Q3", time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{"2025-q4", time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"2026", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		season, err := poker.ParseSeason(c.name)
+		assertNoError(t, err)
+		if !season.Start.Equal(c.start) || !season.End.Equal(c.end) {
+			t.Errorf("got %s from %v to %v, want %v to %v", c.name, season.Start, season.End, c.start, c.end)
+		}
+	}
+
+	for _, bad := range []string{"2026-Q5", "summer", "2026-Qx", "2026-Q", ""} {
+		if _, err := poker.ParseSeason(bad); err == nil {
+			t.Errorf("expected an error for season %q", bad)
+		}
+	}
+
+	if got := poker.SeasonOf(time.Date(2026, 9, 30, 23, 59, 0, 0, time.UTC)).Name; got != "2026-Q3" {
+		t.Errorf("got season %s for the end of September, want 2026-Q3", got)
+	}
+}
+
+func TestSeasons(t *testing.T) {
+	q1 := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	for name, open := range playerStores {
+		t.Run(name, func(t *testing.T) {
+			clock := poker.NewVirtualClock(q1)
+			store, reopen, clean := open(t, clock)
+			defer clean()
+
+			store.RecordWin("Chris")
+			store.RecordGame([]string{"Chris", "Pepper"}, []string{"Pepper"})
+			store.RecordGame([]string{"Chris", "Pepper"}, []string{"Chris"})
+			clock.Advance(90 * 24 * time.Hour)
+			store.RecordGame([]string{"Chris", "Pepper"}, []string{"Pepper"})
+			store.RecordGame([]string{"Pepper", "Ruth"}, []string{"Ruth"})
+			store.RecordGame([]string{"Pepper", "Ruth"}, []string{"Ruth"})
+			assertNoError(t, store.UndoLastWin())
+
+			store = reopen()
+			assertSeasonWins(t, store, "2025-Q1", map[string]int{"Chris": 2, "Pepper": 1})
+			assertSeasonWins(t, store, "2025-Q2", map[string]int{"Chris": 0, "Pepper": 1, "Ruth": 1})
+			assertSeasonWins(t, store, "2025", map[string]int{"Chris": 2, "Pepper": 2, "Ruth": 1})
+			assertSeasonWins(t, store, poker.AllTime, map[string]int{"Chris": 2, "Pepper": 2, "Ruth": 1, "Cleo": 10})
+
+			results := store.GetResults()
+			if len(results) != 5 || !results[0].At.Equal(q1) {
+				t.Errorf("expected 5 results, the first one at %v, got %+v", q1, results)
+			}
+
+			champions := poker.Champions(results, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC))
+			want := []poker.Champion{
+				{Season: "2025-Q2", Name: "Pepper", Wins: 1, Played: 2},
+				{Season: "2025-Q1", Name: "Chris", Wins: 2, Played: 3},
+			}
+			if !reflect.DeepEqual(champions, want) {
+				t.Errorf("got champions %+v, want %+v", champions, want)
+			}
+		})
+	}
+
+	t.Run("a season still going on has no champion yet", func(t *testing.T) {
+		store := poker.NewInMemoryPlayerStore(poker.NewVirtualClock(q1))
+		store.RecordWin("Chris")
+
+		if champions := poker.Champions(store.GetResults(), q1.Add(time.Hour)); len(champions) != 0 {
+			t.Errorf("expected no champions before the season is over, got %v", champions)
+		}
+	})
+}
+
+func TestSeasonsAPI(t *testing.T) {
+	clock := poker.NewVirtualClock(time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC))
+	store := poker.NewInMemoryPlayerStore(clock, map[string]int{"Cleo": 10})
+	store.RecordGame([]string{"Chris", "Pepper"}, []string{"Chris"})
+	clock.Advance(90 * 24 * time.Hour)
+	store.RecordGame([]string{"Chris", "Pepper"}, []string{"Pepper"})
+
+	server, err := poker.NewPlayerServer(store, dummyGame, poker.NewTableRegistry(func() poker.Game { return dummyGame }), dummyGameStore, dummyStructures)
+	assertNoError(t, err)
+
+	t.Run("it ranks the results of the season asked for", func(t *testing.T) {
+		res := serveRequest(server, http.MethodGet, "/league?season=2025-Q2", "")
+		standings := assertresponsecode(t, res)
+		if len(standings) != 2 || standings[0].Name != "Pepper" || standings[0].Wins != 1 || standings[1].Wins != 0 {
+			t.Errorf("unexpected 2025-Q2 standings %+v", standings)
+		}
+
+		standings = assertresponsecode(t, serveRequest(server, http.MethodGet, "/league?season=all-time", ""))
+		if len(standings) != 3 || standings[0].Name != "Cleo" {
+			t.Errorf("unexpected all-time standings %+v", standings)
+		}
+	})
+
+	t.Run("an unknown season is a bad request", func(t *testing.T) {
+		assertStatus(t, serveRequest(server, http.MethodGet, "/league?season=summer", ""), http.StatusBadRequest)
+	})
+
+	t.Run("it lists the champions of the seasons that are over", func(t *testing.T) {
+		res := serveRequest(server, http.MethodGet, "/league/champions", "")
+		assertStatus(t, res, http.StatusOK)
+
+		var champions []poker.Champion
+		json.NewDecoder(res.Body).Decode(&champions)
+		want := []poker.Champion{
+			{Season: "2025-Q2", Name: "Pepper", Wins: 1, Played: 1},
+			{Season: "2025-Q1", Name: "Chris", Wins: 1, Played: 1},
+		}
+		if !reflect.DeepEqual(champions, want) {
+			t.Errorf("got champions %+v, want %+v", champions, want)
+		}
+	})
+}
+
+/**
+playerStores opens each of the stores with Cleo on 10 wins in it, reopen gives the store
+as read back from disk, the same store for the one that is only in memory.
+*/
+var playerStores = map[string]func(t *testing.T, clock poker.Clock) (store poker.PlayerStore, reopen func() poker.PlayerStore, clean func()){
+	"in memory": func(t *testing.T, clock poker.Clock) (poker.PlayerStore, func() poker.PlayerStore, func()) {
+		store := poker.NewInMemoryPlayerStore(clock, map[string]int{"Cleo": 10})
+		return store, func() poker.PlayerStore { return store }, func() {}
+	},
+	"file system": func(t *testing.T, clock poker.Clock) (poker.PlayerStore, func() poker.PlayerStore, func()) {
+		database, err, clean := createTempFile(`[{"Name": "Cleo", "Wins": 10}]`)
+		assertNoError(t, err)
+		store, err := poker.NewFileSystemPlayerStore(database, clock)
+		assertNoError(t, err)
+		return store, func() poker.PlayerStore {
+			reopened, err := poker.NewFileSystemPlayerStore(database, clock)
+			assertNoError(t, err)
+			return reopened
+		}, clean
+	},
+	"journal": func(t *testing.T, clock poker.Clock) (poker.PlayerStore, func() poker.PlayerStore, func()) {
+		path, clean := createTempLeague(t, `[{"Name": "Cleo", "Wins": 10}]`)
+		store, err := poker.NewJournalPlayerStore(path, 100, clock)
+		assertNoError(t, err)
+		var reopened *poker.JournalPlayerStore
+		return store, func() poker.PlayerStore {
+				store.Close()
+				reopened, err = poker.NewJournalPlayerStore(path, 100, clock)
+				assertNoError(t, err)
+				return reopened
+			}, func() {
+				if reopened != nil {
+					reopened.Close()
+				}
+				clean()
+			}
+	},
+}
+
+func assertSeasonWins(t *testing.T, store poker.PlayerStore, season string, want map[string]int) {
+	t.Helper()
+	league, err := poker.SeasonLeague(store, season, time.Now())
+	assertNoError(t, err)
+	got := map[string]int{}
+	for _, p := range league {
+		got[p.Name] = p.Wins
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got wins %v in %s, want %v", got, season, want)
+	}
+}
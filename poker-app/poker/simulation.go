@@ -0,0 +1,179 @@
+package poker
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"time"
+)
+
+const (
+	DefaultStartingStack = 3000
+	DefaultHandDuration  = 2 * time.Minute
+	DefaultMaxHands      = 1000
+)
+
+/**
+Simulation plays games among bots without anybody at the table, through the same Game and stores
+the CLI and the web server use, on a virtual clock so that hours of blinds pass in no time.
+
+Every player starts with StartingStack chips, the hands are played with them so nobody bets more
+than they have, and after each hand the players pay what they bet and take what they won out of the
+pots. A player who has lost all their chips is knocked out and the game goes on until one player is left, or
+until MaxHands have been played, when the biggest stack wins. Every hand moves the clock on by
+HandDuration, which is what takes the game up the blind structure.
+*/
+type Simulation struct {
+	StartingStack int
+	HandDuration  time.Duration
+	MaxHands      int
+
+	game      Game
+	games     GameStore
+	clock     *VirtualClock
+	bots      Bots
+	structure string
+}
+
+// NewSimulation plays the bots against each other, the game has to go by the clock given so the simulation can move it on.
+func NewSimulation(game Game, games GameStore, clock *VirtualClock, bots Bots, structure string) *Simulation {
+	return &Simulation{
+		StartingStack: DefaultStartingStack,
+		HandDuration:  DefaultHandDuration,
+		MaxHands:      DefaultMaxHands,
+		game:          game,
+		games:         games,
+		clock:         clock,
+		bots:          bots,
+		structure:     structure,
+	}
+}
+
+type SimulationStats struct {
+	Games    int
+	Hands    int
+	PlayTime time.Duration
+	// how many games finished at each blind level
+	Levels map[int]int
+	Wins   map[string]int
+}
+
+func (s SimulationStats) AverageHands() float64 {
+	if s.Games == 0 {
+		return 0
+	}
+	return float64(s.Hands) / float64(s.Games)
+}
+
+func (s SimulationStats) AverageLength() time.Duration {
+	if s.Games == 0 {
+		return 0
+	}
+	return s.PlayTime / time.Duration(s.Games)
+}
+
+// Play plays the number of games given, the bots take turns to be first to act from one game to the next.
+func (s *Simulation) Play(numberOfGames int) (SimulationStats, error) {
+	stats := SimulationStats{Levels: map[int]int{}, Wins: map[string]int{}}
+
+	var players []string
+	for name := range s.bots {
+		players = append(players, name)
+	}
+	sort.Strings(players)
+	if len(players) < MinPlayersPerHand || len(players) > MaxPlayersPerHand {
+		return stats, fmt.Errorf("a simulation needs between %d and %d bots, got %d", MinPlayersPerHand, MaxPlayersPerHand, len(players))
+	}
+	for _, p := range players {
+		stats.Wins[p] = 0
+	}
+
+	for i := 0; i < numberOfGames; i++ {
+		seats := append(append([]string{}, players[i%len(players):]...), players[:i%len(players)]...)
+		hands, winners, err := s.playGame(seats)
+		if err != nil {
+			return stats, fmt.Errorf("problem playing game %d, %v", i+1, err)
+		}
+
+		records, err := s.games.GetGames()
+		if err != nil || len(records) == 0 {
+			return stats, fmt.Errorf("problem reading back game %d, %v", i+1, err)
+		}
+		record := records[len(records)-1]
+
+		stats.Games++
+		stats.Hands += hands
+		stats.PlayTime += record.Duration()
+		stats.Levels[record.BlindLevels]++
+		for _, w := range winners {
+			stats.Wins[w]++
+		}
+	}
+	return stats, nil
+}
+
+func (s *Simulation) playGame(players []string) (int, []string, error) {
+	alerts, err := s.game.Start(len(players), s.structure, BuyIn{}, ioutil.Discard)
+	if err != nil {
+		s.game.Abort()
+		return 0, nil, err
+	}
+	defer alerts.Cancel()
+
+	entrants := append([]string{}, players...)
+	stacks := map[string]int{}
+	for _, p := range players {
+		stacks[p] = s.StartingStack
+	}
+
+	var eliminated []string
+	hands := 0
+	for len(players) > 1 && hands < s.MaxHands {
+		result, err := s.game.PlayHand(players, stacks, s.bots, ioutil.Discard)
+		if err != nil {
+			s.game.Abort()
+			return hands, nil, err
+		}
+		hands++
+
+		result.Settle(stacks)
+		for _, p := range result.Eliminated {
+			if stacks[p] <= 0 {
+				eliminated = append(eliminated, p)
+				players = without(players, p)
+			}
+		}
+
+		// the button moves on to the next player
+		if len(players) > 1 {
+			players = append(players[1:], players[0])
+		}
+		s.clock.Advance(s.HandDuration)
+	}
+
+	// out of hands, the biggest stacks win and everybody else finishes in order of their chips
+	sort.SliceStable(players, func(i, j int) bool {
+		return stacks[players[i]] < stacks[players[j]]
+	})
+	var winners []string
+	for _, p := range players {
+		if stacks[p] == stacks[players[len(players)-1]] {
+			winners = append(winners, p)
+		} else {
+			eliminated = append(eliminated, p)
+		}
+	}
+
+	s.game.Finish(entrants, HandResult{Winners: winners, Eliminated: eliminated})
+	return hands, winners, nil
+}
+
+func without(players []string, player string) []string {
+	var left []string
+	for _, p := range players {
+		if p != player {
+			left = append(left, p)
+		}
+	}
+	return left
+}
@@ -0,0 +1,175 @@
+package poker
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// GameFactory hands out a fresh Game for every table, so that each table keeps its own blind schedule.
+type GameFactory func() Game
+
+type TableInfo struct {
+	ID      string
+	Seats   int
+	Players []string
+	Playing bool
+}
+
+/**
+Table is a named game session: players take a seat before a game is started on it,
+and there is at most one game running at a table at any time.
+*/
+type Table struct {
+	id    string
+	seats int
+	game  Game
+	hub   *GameHub
+
+	mu      sync.Mutex
+	players []string
+	playing bool
+}
+
+func (t *Table) ID() string {
+	return t.id
+}
+
+func (t *Table) Game() Game {
+	return t.game
+}
+
+// Hub is where the spectators of the table subscribe to its games.
+func (t *Table) Hub() *GameHub {
+	return t.hub
+}
+
+func (t *Table) Info() TableInfo {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return TableInfo{t.id, t.seats, append([]string{}, t.players...), t.playing}
+}
+
+func (t *Table) Join(player string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if player == "" {
+		return fmt.Errorf("player name must not be empty")
+	}
+	for _, p := range t.players {
+		if p == player {
+			return fmt.Errorf("%s is already seated at table %s", player, t.id)
+		}
+	}
+	if len(t.players) >= t.seats {
+		return fmt.Errorf("table %s is full, all %d seats are taken", t.id, t.seats)
+	}
+	t.players = append(t.players, player)
+	return nil
+}
+
+func (t *Table) Leave(player string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, p := range t.players {
+		if p == player {
+			t.players = append(t.players[:i], t.players[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not seated at table %s", player, t.id)
+}
+
+/**
+Sit reserves the table for a game and returns the players seated at that moment,
+call Stand once the game is over to free the table again.
+*/
+func (t *Table) Sit() ([]string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.playing {
+		return nil, fmt.Errorf("a game is already running at table %s", t.id)
+	}
+	if len(t.players) < MinPlayersPerHand {
+		return nil, fmt.Errorf("table %s needs at least %d players to start, has %d", t.id, MinPlayersPerHand, len(t.players))
+	}
+	t.playing = true
+	return append([]string{}, t.players...), nil
+}
+
+func (t *Table) Stand() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.playing = false
+}
+
+type TableRegistry struct {
+	newGame GameFactory
+
+	mu     sync.RWMutex
+	tables map[string]*Table
+}
+
+func NewTableRegistry(newGame GameFactory) *TableRegistry {
+	return &TableRegistry{newGame: newGame, tables: map[string]*Table{}}
+}
+
+func (r *TableRegistry) Open(id string, seats int) (*Table, error) {
+	if id == "" {
+		return nil, fmt.Errorf("table id must not be empty")
+	}
+	if seats == 0 {
+		seats = MaxPlayersPerHand
+	}
+	if seats < MinPlayersPerHand || seats > MaxPlayersPerHand {
+		return nil, fmt.Errorf("a table has between %d and %d seats, got %d", MinPlayersPerHand, MaxPlayersPerHand, seats)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.tables[id]; ok {
+		return nil, fmt.Errorf("table %s already exists", id)
+	}
+	table := &Table{id: id, seats: seats, game: r.newGame(), hub: NewGameHub()}
+	r.tables[id] = table
+	return table, nil
+}
+
+func (r *TableRegistry) Get(id string) (*Table, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	table, ok := r.tables[id]
+	return table, ok
+}
+
+func (r *TableRegistry) Close(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	table, ok := r.tables[id]
+	if !ok {
+		return fmt.Errorf("unknown table %s", id)
+	}
+	if table.Info().Playing {
+		return fmt.Errorf("cannot close table %s while a game is running", id)
+	}
+	delete(r.tables, id)
+	return nil
+}
+
+func (r *TableRegistry) List() []TableInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var infos []TableInfo
+	for _, t := range r.tables {
+		infos = append(infos, t.Info())
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ID < infos[j].ID
+	})
+	return infos
+}
@@ -0,0 +1,134 @@
+package poker_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ydsxiong/go-playground/poker-app/poker"
+)
+
+func TestTableRegistry(t *testing.T) {
+
+	t.Run("every table gets its own game", func(t *testing.T) {
+		games := 0
+		registry := poker.NewTableRegistry(func() poker.Game {
+			games++
+			return &GameSpy{}
+		})
+
+		red, _ := registry.Open("red", 6)
+		blue, _ := registry.Open("blue", 6)
+
+		if games != 2 || red.Game() == blue.Game() {
+			t.Errorf("expected a game per table, got %d games", games)
+		}
+		if _, err := registry.Open("red", 6); err == nil {
+			t.Error("expected an error opening the same table twice")
+		}
+	})
+
+	t.Run("players cannot sit at a full table", func(t *testing.T) {
+		registry := poker.NewTableRegistry(func() poker.Game { return &GameSpy{} })
+		table, _ := registry.Open("red", 2)
+
+		assertNoError(t, table.Join("Chris"))
+		assertNoError(t, table.Join("Cleo"))
+
+		if err := table.Join("Pepper"); err == nil {
+			t.Error("expected an error joining a full table")
+		}
+
+		assertNoError(t, table.Leave("Chris"))
+		assertNoError(t, table.Join("Pepper"))
+	})
+
+	t.Run("only one game runs at a table at a time", func(t *testing.T) {
+		registry := poker.NewTableRegistry(func() poker.Game { return &GameSpy{} })
+		table, _ := registry.Open("red", 2)
+		table.Join("Chris")
+		table.Join("Cleo")
+
+		_, err := table.Sit()
+		assertNoError(t, err)
+
+		if _, err := table.Sit(); err == nil {
+			t.Error("expected an error starting a second game")
+		}
+		if err := registry.Close("red"); err == nil {
+			t.Error("expected an error closing a table with a game running")
+		}
+
+		table.Stand()
+		assertNoError(t, registry.Close("red"))
+	})
+}
+
+func TestTablesAPI(t *testing.T) {
+	store := poker.GetInMemoryStore()
+
+	t.Run("open a table, take seats and play a game on it", func(t *testing.T) {
+		game := &GameSpy{HandWinner: "Cleo"}
+		registry := poker.NewTableRegistry(func() poker.Game { return game })
+		server, _ := poker.NewPlayerServer(store, dummyGame, registry, dummyGameStore, dummyStructures)
+
+		res := serveRequest(server, http.MethodPost, "/tables", `{"ID": "red", "Seats": 4}`)
+		assertStatus(t, res, http.StatusCreated)
+
+		for _, player := range []string{"Chris", "Cleo"} {
+			res = serveRequest(server, http.MethodPost, "/tables/red/players/"+player, "")
+			assertStatus(t, res, http.StatusOK)
+		}
+
+		res = serveRequest(server, http.MethodGet, "/tables", "")
+		var tables []poker.TableInfo
+		json.NewDecoder(res.Body).Decode(&tables)
+		if len(tables) != 1 || len(tables[0].Players) != 2 {
+			t.Fatalf("expected one table with two players, got %+v", tables)
+		}
+
+		httpServer := httptest.NewServer(server)
+		defer httpServer.Close()
+		ws := mustDialWS(t, "ws"+strings.TrimPrefix(httpServer.URL, "http")+"/tables/red/ws")
+		defer ws.Close()
+
+		writeWSMessage(t, ws, poker.StartTableGameMsg)
+
+		assertGameStartedWith(t, game, 2)
+		assertFinishCalledWith(t, game, "Cleo")
+	})
+
+	t.Run("unknown tables are not found", func(t *testing.T) {
+		server := mustMakePlayerServer(t, store, dummyGame)
+
+		res := serveRequest(server, http.MethodPost, "/tables/green/players/Chris", "")
+		assertStatus(t, res, http.StatusNotFound)
+	})
+}
+
+func serveRequest(server http.Handler, method, url, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, url, strings.NewReader(body))
+	return serveRequestWith(server, req)
+}
+
+func serveRequestWith(server http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	res := httptest.NewRecorder()
+	server.ServeHTTP(res, req)
+	return res
+}
+
+func assertStatus(t *testing.T, res *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if res.Code != want {
+		t.Errorf("wrong status code, got %d, but wanted %d (%s)", res.Code, want, res.Body.String())
+	}
+}
+
+func assertNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("did not expect an error, %v", err)
+	}
+}
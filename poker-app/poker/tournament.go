@@ -0,0 +1,499 @@
+package poker
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FinalTableID is the table the last players of a tournament are brought together at.
+const FinalTableID = "final"
+
+type TournamentTable struct {
+	ID      string
+	Players []string
+	Playing bool
+}
+
+// TableMove is a player being moved to another table, to balance the tables or because theirs was broken up.
+type TableMove struct {
+	Player string
+	From   string
+	To     string
+}
+
+type TournamentInfo struct {
+	ID         string
+	Structure  string
+	BuyIn      BuyIn
+	Seats      int
+	Stack      int
+	Entrants   int
+	Remaining  int
+	Blind      string
+	Tables     []TournamentTable
+	Stacks     map[string]int
+	Moves      []TableMove
+	Eliminated []string
+	Placings   []Placing `json:",omitempty"`
+	Finished   bool
+}
+
+/**
+Tournament is one game played over several tables. The blinds are scheduled once for the whole
+tournament on its Game, so every table plays at the same level. Every entrant starts with the same
+stack of chips, which the hands dealt at a table are played with: nobody can bet more than they have,
+and after the hand the players pay what they bet and take what they won out of the pots, so the chips
+in play never change. Players who have lost all their chips are knocked
+out, after which the tables are balanced again: tables that are no longer needed are broken up, no
+table has more than one player more than any other and once everybody left fits at one table they are
+brought together at the final table.
+*/
+type Tournament struct {
+	id        string
+	seats     int
+	stack     int
+	structure string
+	buyIn     BuyIn
+	game      Game
+	hub       *GameHub
+
+	mu         sync.Mutex
+	entrants   []string
+	stacks     map[string]int
+	tables     []*TournamentTable
+	moves      []TableMove
+	eliminated []string
+	blind      string
+	alerts     AlertHandle
+	placings   []Placing
+	finished   bool
+	cancelled  bool
+}
+
+/**
+NewTournament seats the players around as few tables as they fit at, dealing them out in turn
+so the tables start balanced, gives each of them a stack of chips and starts the blinds.
+*/
+func NewTournament(id string, players []string, seats, stack int, structure string, buyIn BuyIn, game Game) (*Tournament, error) {
+	if id == "" {
+		return nil, fmt.Errorf("tournament id must not be empty")
+	}
+	if seats == 0 {
+		seats = MaxPlayersPerHand
+	}
+	if seats < MinPlayersPerHand || seats > MaxPlayersPerHand {
+		return nil, fmt.Errorf("a table has between %d and %d seats, got %d", MinPlayersPerHand, MaxPlayersPerHand, seats)
+	}
+	if stack == 0 {
+		stack = DefaultStartingStack
+	}
+	if stack < 0 {
+		return nil, fmt.Errorf("the starting stack must be positive, got %d", stack)
+	}
+	if len(players) < MinPlayersPerHand {
+		return nil, fmt.Errorf("a tournament needs at least %d players, got %d", MinPlayersPerHand, len(players))
+	}
+	seen := map[string]bool{}
+	for _, p := range players {
+		if p == "" || seen[p] {
+			return nil, fmt.Errorf("player names must be unique and not empty, got %q", players)
+		}
+		seen[p] = true
+	}
+
+	t := &Tournament{
+		id:        id,
+		seats:     seats,
+		stack:     stack,
+		structure: structure,
+		buyIn:     buyIn,
+		game:      game,
+		hub:       NewGameHub(),
+		entrants:  append([]string{}, players...),
+		stacks:    map[string]int{},
+	}
+	for _, p := range players {
+		t.stacks[p] = stack
+	}
+
+	numberOfTables := (len(players) + seats - 1) / seats
+	for i := 0; i < numberOfTables; i++ {
+		t.tables = append(t.tables, &TournamentTable{ID: strconv.Itoa(i + 1)})
+	}
+	if numberOfTables == 1 {
+		t.tables[0].ID = FinalTableID
+	}
+	for i, p := range players {
+		table := t.tables[i%numberOfTables]
+		table.Players = append(table.Players, p)
+	}
+
+	// the tournament hosts its own hub, so spectators only hear of it once its game has started
+	t.hub.Host()
+	alerts, err := game.Start(len(players), structure, buyIn, t)
+	if err != nil {
+		game.Abort()
+		t.hub.Release()
+		return nil, err
+	}
+	t.alerts = alerts
+	t.hub.Start(t.entrants, structure)
+	return t, nil
+}
+
+func (t *Tournament) ID() string {
+	return t.id
+}
+
+// Hub is where the spectators of the tournament follow its blinds and its winner.
+func (t *Tournament) Hub() *GameHub {
+	return t.hub
+}
+
+// Write is where the blind alerts of the tournament go, every table plays at the level last written.
+func (t *Tournament) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	t.blind = strings.TrimSpace(string(p))
+	t.mu.Unlock()
+	return t.hub.Write(p)
+}
+
+func (t *Tournament) Info() TournamentInfo {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	info := TournamentInfo{
+		ID:         t.id,
+		Structure:  t.structure,
+		BuyIn:      t.buyIn,
+		Seats:      t.seats,
+		Stack:      t.stack,
+		Entrants:   len(t.entrants),
+		Remaining:  t.remaining(),
+		Blind:      t.blind,
+		Moves:      append([]TableMove{}, t.moves...),
+		Eliminated: append([]string{}, t.eliminated...),
+		Placings:   t.placings,
+		Finished:   t.finished,
+		Stacks:     map[string]int{},
+	}
+	for p, chips := range t.stacks {
+		info.Stacks[p] = chips
+	}
+	for _, table := range t.tables {
+		info.Tables = append(info.Tables, TournamentTable{table.ID, append([]string{}, table.Players...), table.Playing})
+	}
+	return info
+}
+
+/**
+PlayTable deals a hand at the table with the players seated there. The chips bet go to the winners
+of the hand, the players left without any are knocked out of the tournament and the tables are balanced
+again, what happened is written to the table after the hand. The tournament is over once only one
+player is left.
+*/
+func (t *Tournament) PlayTable(id string, actor Actor, to io.Writer) (HandResult, error) {
+	t.mu.Lock()
+	table, err := t.sit(id)
+	var players []string
+	stacks := map[string]int{}
+	if err == nil {
+		players = append(players, table.Players...)
+		for _, p := range players {
+			stacks[p] = t.stacks[p]
+		}
+	}
+	t.mu.Unlock()
+	if err != nil {
+		return HandResult{}, err
+	}
+
+	result, err := t.game.PlayHand(players, stacks, actor, to)
+
+	t.mu.Lock()
+	table.Playing = false
+	if err != nil {
+		t.mu.Unlock()
+		return result, err
+	}
+	result.Settle(t.stacks)
+	report := t.knockOut(result.Eliminated)
+	report = append(report, t.balance()...)
+	if t.remaining() == 1 {
+		report = append(report, t.finish()...)
+	}
+	finished := t.finished
+	t.mu.Unlock()
+
+	// the alerts write to the tournament, so they are not cancelled holding its lock
+	if finished {
+		t.Cancel()
+	}
+	for _, line := range report {
+		fmt.Fprintln(to, line)
+	}
+	if finished && t.buyIn.Amount > 0 {
+		printPlacings(to, t.placings)
+	}
+	return result, nil
+}
+
+/**
+Cancel stops the blinds, of a tournament that is over or will not be played to the end.
+A tournament called off before it was over is recorded as aborted and its spectators are told so,
+no more hands are dealt at its tables.
+*/
+func (t *Tournament) Cancel() {
+	t.alerts.Cancel()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.finished && !t.cancelled {
+		t.game.Abort()
+	}
+	t.cancelled = true
+	t.hub.Release()
+}
+
+// sit, knockOut, balance and finish must be called holding the lock.
+func (t *Tournament) sit(id string) (*TournamentTable, error) {
+	if t.finished {
+		return nil, fmt.Errorf("tournament %s is over", t.id)
+	}
+	if t.cancelled {
+		return nil, fmt.Errorf("tournament %s was called off", t.id)
+	}
+	table := t.table(id)
+	if table == nil {
+		return nil, fmt.Errorf("unknown table %s in tournament %s", id, t.id)
+	}
+	if table.Playing {
+		return nil, fmt.Errorf("a hand is already being played at table %s", id)
+	}
+	if len(table.Players) < MinPlayersPerHand {
+		return nil, fmt.Errorf("table %s is waiting for players to be moved to it", id)
+	}
+	table.Playing = true
+	return table, nil
+}
+
+// knockOut takes the players who lost the hand and have no chips left away from their table.
+func (t *Tournament) knockOut(players []string) []string {
+	var report []string
+	for _, p := range players {
+		if t.stacks[p] > 0 {
+			continue
+		}
+		for _, table := range t.tables {
+			if table.remove(p) {
+				report = append(report, fmt.Sprintf("%s is knocked out in place %d", p, t.remaining()+1))
+				t.eliminated = append(t.eliminated, p)
+			}
+		}
+	}
+	return report
+}
+
+/**
+balance breaks up tables that are no longer needed and then moves players from the fullest table
+to the emptiest until they differ by one player at most. Players are never moved away from a table
+while a hand is being played there, the tables are balanced again after every hand.
+*/
+func (t *Tournament) balance() []string {
+	remaining := t.remaining()
+	if remaining <= 1 {
+		return nil
+	}
+	needed := (remaining + t.seats - 1) / t.seats
+
+	if needed == 1 && (len(t.tables) > 1 || t.tables[0].ID != FinalTableID) {
+		return t.finalTable()
+	}
+
+	var report []string
+	for len(t.tables) > needed {
+		broken := t.emptiest(true)
+		if broken == nil {
+			break
+		}
+		t.removeTable(broken)
+		for _, p := range broken.Players {
+			report = append(report, t.move(p, broken, t.emptiest(false)))
+		}
+	}
+
+	for {
+		fullest, emptiest := t.fullest(), t.emptiest(false)
+		if fullest == nil || len(fullest.Players)-len(emptiest.Players) <= 1 {
+			return report
+		}
+		player := fullest.Players[len(fullest.Players)-1]
+		fullest.remove(player)
+		report = append(report, t.move(player, fullest, emptiest))
+	}
+}
+
+// finalTable brings everybody together at the final table once no hand is being played anywhere.
+func (t *Tournament) finalTable() []string {
+	for _, table := range t.tables {
+		if table.Playing {
+			return nil
+		}
+	}
+	final := &TournamentTable{ID: FinalTableID}
+	var report []string
+	for _, table := range t.tables {
+		for _, p := range table.Players {
+			report = append(report, t.move(p, table, final))
+		}
+	}
+	t.tables = []*TournamentTable{final}
+	return append(report, fmt.Sprintf("The final table is %s", strings.Join(final.Players, ", ")))
+}
+
+func (t *Tournament) finish() []string {
+	t.finished = true
+
+	var winner string
+	for _, table := range t.tables {
+		if len(table.Players) > 0 {
+			winner = table.Players[0]
+		}
+	}
+	result := HandResult{Winners: []string{winner}, Eliminated: t.eliminated}
+	t.placings = t.game.Finish(t.entrants, result)
+	t.hub.Finish(result.Winners)
+	return []string{fmt.Sprintf("%s wins tournament %s", winner, t.id)}
+}
+
+func (t *Tournament) move(player string, from, to *TournamentTable) string {
+	to.Players = append(to.Players, player)
+	t.moves = append(t.moves, TableMove{player, from.ID, to.ID})
+	return fmt.Sprintf("%s moves from table %s to table %s", player, from.ID, to.ID)
+}
+
+func (t *Tournament) remaining() int {
+	n := 0
+	for _, table := range t.tables {
+		n += len(table.Players)
+	}
+	return n
+}
+
+func (t *Tournament) table(id string) *TournamentTable {
+	for _, table := range t.tables {
+		if table.ID == id {
+			return table
+		}
+	}
+	return nil
+}
+
+func (t *Tournament) removeTable(table *TournamentTable) {
+	for i, candidate := range t.tables {
+		if candidate == table {
+			t.tables = append(t.tables[:i], t.tables[i+1:]...)
+			return
+		}
+	}
+}
+
+// emptiest finds the table with the fewest players, when idle is set only a table without a hand being played will do.
+func (t *Tournament) emptiest(idle bool) *TournamentTable {
+	var emptiest *TournamentTable
+	for _, table := range t.tables {
+		if idle && table.Playing {
+			continue
+		}
+		if emptiest == nil || len(table.Players) < len(emptiest.Players) {
+			emptiest = table
+		}
+	}
+	return emptiest
+}
+
+// fullest finds the table with the most players that nobody is playing a hand at.
+func (t *Tournament) fullest() *TournamentTable {
+	var fullest *TournamentTable
+	for _, table := range t.tables {
+		if !table.Playing && (fullest == nil || len(table.Players) > len(fullest.Players)) {
+			fullest = table
+		}
+	}
+	return fullest
+}
+
+func (table *TournamentTable) remove(player string) bool {
+	for i, p := range table.Players {
+		if p == player {
+			table.Players = append(table.Players[:i], table.Players[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+type TournamentRegistry struct {
+	newGame GameFactory
+
+	mu          sync.RWMutex
+	tournaments map[string]*Tournament
+}
+
+// NewTournamentRegistry gives every tournament a fresh Game from the factory, so each keeps its own blinds.
+func NewTournamentRegistry(newGame GameFactory) *TournamentRegistry {
+	return &TournamentRegistry{newGame: newGame, tournaments: map[string]*Tournament{}}
+}
+
+func (r *TournamentRegistry) Open(id string, players []string, seats, stack int, structure string, buyIn BuyIn) (*Tournament, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.tournaments[id]; ok {
+		return nil, fmt.Errorf("tournament %s already exists", id)
+	}
+	tournament, err := NewTournament(id, players, seats, stack, structure, buyIn, r.newGame())
+	if err != nil {
+		return nil, err
+	}
+	r.tournaments[id] = tournament
+	return tournament, nil
+}
+
+func (r *TournamentRegistry) Get(id string) (*Tournament, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tournament, ok := r.tournaments[id]
+	return tournament, ok
+}
+
+// Close cancels the tournament if it is still being played and forgets about it.
+func (r *TournamentRegistry) Close(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tournament, ok := r.tournaments[id]
+	if !ok {
+		return fmt.Errorf("unknown tournament %s", id)
+	}
+	tournament.Cancel()
+	delete(r.tournaments, id)
+	return nil
+}
+
+func (r *TournamentRegistry) List() []TournamentInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var infos []TournamentInfo
+	for _, t := range r.tournaments {
+		infos = append(infos, t.Info())
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ID < infos[j].ID
+	})
+	return infos
+}
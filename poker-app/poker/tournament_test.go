@@ -0,0 +1,342 @@
+package poker_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ydsxiong/go-playground/poker-app/poker"
+)
+
+var tournamentPlayers = []string{"Alice", "Bob", "Chris", "Cleo", "Pepper", "Ruth", "Sam"}
+
+// keeping plays a hand in which everybody but the given player folds as soon as it is their turn
+func keeping(player string) poker.Actor {
+	return poker.ActorFunc(func(view poker.TableView) poker.Action {
+		if view.Player != player {
+			return poker.Action{Kind: poker.Fold}
+		}
+		return passiveActor(view)
+	})
+}
+
+// riggedGame deals from a stacked deck in which the players higher up the ranking hold the higher pair,
+// so that a player who is all-in and cannot fold loses the showdown to whoever is meant to win
+type riggedGame struct {
+	poker.Game
+	ranking []string
+}
+
+func (g *riggedGame) PlayHand(players []string, stacks map[string]int, actor poker.Actor, to io.Writer) (poker.HandResult, error) {
+	pairs := []string{"A", "K", "Q", "J", "T", "8", "6"}
+	cards := ""
+	for _, p := range players {
+		for rank, name := range g.ranking {
+			if name == p {
+				cards += fmt.Sprintf("%ss %sd ", pairs[rank], pairs[rank])
+			}
+		}
+	}
+	hand, err := poker.NewHand(players, stacks, poker.NewStackedDeck(poker.MustParseCards(cards+"2c 7h 9s 4d 3c")...), 100, 0)
+	if err != nil {
+		return poker.HandResult{}, err
+	}
+	return hand.Play(actor, to)
+}
+
+func TestTournament(t *testing.T) {
+
+	t.Run("it seats the players at as few tables as they fit at and starts the blinds once", func(t *testing.T) {
+		alerter := &SpyBlindAlerter{}
+		game := poker.NewGame(poker.GetInMemoryStore(), alerter, poker.GetInMemoryGameStore(), dummyStructures, poker.RealClock{})
+
+		tournament, err := poker.NewTournament("sunday", tournamentPlayers, 3, 0, "", poker.BuyIn{}, game)
+		assertNoError(t, err)
+
+		assertTournamentTables(t, tournament, map[string][]string{
+			"1": {"Alice", "Cleo", "Sam"},
+			"2": {"Bob", "Pepper"},
+			"3": {"Chris", "Ruth"},
+		})
+		standard, _ := dummyStructures.Get("", len(tournamentPlayers))
+		if len(alerter.alert) != len(standard.Levels) {
+			t.Errorf("expected one alert per level for the whole tournament, got %d", len(alerter.alert))
+		}
+	})
+
+	t.Run("players who fold keep their seat and only those left without chips are knocked out", func(t *testing.T) {
+		game := poker.NewGame(poker.GetInMemoryStore(), dummySpyAlerter, poker.GetInMemoryGameStore(), dummyStructures, poker.RealClock{})
+		tournament, _ := poker.NewTournament("sunday", []string{"Chris", "Cleo", "Pepper"}, 3, 500, "", poker.BuyIn{}, game)
+
+		// Chris posts the small blind and calls, Cleo folds the big blind and Pepper folds without having bet
+		mustPlayTable(t, tournament, poker.FinalTableID, "Chris", &bytes.Buffer{})
+
+		assertTournamentTables(t, tournament, map[string][]string{
+			poker.FinalTableID: {"Chris", "Cleo", "Pepper"},
+		})
+		info := tournament.Info()
+		if want := map[string]int{"Chris": 600, "Cleo": 400, "Pepper": 500}; !reflect.DeepEqual(info.Stacks, want) {
+			t.Errorf("got stacks %v, want %v", info.Stacks, want)
+		}
+		if info.Remaining != 3 || len(info.Eliminated) != 0 {
+			t.Errorf("expected nobody to be knocked out, got %+v", info)
+		}
+	})
+
+	t.Run("it balances the tables, brings the last players to the final table and pays out", func(t *testing.T) {
+		store := poker.GetInMemoryStore()
+		game := &riggedGame{
+			Game:    poker.NewGame(store, dummySpyAlerter, poker.GetInMemoryGameStore(), dummyStructures, poker.RealClock{}),
+			ranking: []string{"Chris", "Bob", "Alice", "Cleo", "Pepper", "Ruth", "Sam"},
+		}
+		buyIn := poker.BuyIn{Amount: 10, Payouts: poker.PayoutTable{70, 30}}
+		// a stack of one big blind, so the big blind is all-in and out once it loses the showdown
+		tournament, _ := poker.NewTournament("sunday", tournamentPlayers, 3, 100, "", buyIn, game)
+
+		out := &bytes.Buffer{}
+		mustPlayTable(t, tournament, "1", "Alice", out)
+		assertTournamentTables(t, tournament, map[string][]string{
+			"2": {"Bob", "Pepper", "Alice"},
+			"3": {"Chris", "Ruth", "Sam"},
+		})
+		if !strings.Contains(out.String(), "Cleo is knocked out in place 7\nAlice moves from table 1 to table 2\nSam moves from table 1 to table 3\n") {
+			t.Errorf("expected the table to be told who is out and who moves, got %q", out.String())
+		}
+
+		mustPlayTable(t, tournament, "3", "Chris", out)
+		mustPlayTable(t, tournament, "2", "Bob", out)
+		assertTournamentTables(t, tournament, map[string][]string{
+			"2": {"Bob", "Alice"},
+			"3": {"Chris", "Sam"},
+		})
+
+		mustPlayTable(t, tournament, "3", "Chris", out)
+		assertTournamentTables(t, tournament, map[string][]string{
+			poker.FinalTableID: {"Bob", "Alice", "Chris"},
+		})
+
+		for hands := 0; !tournament.Info().Finished; hands++ {
+			if hands == 10 {
+				t.Fatalf("expected the tournament to be over, got %+v", tournament.Info())
+			}
+			mustPlayTable(t, tournament, poker.FinalTableID, "Chris", out)
+		}
+		info := tournament.Info()
+		if want := []string{"Cleo", "Ruth", "Pepper", "Sam", "Alice", "Bob"}; !reflect.DeepEqual(info.Eliminated, want) {
+			t.Errorf("got eliminated %v, want %v", info.Eliminated, want)
+		}
+		if want := []poker.TableMove{
+			{"Alice", "1", "2"}, {"Sam", "1", "3"},
+			{"Bob", "2", poker.FinalTableID}, {"Alice", "2", poker.FinalTableID}, {"Chris", "3", poker.FinalTableID},
+		}; !reflect.DeepEqual(info.Moves, want) {
+			t.Errorf("got moves %v, want %v", info.Moves, want)
+		}
+		if got := info.Stacks["Chris"]; got != 700 {
+			t.Errorf("expected the winner to have every chip, got %d", got)
+		}
+
+		for name, want := range map[string]int{"Chris": 39, "Bob": 11, "Sam": -10} {
+			if got, _ := store.GetProfit(name); got != want {
+				t.Errorf("got profit %d for %s, want %d", got, name, want)
+			}
+		}
+		if _, err := tournament.PlayTable(poker.FinalTableID, keeping("Chris"), out); err == nil {
+			t.Error("expected an error playing on once the tournament is over")
+		}
+	})
+
+	t.Run("every table plays at the blind the tournament is at", func(t *testing.T) {
+		clock := poker.NewVirtualClock(time.Now())
+		game := poker.NewGame(poker.GetInMemoryStore(), poker.ClockAlerter(clock), poker.GetInMemoryGameStore(), dummyStructures, clock)
+		tournament, _ := poker.NewTournament("sunday", tournamentPlayers, 3, 0, "", poker.BuyIn{}, game)
+
+		clock.Advance(0)
+		if got := tournament.Info().Blind; got != "Blind is now 100" {
+			t.Errorf("got blind %q, want %q", got, "Blind is now 100")
+		}
+
+		standard, _ := dummyStructures.Get("", len(tournamentPlayers))
+		clock.Advance(standard.Levels[0].Duration())
+		for _, table := range []string{"1", "2"} {
+			out := &bytes.Buffer{}
+			result, _ := tournament.PlayTable(table, keeping("nobody"), out)
+			if result.Pot != standard.Levels[1].Blind*3/2 {
+				t.Errorf("expected the blinds of level 2 at table %s, got %q", table, out.String())
+			}
+		}
+	})
+
+	t.Run("nobody bets more than their stack and the chips in play never change", func(t *testing.T) {
+		game := poker.NewGame(poker.GetInMemoryStore(), dummySpyAlerter, poker.GetInMemoryGameStore(), dummyStructures, poker.RealClock{})
+		tournament, _ := poker.NewTournament("sunday", tournamentPlayers, 3, 500, "", poker.BuyIn{}, game)
+
+		// raises far beyond anybody's stack, calls them or folds at random
+		rnd := rand.New(rand.NewSource(1))
+		reckless := poker.ActorFunc(func(view poker.TableView) poker.Action {
+			switch rnd.Intn(4) {
+			case 0:
+				return poker.Action{Kind: poker.Fold}
+			case 1:
+				return passiveActor(view)
+			case 2:
+				return poker.Action{Kind: poker.AllIn}
+			}
+			return poker.Action{Kind: poker.Raise, Amount: 5000}
+		})
+
+		total := len(tournamentPlayers) * 500
+		for hands := 0; !tournament.Info().Finished; hands++ {
+			if hands == 1000 {
+				t.Fatalf("expected the tournament to be over, got %+v", tournament.Info())
+			}
+			tables := tournament.Info().Tables
+			table := tables[hands%len(tables)]
+			if len(table.Players) < poker.MinPlayersPerHand {
+				continue
+			}
+			_, err := tournament.PlayTable(table.ID, reckless, &bytes.Buffer{})
+			assertNoError(t, err)
+
+			chips := 0
+			for p, stack := range tournament.Info().Stacks {
+				if stack < 0 {
+					t.Fatalf("%s is left with %d chips", p, stack)
+				}
+				chips += stack
+			}
+			if chips != total {
+				t.Fatalf("got %d chips in play after hand %d, want %d", chips, hands, total)
+			}
+		}
+	})
+
+	t.Run("it refuses to start without enough players", func(t *testing.T) {
+		if _, err := poker.NewTournament("sunday", []string{"Chris"}, 3, 0, "", poker.BuyIn{}, dummyGame); err == nil {
+			t.Error("expected an error starting a tournament for one player")
+		}
+	})
+
+	t.Run("a tournament called off deals no more hands", func(t *testing.T) {
+		game := poker.NewGame(poker.GetInMemoryStore(), dummySpyAlerter, poker.GetInMemoryGameStore(), dummyStructures, poker.RealClock{})
+		tournament, _ := poker.NewTournament("sunday", tournamentPlayers, 3, 0, "", poker.BuyIn{}, game)
+		tournament.Cancel()
+
+		if _, err := tournament.PlayTable("1", keeping("Alice"), &bytes.Buffer{}); err == nil {
+			t.Error("expected no more hands to be dealt once the tournament was called off")
+		}
+	})
+
+	t.Run("a tournament whose game fails to start is never recorded", func(t *testing.T) {
+		games := poker.GetInMemoryGameStore()
+		game := poker.NewGame(poker.GetInMemoryStore(), dummySpyAlerter, games, dummyStructures, poker.RealClock{})
+		if _, err := poker.NewTournament("sunday", tournamentPlayers, 3, 0, "glacial", poker.BuyIn{}, game); err == nil {
+			t.Error("expected an error starting a tournament with an unknown structure")
+		}
+		if records, _ := games.GetGames(); len(records) != 0 {
+			t.Errorf("expected no game recorded, got %+v", records)
+		}
+	})
+}
+
+func TestTournamentsAPI(t *testing.T) {
+	store := poker.GetInMemoryStore()
+	games := poker.GetInMemoryGameStore()
+	registry := poker.NewTableRegistry(func() poker.Game {
+		return &riggedGame{
+			Game:    poker.NewGame(store, dummySpyAlerter, games, dummyStructures, poker.RealClock{}),
+			ranking: []string{"Chris", "Cleo", "Pepper", "Ruth"},
+		}
+	})
+	server, _ := poker.NewPlayerServer(store, dummyGame, registry, games, dummyStructures)
+
+	t.Run("start a tournament and deal a hand at one of its tables", func(t *testing.T) {
+		res := serveRequest(server, http.MethodPost, "/tournaments", `{"ID": "sunday", "Players": ["Chris", "Cleo", "Pepper", "Ruth"], "Seats": 2, "Stack": 100}`)
+		assertStatus(t, res, http.StatusCreated)
+
+		httpServer := httptest.NewServer(server)
+		defer httpServer.Close()
+		ws := mustDialWS(t, "ws"+strings.TrimPrefix(httpServer.URL, "http")+"/tournaments/sunday/tables/1/ws")
+		defer ws.Close()
+
+		// heads up the small blind acts first, Pepper is all-in for the big blind and loses the showdown
+		writeWSMessage(t, ws, "call")
+
+		within(t, time.Second, func() {
+			for {
+				_, msg, err := ws.ReadMessage()
+				if err != nil || strings.Contains(string(msg), "Pepper is knocked out in place 4") {
+					return
+				}
+			}
+		})
+
+		var info poker.TournamentInfo
+		json.NewDecoder(serveRequest(server, http.MethodGet, "/tournaments/sunday", "").Body).Decode(&info)
+		if info.Remaining != 3 || !reflect.DeepEqual(info.Eliminated, []string{"Pepper"}) {
+			t.Errorf("expected Pepper to be out, got %+v", info)
+		}
+	})
+
+	t.Run("the seat assignments can be viewed", func(t *testing.T) {
+		res := serveRequest(server, http.MethodGet, "/tournaments/sunday/view", "")
+		assertStatus(t, res, http.StatusOK)
+		if !strings.Contains(res.Body.String(), "Table moves") {
+			t.Errorf("expected the tournament page, got %q", res.Body.String())
+		}
+	})
+
+	t.Run("bad tournaments are rejected and unknown ones are not found", func(t *testing.T) {
+		assertStatus(t, serveRequest(server, http.MethodPost, "/tournaments", `{"ID": "monday", "Players": ["Chris"]}`), http.StatusConflict)
+		assertStatus(t, serveRequest(server, http.MethodPost, "/tournaments", `{"ID": "monday", "Players": ["Chris", "Cleo"], "BuyIn": "lots"}`), http.StatusBadRequest)
+		assertStatus(t, serveRequest(server, http.MethodGet, "/tournaments/monday", ""), http.StatusNotFound)
+	})
+
+	t.Run("a tournament can be called off", func(t *testing.T) {
+		httpServer := httptest.NewServer(server)
+		defer httpServer.Close()
+		spectator := mustDialWS(t, "ws"+strings.TrimPrefix(httpServer.URL, "http")+"/tournaments/sunday/spectate")
+		defer spectator.Close()
+		var state poker.GameEvent
+		if err := spectator.ReadJSON(&state); err != nil || state.Type != poker.GameStateEvent {
+			t.Fatalf("expected the state of the tournament, got %+v %v", state, err)
+		}
+
+		assertStatus(t, serveRequest(server, http.MethodDelete, "/tournaments/sunday", ""), http.StatusNoContent)
+		assertStatus(t, serveRequest(server, http.MethodGet, "/tournaments/sunday", ""), http.StatusNotFound)
+
+		assertEventReceived(t, spectator, poker.GameEvent{Type: poker.GameAbortedEvent})
+		var records []poker.GameRecord
+		json.NewDecoder(serveRequest(server, http.MethodGet, "/games", "").Body).Decode(&records)
+		if len(records) != 1 || !records[0].Aborted || records[0].FinishedAt == nil {
+			t.Errorf("expected the tournament to be recorded as aborted, got %+v", records)
+		}
+	})
+}
+
+func mustPlayTable(t *testing.T, tournament *poker.Tournament, table, winner string, out *bytes.Buffer) {
+	t.Helper()
+	result, err := tournament.PlayTable(table, keeping(winner), out)
+	assertNoError(t, err)
+	if !reflect.DeepEqual(result.Winners, []string{winner}) {
+		t.Fatalf("expected %s to win at table %s, got %v", winner, table, result.Winners)
+	}
+}
+
+func assertTournamentTables(t *testing.T, tournament *poker.Tournament, want map[string][]string) {
+	t.Helper()
+	got := map[string][]string{}
+	for _, table := range tournament.Info().Tables {
+		got[table.ID] = table.Players
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got tables %v, want %v", got, want)
+	}
+}